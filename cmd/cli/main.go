@@ -0,0 +1,157 @@
+// Command cli runs maintenance tasks against the application database.
+//
+//	cli import -author=<email> [-dry-run] [-conflict=skip|rename] <path>
+//	cli export [-out=posts.zip]
+package main
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/infrastructure/config"
+	"app05/internal/infrastructure/i18n"
+	"app05/internal/infrastructure/logger"
+	"app05/internal/infrastructure/postio"
+	"app05/internal/infrastructure/services"
+	"app05/internal/infrastructure/storage"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/joho/godotenv"
+	"os"
+	"strings"
+)
+
+const usage = `Usage:
+  cli import -author=<email> [-dry-run] [-conflict=skip|rename] <path>
+        Import posts from a Markdown file, a WordPress WXR export, a zip of them or a directory
+  cli export [-out=posts.zip]
+        Export all posts as a zip of Markdown files with front matter
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		fmt.Println("Error: Unable to load .env file,continuing without it")
+	}
+
+	cfg := config.LoadConfig()
+	myLogger := logger.NewZapLogger()
+	defer func(logger contracts.Logger) {
+		_ = logger.Sync()
+	}(myLogger)
+
+	dbConn, err := config.InitDB(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer dbConn.Close()
+
+	locales, err := i18n.NewLocales(cfg.Site.Language, cfg.Site.Locales)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	store := storage.NewStorage(dbConn)
+	transferService := services.NewPostTransferService(store.Post, store.Taxonomy, locales, myLogger)
+
+	ctx := context.Background()
+	switch os.Args[1] {
+	case "import":
+		err = runImport(ctx, store, transferService, os.Args[2:])
+	case "export":
+		err = runExport(ctx, transferService, os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func runImport(ctx context.Context, store storage.Storage, transferService *services.PostTransferService, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	authorEmail := flags.String("author", "", "email of the user the imported posts belong to")
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without writing anything")
+	onConflict := flags.String("conflict", postDTOs.ImportConflictSkip, "what to do with posts whose slug is taken: skip or rename")
+	_ = flags.Parse(args)
+
+	if *authorEmail == "" || flags.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	author, err := store.User.GetUserByEmail(ctx, *authorEmail)
+	if err != nil {
+		return fmt.Errorf("author %s: %w", *authorEmail, err)
+	}
+
+	loaded, err := postio.LoadPath(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	report, err := transferService.Import(ctx, loaded, author.ID, postDTOs.ImportOptions{
+		DryRun:     *dryRun,
+		OnConflict: *onConflict,
+	})
+	if err != nil {
+		return err
+	}
+
+	printReport(report)
+	return nil
+}
+
+func runExport(ctx context.Context, transferService *services.PostTransferService, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	out := flags.String("out", "posts.zip", "path of the zip archive to write")
+	_ = flags.Parse(args)
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+
+	if err := transferService.Export(ctx, file); err != nil {
+		file.Close()
+		os.Remove(*out)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Println("Posts exported to", *out)
+	return nil
+}
+
+func printReport(report *postDTOs.ImportReportDTO) {
+	for _, item := range report.Items {
+		fmt.Printf("%-6s %s", strings.ToUpper(item.Action), item.Source)
+		if item.Slug != "" {
+			fmt.Printf(" -> %s", item.Slug)
+		}
+		fmt.Println()
+		for _, message := range item.Errors {
+			fmt.Println("       error:", message)
+		}
+		for _, message := range item.Warnings {
+			fmt.Println("       warning:", message)
+		}
+	}
+
+	verb := "Imported"
+	if report.DryRun {
+		verb = "Dry run: would import"
+	}
+	fmt.Printf("\n%s %d of %d posts, %d skipped\n", verb, report.Created, report.Total, report.Skipped)
+}
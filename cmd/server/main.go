@@ -4,10 +4,16 @@ import (
 	middlewares "app05/internal/api/middleware"
 	"app05/internal/api/routes"
 	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/blob"
 	"app05/internal/infrastructure/cache"
 	"app05/internal/infrastructure/config"
+	"app05/internal/infrastructure/i18n"
 	"app05/internal/infrastructure/logger"
+	"app05/internal/infrastructure/markdown"
 	"app05/internal/infrastructure/rate_limiter"
+	"app05/internal/infrastructure/scanner"
 	"app05/internal/infrastructure/scheduler"
 	"app05/internal/infrastructure/scheduler/jobs"
 	"app05/internal/infrastructure/services"
@@ -75,17 +81,91 @@ func main() {
 		"url", cfg.Redis.URL,
 		"db", cfg.Redis.DB)
 
+	viewCounter := cache.NewViewCounter(redisCache.Client(), myLogger, cfg.Views.DedupWindow)
+	renderCache := cache.NewRenderCache(redisCache.Client(), cfg.Content.RenderCacheTTL)
+	feedCache := cache.NewFeedCache(redisCache.Client(), cfg.Feeds.CacheTTL)
+	reactionCounter := cache.NewReactionCounter(redisCache.Client(), cfg.Reactions.CacheTTL)
+	relatedCache := cache.NewRelatedCache(redisCache.Client(), cfg.Related.CacheTTL)
+	coViewTracker := cache.NewCoViewTracker(redisCache.Client(), cfg.Related.CoViewWindow, cfg.Related.CoViewRetention)
+	trendingStore := cache.NewTrendingStore(redisCache.Client(), cfg.Trending.HalfLife)
+	uploadStore := cache.NewUploadStore(redisCache.Client(), cfg.Uploads.Expiry)
+
+	locales, err := i18n.NewLocales(cfg.Site.Language, cfg.Site.Locales)
+	if err != nil {
+		myLogger.Fatal("Invalid site locales", "error", err)
+	}
+
 	// STORAGE INITIALIZATION
 	store := storage.NewStorage(dbConn)
 
+	blobStore, err := blob.New(context.Background(), cfg.Blob)
+	if err != nil {
+		myLogger.Fatal("Failed to initialize blob storage", "driver", cfg.Blob.Driver, "error", err)
+	}
+	malwareScanner, err := scanner.New(cfg.Scanner)
+	if err != nil {
+		myLogger.Fatal("Failed to initialize malware scanner", "driver", cfg.Scanner.Driver, "error", err)
+	}
+	if clamd, ok := malwareScanner.(*scanner.ClamdScanner); ok {
+		// Uploads stay quarantined until the daemon is reachable, so this is not fatal
+		if err := clamd.Ping(context.Background()); err != nil {
+			myLogger.Warn("ClamAV daemon is not reachable", "address", cfg.Scanner.Clamd.Address, "error", err)
+		}
+	} else {
+		myLogger.Warn("Malware scanning is disabled, uploads are accepted unscanned")
+	}
+	blobLinks := blob.NewLinks(blobStore, cfg.Blob.PublicURL, cfg.Blob.PublicPrefixes, cfg.Blob.SignedURLExpiry)
+
 	// REGISTER SERVICES
 	authService := services.NewAuthService(store.User, store.Session, redisCache, myLogger)
-	userService := services.NewUserService(store.User, myLogger)
+	imageVariantService := services.NewImageVariantService(store.Variant, blobStore, blobLinks, cfg.Media.VariantWorkers, myLogger)
+	userService := services.NewUserService(store.User, blobStore, imageVariantService, cfg.Blob.PublicURL, myLogger)
 	serverService := services.NewServerStatusService(cfg.AppVersion, cfg.Env)
-	postService := services.NewPostService(store.Post)
+	contentRenderer := services.NewContentRenderer(markdown.NewRenderer(), renderCache, myLogger)
+	trendingWeights := postDTOs.TrendingWeights{
+		View:     cfg.Trending.ViewWeight,
+		Reaction: cfg.Trending.ReactionWeight,
+		Comment:  cfg.Trending.CommentWeight,
+	}
+	trendingTracker := services.NewTrendingTracker(trendingStore, trendingWeights, myLogger)
+	engagementService := services.NewEngagementService(store.Reaction, store.Bookmark, store.Post, reactionCounter, contentRenderer, trendingTracker, myLogger)
+	mediaQuotas := map[entities.Role]int64{
+		entities.RoleInstructor: cfg.Media.InstructorQuota,
+		entities.RoleAdmin:      cfg.Media.AdminQuota,
+		entities.RoleSuperUser:  cfg.Media.SuperUserQuota,
+	}
+	mediaScanService := services.NewMediaScanService(store.Media, blobStore, malwareScanner, imageVariantService, cfg.Scanner.Workers, myLogger)
+	mediaService := services.NewMediaService(store.Media, blobStore, imageVariantService, mediaScanService, blobLinks, mediaQuotas, cfg.Media.MaxFileSize, myLogger)
+	uploadMaxSizes := map[entities.Role]int64{
+		entities.RoleInstructor: cfg.Uploads.InstructorMaxSize,
+		entities.RoleAdmin:      cfg.Uploads.AdminMaxSize,
+		entities.RoleSuperUser:  cfg.Uploads.SuperUserMaxSize,
+	}
+	uploadService := services.NewUploadService(uploadStore, blobStore, mediaService, uploadMaxSizes, myLogger)
+	seriesService := services.NewSeriesService(store.Series, store.Post, myLogger)
+	courseService := services.NewCourseService(store.Course, myLogger)
+	enrollmentService := services.NewEnrollmentService(store.Enrollment, store.Course, store.User, myLogger)
+	progressService := services.NewProgressService(store.Progress, store.Enrollment, store.Course, courseService, myLogger)
+	postAuthorService := services.NewPostAuthorService(store.Author, store.Post, store.User, myLogger)
+	relatedPostService := services.NewRelatedPostService(store.Post, contentRenderer, engagementService, relatedCache, coViewTracker, cfg.Related.Limit, myLogger)
+	postService := services.NewPostService(store.Post, contentRenderer, engagementService, seriesService, postAuthorService, relatedPostService, locales, trendingTracker, mediaService, viewCounter, myLogger)
+	taxonomyService := services.NewTaxonomyService(store.Taxonomy, store.Post, contentRenderer, engagementService, postAuthorService, relatedPostService, locales, myLogger)
+	trendingService := services.NewTrendingService(store.Post, store.Taxonomy, store.Trending, contentRenderer, engagementService, trendingStore, viewCounter, trendingWeights, cfg.Trending.Window, cfg.Trending.HalfLife, myLogger)
+	site := services.SiteInfo{
+		Name:        cfg.Site.Name,
+		Description: cfg.Site.Description,
+		Language:    locales.Default(),
+		URL:         cfg.Site.URL,
+		APIURL:      cfg.Site.APIURL,
+	}
+	postTransferService := services.NewPostTransferService(store.Post, store.Taxonomy, locales, myLogger)
+	seoService := services.NewSEOService(store.Post, contentRenderer, postAuthorService, site, myLogger)
+	feedService := services.NewFeedService(store.Post, store.Taxonomy, store.User, contentRenderer, feedCache, site, cfg.Feeds.ItemLimit, myLogger)
 
 	//RATE LIMITER
 	rL := rate_limiter.NewFixedWindowRateLimiter(cfg.RateLimiter.RequestPerTimeFrame, cfg.RateLimiter.TimeFrame)
+	commentLimiter := rate_limiter.NewFixedWindowRateLimiter(cfg.Comments.RequestPerTimeFrame, cfg.Comments.TimeFrame)
+	commentService := services.NewCommentService(store.Comment, store.Post, commentLimiter, trendingTracker, myLogger)
 
 	// INITIALIZE SCHEDULER
 	newScheduler := scheduler.NewScheduler(myLogger)
@@ -98,6 +178,24 @@ func main() {
 	)
 	newScheduler.AddJob(cleanupJob)
 
+	// Flush buffered post views to the database
+	newScheduler.AddJob(jobs.NewViewFlushJob(viewCounter, store.Post, myLogger, cfg.Views.FlushInterval))
+
+	// Correct cached reaction counts of changed posts
+	newScheduler.AddJob(jobs.NewReactionReconcileJob(reactionCounter, store.Reaction, myLogger, cfg.Reactions.ReconcileInterval))
+
+	// Rebuild trending leaderboards from the database once a night
+	newScheduler.AddJob(jobs.NewTrendingRebuildJob(trendingService, myLogger, cfg.Trending.RebuildHour))
+
+	// Hand images whose variants were not generated back to the workers
+	newScheduler.AddJob(jobs.NewImageVariantRequeueJob(imageVariantService, myLogger, time.Minute))
+
+	// Hand media whose malware scan did not finish back to the scanners
+	newScheduler.AddJob(jobs.NewMediaScanRequeueJob(mediaScanService, myLogger, time.Minute))
+
+	// Delete the chunks of resumable uploads that expired unfinished
+	newScheduler.AddJob(jobs.NewUploadCleanupJob(uploadService, myLogger, time.Hour))
+
 	// Create context for graceful shutdown
 	ctx, cancel = context.WithCancel(context.Background())
 	defer cancel()
@@ -105,35 +203,61 @@ func main() {
 	// Start newScheduler
 	fmt.Println("Starting scheduler...")
 	newScheduler.Start(ctx)
+	imageVariantService.Start(ctx)
+	mediaScanService.Start(ctx)
+
+	// Client addresses are only taken from forwarding headers set by trusted proxies
+	realIP, err := middlewares.RealIPMiddleware(cfg.TrustedProxies)
+	if err != nil {
+		myLogger.Fatal("Invalid trusted proxies", "error", err)
+	}
 
 	//INITIALIZE THE ROUTER AND REGISTER MIDDLEWARE STACK
 	router := chi.NewRouter()
 	router.Use(middleware.RequestID)
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
-	router.Use(middleware.RealIP)
+	router.Use(realIP)
 	// CORS middlewares
 	router.Use(cors.Handler(cors.Options{
 		AllowedOrigins: []string{"http://localhost:3000"}, // Use this to allow specific origin hosts
 		//AllowedOrigins: []string{"https://*", "http://*"},
 		// AllowOriginFunc:  func(r *http.Request, origin string) bool { return true },
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Tus-Resumable", "Upload-Length", "Upload-Metadata", "Upload-Offset"},
+		ExposedHeaders:   []string{"Link", "Location", "Tus-Resumable", "Tus-Version", "Tus-Extension", "Tus-Max-Size", "Upload-Offset", "Upload-Length", "Upload-Expires", "Media-ID"},
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
 	router.Use(middlewares.RateLimiterMiddleware(rL, cfg.RateLimiter.Enabled))
 
-	fileServer := http.FileServer(http.Dir("uploads"))
-	router.Handle("/uploads/*", http.StripPrefix("/uploads/", fileServer))
+	// Files of the local blob store are served from disk, S3 serves its own
+	if localStore, ok := blobStore.(*blob.LocalStore); ok {
+		routes.RegisterBlobRoutes(router, localStore, blobLinks, myLogger)
+	}
 
 	// ROUTES
+	routes.RegisterFeedRoutes(router, feedService, myLogger)
+	routes.RegisterSitemapRoutes(router, seoService, myLogger)
+
 	router.Route("/api/v1", func(r chi.Router) {
+		r.Use(middlewares.LocaleMiddleware(locales))
+
 		routes.RegisterServerStatusRoutes(r, serverService, myLogger)
 		routes.RegisterAuthRoutes(r, redisCache, authService, myLogger)
 		routes.RegisterUserRoutes(r, redisCache, userService, myLogger)
-		routes.RegisterPostRoutes(r, postService, myLogger)
+		routes.RegisterPostRoutes(r, redisCache, postService, myLogger)
+		routes.RegisterTaxonomyRoutes(r, redisCache, taxonomyService, myLogger)
+		routes.RegisterCommentRoutes(r, redisCache, commentService, myLogger)
+		routes.RegisterSEORoutes(r, redisCache, seoService, myLogger)
+		routes.RegisterPostTransferRoutes(r, redisCache, postTransferService, myLogger)
+		routes.RegisterEngagementRoutes(r, redisCache, engagementService, myLogger)
+		routes.RegisterSeriesRoutes(r, redisCache, seriesService, myLogger)
+		routes.RegisterCourseRoutes(r, redisCache, courseService, enrollmentService, progressService, myLogger)
+		routes.RegisterPostAuthorRoutes(r, redisCache, postAuthorService, myLogger)
+		routes.RegisterRelatedPostRoutes(r, redisCache, relatedPostService, myLogger)
+		routes.RegisterTrendingRoutes(r, redisCache, trendingService, myLogger)
+		routes.RegisterMediaRoutes(r, redisCache, mediaService, uploadService, cfg.Uploads.RequestTimeout, myLogger)
 
 	})
 
@@ -143,7 +267,7 @@ func main() {
 		appErrors.HandleError(w, Error, myLogger)
 	})
 
-	// Server
+	// Server. Upload routes extend the read and write deadlines per request.
 	server := &http.Server{
 		Addr:         cfg.ServerPort,
 		Handler:      router,
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/blob"
+	"app05/pkg/appErrors"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// BlobHandler serves the public files and presigned URLs of the local blob store
+type BlobHandler struct {
+	store  *blob.LocalStore
+	links  *blob.Links
+	logger contracts.Logger
+}
+
+func NewBlobHandler(store *blob.LocalStore, links *blob.Links, logger contracts.Logger) *BlobHandler {
+	return &BlobHandler{
+		store:  store,
+		links:  links,
+		logger: logger,
+	}
+}
+
+// GetPublicBlob handles GET /uploads/{key}. Only public files are served, private
+// files and directories are reported as not found.
+func (h *BlobHandler) GetPublicBlob(w http.ResponseWriter, r *http.Request) {
+	key := blobKey(r)
+	if !h.links.IsPublic(key) {
+		appErrors.HandleError(w, appErrors.New(appErrors.CodeNotFound, "file not found"), h.logger)
+		return
+	}
+
+	h.serveBlob(w, r, key, "public, max-age=86400")
+}
+
+// GetBlob handles GET /blobs/{key}?expires=&signature=
+func (h *BlobHandler) GetBlob(w http.ResponseWriter, r *http.Request) {
+	key := blobKey(r)
+	if err := h.store.Verify(key, r.URL.Query()); err != nil {
+		appErrors.HandleError(w, blobError(err), h.logger)
+		return
+	}
+
+	h.serveBlob(w, r, key, "private, no-store")
+}
+
+func (h *BlobHandler) serveBlob(w http.ResponseWriter, r *http.Request, key, cacheControl string) {
+	body, info, err := h.store.Get(r.Context(), key)
+	if err != nil {
+		appErrors.HandleError(w, blobError(err), h.logger)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", info.ContentType)
+	w.Header().Set("Cache-Control", cacheControl)
+	// Uploaded files are served as their extension says, never sniffed into something else
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, "", info.ModTime, seeker)
+		return
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		h.logger.Warn("Failed to send blob", "key", key, "error", err)
+	}
+}
+
+// blobKey returns the key in the request path. The router matches on the escaped
+// path when the URL has one, leaving the key escaped.
+func blobKey(r *http.Request) string {
+	key := chi.URLParam(r, "*")
+	if r.URL.RawPath != "" {
+		if unescaped, err := url.PathUnescape(key); err == nil {
+			return unescaped
+		}
+	}
+	return key
+}
+
+// blobError maps blob store errors to API errors
+func blobError(err error) error {
+	switch {
+	case errors.Is(err, blob.ErrInvalidSignature):
+		return appErrors.New(appErrors.CodeForbidden, err.Error())
+	case errors.Is(err, blob.ErrInvalidKey):
+		return appErrors.New(appErrors.CodeBadRequest, err.Error())
+	case errors.Is(err, contracts.ErrBlobNotFound):
+		return appErrors.New(appErrors.CodeNotFound, "file not found")
+	}
+	return err
+}
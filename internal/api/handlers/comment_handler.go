@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/commentDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+)
+
+type CommentHandler struct {
+	commentService *services.CommentService
+	validator      *validator.Validate
+	logger         contracts.Logger
+}
+
+func NewCommentHandler(commentService *services.CommentService, logger contracts.Logger) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+		validator:      validator.New(),
+		logger:         logger,
+	}
+}
+
+// GetPostComments returns approved comment threads for a post
+func (h *CommentHandler) GetPostComments(w http.ResponseWriter, r *http.Request) {
+	userID, role, _ := currentUser(r)
+	page, perPage := utils.ParsePagination(r)
+	comments, total, err := h.commentService.GetPostComments(r.Context(), chi.URLParam(r, "slug"), userID, role, page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, comments, page, perPage, total)
+}
+
+func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input commentDTOs.CreateCommentRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(r.Context(), chi.URLParam(r, "slug"), userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, comment)
+}
+
+func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input commentDTOs.UpdateCommentRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(r.Context(), id, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, comment)
+}
+
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if err := h.commentService.DeleteComment(r.Context(), id, userID, role); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, map[string]string{"message": "Comment has been deleted"})
+}
+
+// GetModerationQueue lists comments by moderation status, pending by default
+func (h *CommentHandler) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	status := entities.CommentStatus(r.URL.Query().Get("status"))
+	if status == "" {
+		status = entities.CommentStatusPending
+	}
+
+	page, perPage := utils.ParsePagination(r)
+	comments, total, err := h.commentService.GetModerationQueue(r.Context(), status, page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, comments, page, perPage, total)
+}
+
+func (h *CommentHandler) ModerateComment(w http.ResponseWriter, r *http.Request) {
+	moderatorID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input commentDTOs.ModerateCommentRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	comment, err := h.commentService.ModerateComment(r.Context(), id, moderatorID, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, comment)
+}
@@ -0,0 +1,567 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/courseDTOs"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+)
+
+type CourseHandler struct {
+	courseService *services.CourseService
+	validator     *validator.Validate
+	logger        contracts.Logger
+}
+
+func NewCourseHandler(courseService *services.CourseService, logger contracts.Logger) *CourseHandler {
+	return &CourseHandler{
+		courseService: courseService,
+		validator:     validator.New(),
+		logger:        logger,
+	}
+}
+
+// GetCourses returns a page of published courses
+func (h *CourseHandler) GetCourses(w http.ResponseWriter, r *http.Request) {
+	page, perPage := utils.ParsePagination(r)
+
+	courses, total, err := h.courseService.GetPublishedCourses(r.Context(), page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, courses, page, perPage, total)
+}
+
+// GetCourse is the landing page of a course, outlining its published modules and lessons
+func (h *CourseHandler) GetCourse(w http.ResponseWriter, r *http.Request) {
+	course, err := h.courseService.GetCourseBySlug(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, course)
+}
+
+// GetLesson returns a published lesson with its content. Access is checked by EnrollmentMiddleware.
+func (h *CourseHandler) GetLesson(w http.ResponseWriter, r *http.Request) {
+	lessonID, err := intURLParam(r, "lessonId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	lesson, err := h.courseService.GetPublishedLesson(r.Context(), chi.URLParam(r, "slug"), lessonID)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, lesson)
+}
+
+// GetManagedCourses returns a page of the current user's courses, drafts included
+func (h *CourseHandler) GetManagedCourses(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	page, perPage := utils.ParsePagination(r)
+
+	courses, total, err := h.courseService.GetManagedCourses(r.Context(), userID, page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, courses, page, perPage, total)
+}
+
+// GetManagedCourse returns a course with its draft modules and lessons for its owner
+func (h *CourseHandler) GetManagedCourse(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	course, err := h.courseService.GetManagedCourse(r.Context(), id, userID, role)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, course)
+}
+
+func (h *CourseHandler) CreateCourse(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input courseDTOs.CreateCourseRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	course, err := h.courseService.CreateCourse(r.Context(), userID, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, course)
+}
+
+func (h *CourseHandler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input courseDTOs.UpdateCourseRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	course, err := h.courseService.UpdateCourse(r.Context(), id, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, course)
+}
+
+// UpdateEnrollmentSettings sets who may enroll in a course and when
+func (h *CourseHandler) UpdateEnrollmentSettings(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input courseDTOs.UpdateEnrollmentSettingsRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	course, err := h.courseService.UpdateEnrollmentSettings(r.Context(), id, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, course)
+}
+
+func (h *CourseHandler) DeleteCourse(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if err := h.courseService.DeleteCourse(r.Context(), id, userID, role); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, map[string]string{"message": "Course deleted successfully"})
+}
+
+func (h *CourseHandler) CreateModule(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input courseDTOs.CreateModuleRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	module, err := h.courseService.CreateModule(r.Context(), id, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, module)
+}
+
+func (h *CourseHandler) UpdateModule(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	moduleID, err := intURLParam(r, "moduleId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input courseDTOs.UpdateModuleRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	module, err := h.courseService.UpdateModule(r.Context(), id, moduleID, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, module)
+}
+
+func (h *CourseHandler) DeleteModule(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	moduleID, err := intURLParam(r, "moduleId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if err := h.courseService.DeleteModule(r.Context(), id, moduleID, userID, role); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, map[string]string{"message": "Module deleted successfully"})
+}
+
+// ReorderModules saves a new order for all modules of a course at once
+func (h *CourseHandler) ReorderModules(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input courseDTOs.ReorderModulesRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	course, err := h.courseService.ReorderModules(r.Context(), id, userID, role, input.ModuleIDs)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, course)
+}
+
+func (h *CourseHandler) CreateLesson(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	moduleID, err := intURLParam(r, "moduleId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input courseDTOs.CreateLessonRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	lesson, err := h.courseService.CreateLesson(r.Context(), id, moduleID, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, lesson)
+}
+
+// GetManagedLesson returns a lesson with its content, drafts included, for the course owner
+func (h *CourseHandler) GetManagedLesson(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	moduleID, err := intURLParam(r, "moduleId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	lessonID, err := intURLParam(r, "lessonId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	lesson, err := h.courseService.GetManagedLesson(r.Context(), id, moduleID, lessonID, userID, role)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, lesson)
+}
+
+func (h *CourseHandler) UpdateLesson(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	moduleID, err := intURLParam(r, "moduleId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	lessonID, err := intURLParam(r, "lessonId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input courseDTOs.UpdateLessonRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	lesson, err := h.courseService.UpdateLesson(r.Context(), id, moduleID, lessonID, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, lesson)
+}
+
+func (h *CourseHandler) DeleteLesson(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	moduleID, err := intURLParam(r, "moduleId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	lessonID, err := intURLParam(r, "lessonId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if err := h.courseService.DeleteLesson(r.Context(), id, moduleID, lessonID, userID, role); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, map[string]string{"message": "Lesson deleted successfully"})
+}
+
+// ReorderLessons saves a new order for all lessons of a module at once
+func (h *CourseHandler) ReorderLessons(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	moduleID, err := intURLParam(r, "moduleId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input courseDTOs.ReorderLessonsRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	course, err := h.courseService.ReorderLessons(r.Context(), id, moduleID, userID, role, input.LessonIDs)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, course)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+)
+
+type EngagementHandler struct {
+	engagementService *services.EngagementService
+	logger            contracts.Logger
+}
+
+func NewEngagementHandler(engagementService *services.EngagementService, logger contracts.Logger) *EngagementHandler {
+	return &EngagementHandler{
+		engagementService: engagementService,
+		logger:            logger,
+	}
+}
+
+// AddReaction handles PUT /posts/{slug}/reactions/{reaction}
+func (h *EngagementHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	reaction := entities.ReactionType(chi.URLParam(r, "reaction"))
+	summary, err := h.engagementService.AddReaction(r.Context(), chi.URLParam(r, "slug"), userID, role, reaction)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, summary)
+}
+
+// RemoveReaction handles DELETE /posts/{slug}/reactions/{reaction}
+func (h *EngagementHandler) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	reaction := entities.ReactionType(chi.URLParam(r, "reaction"))
+	summary, err := h.engagementService.RemoveReaction(r.Context(), chi.URLParam(r, "slug"), userID, role, reaction)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, summary)
+}
+
+func (h *EngagementHandler) AddBookmark(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if err := h.engagementService.AddBookmark(r.Context(), chi.URLParam(r, "slug"), userID, role); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, map[string]string{"message": "Post added to your reading list"})
+}
+
+func (h *EngagementHandler) RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if err := h.engagementService.RemoveBookmark(r.Context(), chi.URLParam(r, "slug"), userID); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, map[string]string{"message": "Post removed from your reading list"})
+}
+
+// GetMyBookmarks returns the signed-in user's reading list
+func (h *EngagementHandler) GetMyBookmarks(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	page, perPage := utils.ParsePagination(r)
+	bookmarks, total, err := h.engagementService.GetBookmarks(r.Context(), userID, role, page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, bookmarks, page, perPage, total)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/courseDTOs"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+)
+
+type EnrollmentHandler struct {
+	enrollmentService *services.EnrollmentService
+	validator         *validator.Validate
+	logger            contracts.Logger
+}
+
+func NewEnrollmentHandler(enrollmentService *services.EnrollmentService, logger contracts.Logger) *EnrollmentHandler {
+	return &EnrollmentHandler{
+		enrollmentService: enrollmentService,
+		validator:         validator.New(),
+		logger:            logger,
+	}
+}
+
+// Enroll handles POST /courses/{slug}/enrollment, enrolling the current user in a free course
+func (h *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	enrollment, err := h.enrollmentService.Enroll(r.Context(), chi.URLParam(r, "slug"), userID)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, enrollment)
+}
+
+// Unenroll handles DELETE /courses/{slug}/enrollment
+func (h *EnrollmentHandler) Unenroll(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if err := h.enrollmentService.Unenroll(r.Context(), chi.URLParam(r, "slug"), userID); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, map[string]string{"message": "Unenrolled successfully"})
+}
+
+// GetMyCourses returns a page of the courses the current user is enrolled in
+func (h *EnrollmentHandler) GetMyCourses(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	page, perPage := utils.ParsePagination(r)
+
+	enrollments, total, err := h.enrollmentService.GetMyCourses(r.Context(), userID, page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, enrollments, page, perPage, total)
+}
+
+// GetCourseEnrollments returns a page of the students of a course for its owner
+func (h *EnrollmentHandler) GetCourseEnrollments(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	page, perPage := utils.ParsePagination(r)
+
+	enrollments, total, err := h.enrollmentService.GetCourseEnrollments(r.Context(), id, userID, role, page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, enrollments, page, perPage, total)
+}
+
+// EnrollUser handles POST /courses/{id}/enrollments, enrolling a user on behalf of the course owner
+func (h *EnrollmentHandler) EnrollUser(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input courseDTOs.EnrollUserRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	enrollment, err := h.enrollmentService.EnrollUser(r.Context(), id, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, enrollment)
+}
+
+// RemoveEnrollment handles DELETE /courses/{id}/enrollments/{userId}
+func (h *EnrollmentHandler) RemoveEnrollment(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	studentID, err := utils.ParseUUID(chi.URLParam(r, "userId"))
+	if err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, "invalid userId parameter")
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if err := h.enrollmentService.RemoveEnrollment(r.Context(), id, studentID, userID, role); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, map[string]string{"message": "Enrollment removed successfully"})
+}
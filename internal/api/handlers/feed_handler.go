@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/infrastructure/feeds"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"bytes"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// feedMaxAge is how long clients and proxies may reuse a feed without revalidating
+const feedMaxAge = 5 * time.Minute
+
+type FeedHandler struct {
+	feedService *services.FeedService
+	logger      contracts.Logger
+}
+
+func NewFeedHandler(feedService *services.FeedService, logger contracts.Logger) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+		logger:      logger,
+	}
+}
+
+// GetPostsFeed handles GET /feeds/posts.{rss,atom,json}?tag=&category=&author=
+// and answers conditional requests with 304 Not Modified
+func (h *FeedHandler) GetPostsFeed(w http.ResponseWriter, r *http.Request) {
+	format, ok := feeds.ParseFormat(chi.URLParam(r, "format"))
+	if !ok {
+		appErrors.HandleError(w, appErrors.New(appErrors.CodeNotFound, "feed not found"), h.logger)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := postDTOs.PublishedPostFilter{
+		TagSlug:      strings.TrimSpace(query.Get("tag")),
+		CategorySlug: strings.TrimSpace(query.Get("category")),
+	}
+	if author := strings.TrimSpace(query.Get("author")); author != "" {
+		authorID, err := utils.ParseUUID(author)
+		if err != nil {
+			appError := appErrors.New(appErrors.CodeBadRequest, "invalid author id")
+			appErrors.HandleError(w, appError, h.logger)
+			return
+		}
+		filter.AuthorID = &authorID
+	}
+
+	document, err := h.feedService.GetPostsFeed(r.Context(), format, filter)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("ETag", document.ETag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(feedMaxAge.Seconds())))
+
+	// ServeContent evaluates If-None-Match and If-Modified-Since against the headers above
+	http.ServeContent(w, r, "", document.LastModified, bytes.NewReader(document.Body))
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/mediaDTOs"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const mediaFormMemory = 8 << 20 // larger uploads are buffered on disk while parsing
+
+type MediaHandler struct {
+	mediaService *services.MediaService
+	validator    *validator.Validate
+	logger       contracts.Logger
+}
+
+func NewMediaHandler(mediaService *services.MediaService, logger contracts.Logger) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+		validator:    validator.New(),
+		logger:       logger,
+	}
+}
+
+// UploadMedia handles POST /media with the file in the multipart "file" field, and
+// optional "alt_text", comma-separated "tags" and "private" (true/false) fields
+func (h *MediaHandler) UploadMedia(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.mediaService.MaxFileSize()+multipartOverhead)
+	if err := r.ParseMultipartForm(mediaFormMemory); err != nil {
+		message := "invalid multipart form"
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			message = "file is too large"
+		}
+		appErrors.HandleError(w, appErrors.New(appErrors.CodeBadRequest, message), h.logger)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, "a file is required in the 'file' field")
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+	defer file.Close()
+
+	var input mediaDTOs.CreateMediaRequest
+	if altText := r.FormValue("alt_text"); altText != "" {
+		input.AltText = &altText
+	}
+	if tags := r.FormValue("tags"); tags != "" {
+		input.Tags = strings.Split(tags, ",")
+	}
+	if private := r.FormValue("private"); private != "" {
+		if input.Private, err = strconv.ParseBool(private); err != nil {
+			appErrors.HandleError(w, appErrors.New(appErrors.CodeBadRequest, "private must be true or false"), h.logger)
+			return
+		}
+	}
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	media, err := h.mediaService.UploadMedia(r.Context(), userID, role, file, header.Size, header.Filename, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, media)
+}
+
+// GetMyMedia handles GET /media?tag= and returns a page of the caller's files
+func (h *MediaHandler) GetMyMedia(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	page, perPage := utils.ParsePagination(r)
+	mediaList, total, err := h.mediaService.GetUserMedia(r.Context(), userID, r.URL.Query().Get("tag"), page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, mediaList, page, perPage, total)
+}
+
+// GetMediaUsage returns the caller's storage use and quota
+func (h *MediaHandler) GetMediaUsage(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	usage, err := h.mediaService.GetUsage(r.Context(), userID, role)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, usage)
+}
+
+// GetMedia returns a file with the posts that use it
+func (h *MediaHandler) GetMedia(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	media, err := h.mediaService.GetMedia(r.Context(), id, userID, role)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, media)
+}
+
+// UpdateMedia sets a file's alt text and tags
+func (h *MediaHandler) UpdateMedia(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input mediaDTOs.UpdateMediaRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	media, err := h.mediaService.UpdateMedia(r.Context(), id, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, media)
+}
+
+// DeleteMedia deletes a file, unless a post uses it
+func (h *MediaHandler) DeleteMedia(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if err := h.mediaService.DeleteMedia(r.Context(), id, userID, role); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, map[string]string{"message": "Media deleted successfully"})
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"app05/pkg/appErrors"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"strconv"
+)
+
+// intURLParam reads a positive integer route parameter
+func intURLParam(r *http.Request, name string) (int, error) {
+	value, err := strconv.Atoi(chi.URLParam(r, name))
+	if err != nil || value < 1 {
+		return 0, appErrors.New(appErrors.CodeBadRequest, "invalid "+name+" parameter")
+	}
+	return value, nil
+}
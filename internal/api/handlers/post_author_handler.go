@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+)
+
+type PostAuthorHandler struct {
+	authorService *services.PostAuthorService
+	validator     *validator.Validate
+	logger        contracts.Logger
+}
+
+func NewPostAuthorHandler(authorService *services.PostAuthorService, logger contracts.Logger) *PostAuthorHandler {
+	return &PostAuthorHandler{
+		authorService: authorService,
+		validator:     validator.New(),
+		logger:        logger,
+	}
+}
+
+// SetAuthor handles PUT /posts/{id}/authors/{userId}
+func (h *PostAuthorHandler) SetAuthor(w http.ResponseWriter, r *http.Request) {
+	postID, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	authorID, err := utils.ParseUUID(chi.URLParam(r, "userId"))
+	if err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, "invalid userId parameter")
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input postDTOs.SetPostAuthorRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	authors, err := h.authorService.SetAuthor(r.Context(), postID, userID, role, authorID, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, authors)
+}
+
+// RemoveAuthor handles DELETE /posts/{id}/authors/{userId}
+func (h *PostAuthorHandler) RemoveAuthor(w http.ResponseWriter, r *http.Request) {
+	postID, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	authorID, err := utils.ParseUUID(chi.URLParam(r, "userId"))
+	if err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, "invalid userId parameter")
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	authors, err := h.authorService.RemoveAuthor(r.Context(), postID, userID, role, authorID)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, authors)
+}
+
+// TransferPosts handles POST /admin/users/{id}/transfer-posts
+func (h *PostAuthorHandler) TransferPosts(w http.ResponseWriter, r *http.Request) {
+	fromUserID, err := utils.ParseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, "invalid id parameter")
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	var input postDTOs.TransferPostsRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	result, err := h.authorService.TransferPosts(r.Context(), fromUserID, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, result)
+}
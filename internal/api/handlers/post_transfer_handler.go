@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/infrastructure/postio"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	maxImportUploadSize = 50 << 20
+	importFormMemory    = 32 << 20
+)
+
+type PostTransferHandler struct {
+	transferService *services.PostTransferService
+	logger          contracts.Logger
+}
+
+func NewPostTransferHandler(transferService *services.PostTransferService, logger contracts.Logger) *PostTransferHandler {
+	return &PostTransferHandler{
+		transferService: transferService,
+		logger:          logger,
+	}
+}
+
+// ImportPosts handles POST /admin/posts/import?dry_run=&conflict= with a multipart "file"
+// holding a Markdown file, a WXR export or a zip of them. Imported posts belong to the caller.
+func (h *PostTransferHandler) ImportPosts(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	options := postDTOs.ImportOptions{OnConflict: r.URL.Query().Get("conflict")}
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		options.DryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			appError := appErrors.New(appErrors.CodeBadRequest, "dry_run must be true or false")
+			appErrors.HandleError(w, appError, h.logger)
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportUploadSize)
+	if err := r.ParseMultipartForm(importFormMemory); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, fmt.Sprintf("upload must be a multipart form of at most %d MB", maxImportUploadSize>>20))
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, "form field 'file' is required")
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		appErrors.HandleError(w, appErrors.Wrap(err, appErrors.CodeInternal), h.logger)
+		return
+	}
+
+	loaded, err := postio.LoadFile(header.Filename, data)
+	if err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	report, err := h.transferService.Import(r.Context(), loaded, userID, options)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, report)
+}
+
+// ExportPosts handles GET /admin/posts/export and downloads all posts as a zip of Markdown files
+func (h *PostTransferHandler) ExportPosts(w http.ResponseWriter, r *http.Request) {
+	// Build the archive first so a failure can still be reported as a JSON error
+	var buf bytes.Buffer
+	if err := h.transferService.Export(r.Context(), &buf); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	filename := fmt.Sprintf("posts-%s.zip", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		h.logger.Error("Failed to write posts export", "error", err)
+	}
+}
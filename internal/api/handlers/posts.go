@@ -1,29 +1,45 @@
 package handlers
 
 import (
+	"app05/internal/core/application/constants"
 	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
 	"app05/internal/infrastructure/services"
 	"app05/pkg/appErrors"
 	"app05/pkg/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"net"
 	"net/http"
+	"strings"
 )
 
+const maxSearchQueryLength = 200
+
 type PostHandler struct {
 	postService *services.PostService
+	validator   *validator.Validate
 	logger      contracts.Logger
 }
 
 func NewPostHandler(postService *services.PostService, logger contracts.Logger) *PostHandler {
 	return &PostHandler{
 		postService: postService,
+		validator:   validator.New(),
 		logger:      logger,
 	}
 }
 
 // GetAllPosts  is a function that returns all posts
 func (h *PostHandler) GetAllPosts(w http.ResponseWriter, r *http.Request) {
+	// Anonymous callers get public content only
+	userID, role, _ := currentUser(r)
+
 	// Get all posts from the posts service
-	posts, err := h.postService.GetAllPosts(r.Context())
+	posts, err := h.postService.GetAllPosts(r.Context(), requestLocale(r), userID, role)
 	if err != nil {
 		appError := appErrors.New(appErrors.CodeInternal, "Failed to retrieve posts")
 		appErrors.HandleError(w, appError, h.logger)
@@ -32,3 +48,233 @@ func (h *PostHandler) GetAllPosts(w http.ResponseWriter, r *http.Request) {
 	// Return the health response as a JSON response
 	utils.SendJSON(w, posts)
 }
+
+// GetPostBySlug returns a published post, in the reader's locale when translated, and counts the request as a view
+func (h *PostHandler) GetPostBySlug(w http.ResponseWriter, r *http.Request) {
+	// Anonymous readers have no user id and get the post without viewer state
+	userID, role, _ := currentUser(r)
+	post, err := h.postService.GetPostBySlug(r.Context(), chi.URLParam(r, "slug"), requestLocale(r), visitorID(r), userID, role)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Language", post.Locale)
+
+	utils.SendJSON(w, post)
+}
+
+// SearchPosts handles GET /posts/search?q= and returns ranked, highlighted matches
+func (h *PostHandler) SearchPosts(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		appError := appErrors.New(appErrors.CodeBadRequest, "search query parameter 'q' is required")
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+	if len(query) > maxSearchQueryLength {
+		appError := appErrors.New(appErrors.CodeBadRequest, "search query is too long")
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	userID, role, _ := currentUser(r)
+	page, perPage := utils.ParsePagination(r)
+	results, total, err := h.postService.SearchPosts(r.Context(), query, requestLocale(r), userID, role, page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, results, page, perPage, total)
+}
+
+// CreatePost creates a draft owned by the signed-in author
+func (h *PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input postDTOs.CreatePostRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	post, err := h.postService.CreatePost(r.Context(), userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, post)
+}
+
+func (h *PostHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input postDTOs.UpdatePostRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	post, err := h.postService.UpdatePost(r.Context(), id, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, post)
+}
+
+// UpdatePostStatus publishes, unpublishes or archives a post
+func (h *PostHandler) UpdatePostStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input postDTOs.UpdatePostStatusRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	post, err := h.postService.UpdatePostStatus(r.Context(), id, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, post)
+}
+
+// UpdatePostVisibility sets who may read a post
+func (h *PostHandler) UpdatePostVisibility(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input postDTOs.UpdatePostVisibilityRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	post, err := h.postService.UpdatePostVisibility(r.Context(), id, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, post)
+}
+
+// UpdatePostTranslation sets a post's locale and the post it translates
+func (h *PostHandler) UpdatePostTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input postDTOs.UpdatePostTranslationRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	post, err := h.postService.UpdatePostTranslation(r.Context(), id, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, post)
+}
+
+// visitorID identifies the caller for view de-duplication. Signed-in users are
+// identified by their user id, anonymous visitors by a hash of IP and user agent. The IP
+// is only taken from forwarding headers of trusted proxies, see RealIPMiddleware.
+func visitorID(r *http.Request) string {
+	if userID, ok := r.Context().Value(constants.UserIdCtxKey).(uuid.UUID); ok {
+		return "user:" + userID.String()
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	hash := sha256.Sum256([]byte(ip + "|" + r.UserAgent()))
+	return "anon:" + hex.EncodeToString(hash[:16])
+}
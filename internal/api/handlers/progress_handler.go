@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/courseDTOs"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+)
+
+type ProgressHandler struct {
+	progressService *services.ProgressService
+	validator       *validator.Validate
+	logger          contracts.Logger
+}
+
+func NewProgressHandler(progressService *services.ProgressService, logger contracts.Logger) *ProgressHandler {
+	return &ProgressHandler{
+		progressService: progressService,
+		validator:       validator.New(),
+		logger:          logger,
+	}
+}
+
+// GetProgress returns the current user's progress through a course and the lesson to resume
+func (h *ProgressHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	progress, err := h.progressService.GetProgress(r.Context(), chi.URLParam(r, "slug"), userID)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, progress)
+}
+
+// SaveCheckpoint handles PUT /courses/{slug}/lessons/{lessonId}/progress, saving the video position
+func (h *ProgressHandler) SaveCheckpoint(w http.ResponseWriter, r *http.Request) {
+	lessonID, err := intURLParam(r, "lessonId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input courseDTOs.LessonCheckpointRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	progress, err := h.progressService.SaveCheckpoint(r.Context(), chi.URLParam(r, "slug"), lessonID, userID, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, progress)
+}
+
+// CompleteLesson handles POST /courses/{slug}/lessons/{lessonId}/complete
+func (h *ProgressHandler) CompleteLesson(w http.ResponseWriter, r *http.Request) {
+	lessonID, err := intURLParam(r, "lessonId")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	progress, err := h.progressService.CompleteLesson(r.Context(), chi.URLParam(r, "slug"), lessonID, userID)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, progress)
+}
+
+// GetClassProgress returns the progress of all students of a course for its owner
+func (h *ProgressHandler) GetClassProgress(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	progress, err := h.progressService.GetClassProgress(r.Context(), id, userID, role)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, progress)
+}
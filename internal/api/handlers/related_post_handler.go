@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+)
+
+type RelatedPostHandler struct {
+	relatedService *services.RelatedPostService
+	logger         contracts.Logger
+}
+
+func NewRelatedPostHandler(relatedService *services.RelatedPostService, logger contracts.Logger) *RelatedPostHandler {
+	return &RelatedPostHandler{
+		relatedService: relatedService,
+		logger:         logger,
+	}
+}
+
+// GetRelatedPosts handles GET /posts/{slug}/related
+func (h *RelatedPostHandler) GetRelatedPosts(w http.ResponseWriter, r *http.Request) {
+	userID, role, _ := currentUser(r)
+	posts, err := h.relatedService.GetRelatedPosts(r.Context(), chi.URLParam(r, "slug"), userID, role)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, posts)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/infrastructure/services"
+	"app05/internal/infrastructure/sitemap"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"bytes"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"time"
+)
+
+// sitemapMaxAge is how long crawlers and proxies may reuse a sitemap without revalidating
+const sitemapMaxAge = time.Hour
+
+type SEOHandler struct {
+	seoService *services.SEOService
+	validator  *validator.Validate
+	logger     contracts.Logger
+}
+
+func NewSEOHandler(seoService *services.SEOService, logger contracts.Logger) *SEOHandler {
+	return &SEOHandler{
+		seoService: seoService,
+		validator:  validator.New(),
+		logger:     logger,
+	}
+}
+
+// GetSitemap handles GET /sitemap.xml
+func (h *SEOHandler) GetSitemap(w http.ResponseWriter, r *http.Request) {
+	document, err := h.seoService.GetSitemap(r.Context())
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	serveSitemap(w, r, document)
+}
+
+// GetSitemapPage handles GET /sitemaps/posts-{page}.xml
+func (h *SEOHandler) GetSitemapPage(w http.ResponseWriter, r *http.Request) {
+	page, err := intURLParam(r, "page")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	document, err := h.seoService.GetSitemapPage(r.Context(), page)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	serveSitemap(w, r, document)
+}
+
+// GetPostMeta returns the OpenGraph and Twitter card metadata of a published post
+func (h *SEOHandler) GetPostMeta(w http.ResponseWriter, r *http.Request) {
+	meta, err := h.seoService.GetPostMeta(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, meta)
+}
+
+func (h *SEOHandler) UpdatePostSEO(w http.ResponseWriter, r *http.Request) {
+	postID, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input postDTOs.UpdatePostSEORequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	post, err := h.seoService.UpdatePostSEO(r.Context(), postID, userID, role, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, post)
+}
+
+func serveSitemap(w http.ResponseWriter, r *http.Request, document *sitemap.Document) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("ETag", document.ETag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(sitemapMaxAge.Seconds())))
+
+	http.ServeContent(w, r, "", document.LastModified, bytes.NewReader(document.Body))
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"app05/internal/core/application/constants"
+	"app05/internal/core/domain/entities"
+	"app05/pkg/appErrors"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+// currentUser returns the authenticated user's id and role set by the auth middleware
+func currentUser(r *http.Request) (uuid.UUID, entities.Role, error) {
+	userID, ok := r.Context().Value(constants.UserIdCtxKey).(uuid.UUID)
+	if !ok {
+		return uuid.Nil, "", appErrors.New(appErrors.CodeUnauthorized, "authentication required")
+	}
+
+	role, _ := r.Context().Value(constants.UserRoleCtxKey).(entities.Role)
+	return userID, role, nil
+}
+
+// requestLocale returns the locale negotiated by the locale middleware, or "" when there is none
+func requestLocale(r *http.Request) string {
+	locale, _ := r.Context().Value(constants.LocaleCtxKey).(string)
+	return locale
+}
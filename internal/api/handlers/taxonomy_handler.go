@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/taxonomyDTOs"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+)
+
+type TaxonomyHandler struct {
+	taxonomyService *services.TaxonomyService
+	validator       *validator.Validate
+	logger          contracts.Logger
+}
+
+func NewTaxonomyHandler(taxonomyService *services.TaxonomyService, logger contracts.Logger) *TaxonomyHandler {
+	return &TaxonomyHandler{
+		taxonomyService: taxonomyService,
+		validator:       validator.New(),
+		logger:          logger,
+	}
+}
+
+// GetCategories returns the category tree with published post counts
+func (h *TaxonomyHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.taxonomyService.GetCategoryTree(r.Context())
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, categories)
+}
+
+func (h *TaxonomyHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var input taxonomyDTOs.CreateCategoryRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	category, err := h.taxonomyService.CreateCategory(r.Context(), input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, category)
+}
+
+func (h *TaxonomyHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input taxonomyDTOs.UpdateCategoryRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	category, err := h.taxonomyService.UpdateCategory(r.Context(), id, input)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, category)
+}
+
+func (h *TaxonomyHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if err := h.taxonomyService.DeleteCategory(r.Context(), id); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, map[string]string{"message": "Category has been deleted"})
+}
+
+// GetCategoryPosts returns published posts in a category and its subcategories
+func (h *TaxonomyHandler) GetCategoryPosts(w http.ResponseWriter, r *http.Request) {
+	userID, role, _ := currentUser(r)
+	page, perPage := utils.ParsePagination(r)
+	posts, total, err := h.taxonomyService.GetPostsByCategory(r.Context(), chi.URLParam(r, "slug"), requestLocale(r), userID, role, page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, posts, page, perPage, total)
+}
+
+// GetTags returns tags ordered by the number of published posts using them
+func (h *TaxonomyHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	page, perPage := utils.ParsePagination(r)
+	tags, total, err := h.taxonomyService.GetTags(r.Context(), page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, tags, page, perPage, total)
+}
+
+// AutocompleteTags handles GET /tags/autocomplete?q=
+func (h *TaxonomyHandler) AutocompleteTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.taxonomyService.AutocompleteTags(r.Context(), r.URL.Query().Get("q"))
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, tags)
+}
+
+// GetTagPosts returns published posts carrying a tag
+func (h *TaxonomyHandler) GetTagPosts(w http.ResponseWriter, r *http.Request) {
+	userID, role, _ := currentUser(r)
+	page, perPage := utils.ParsePagination(r)
+	posts, total, err := h.taxonomyService.GetPostsByTag(r.Context(), chi.URLParam(r, "slug"), requestLocale(r), userID, role, page, perPage)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSONWithPagination(w, posts, page, perPage, total)
+}
+
+func (h *TaxonomyHandler) SetPostCategories(w http.ResponseWriter, r *http.Request) {
+	postID, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input taxonomyDTOs.SetPostCategoriesRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	post, err := h.taxonomyService.SetPostCategories(r.Context(), postID, userID, role, input.CategoryIDs)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, post)
+}
+
+func (h *TaxonomyHandler) SetPostTags(w http.ResponseWriter, r *http.Request) {
+	postID, err := intURLParam(r, "id")
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input taxonomyDTOs.SetPostTagsRequest
+	if err := utils.ParseJSON(w, r, &input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	post, err := h.taxonomyService.SetPostTags(r.Context(), postID, userID, role, input.Tags)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, post)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultTrendingLimit = 10
+	maxTrendingLimit     = 50
+)
+
+type TrendingHandler struct {
+	trendingService *services.TrendingService
+	logger          contracts.Logger
+}
+
+func NewTrendingHandler(trendingService *services.TrendingService, logger contracts.Logger) *TrendingHandler {
+	return &TrendingHandler{
+		trendingService: trendingService,
+		logger:          logger,
+	}
+}
+
+// GetTrendingPosts handles GET /posts/trending?category=&limit=. A category includes its subcategories.
+func (h *TrendingHandler) GetTrendingPosts(w http.ResponseWriter, r *http.Request) {
+	limit := defaultTrendingLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxTrendingLimit {
+			appError := appErrors.New(appErrors.CodeBadRequest, "limit must be between 1 and "+strconv.Itoa(maxTrendingLimit))
+			appErrors.HandleError(w, appError, h.logger)
+			return
+		}
+		limit = parsed
+	}
+
+	userID, role, _ := currentUser(r)
+	category := strings.TrimSpace(r.URL.Query().Get("category"))
+	posts, err := h.trendingService.GetTrendingPosts(r.Context(), category, userID, role, limit)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, posts)
+}
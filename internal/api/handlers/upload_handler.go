@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/mediaDTOs"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"encoding/base64"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,expiration,termination"
+	// Content type of PATCH requests, whose body is the chunk
+	tusChunkContentType = "application/offset+octet-stream"
+	// Time allowed to receive a chunk and, after the last one, to add the file to the media library
+	tusChunkTimeout = 10 * time.Minute
+)
+
+// UploadHandler implements the tus resumable upload protocol (https://tus.io) for the media library
+type UploadHandler struct {
+	uploadService *services.UploadService
+	validator     *validator.Validate
+	logger        contracts.Logger
+}
+
+func NewUploadHandler(uploadService *services.UploadService, logger contracts.Logger) *UploadHandler {
+	return &UploadHandler{
+		uploadService: uploadService,
+		validator:     validator.New(),
+		logger:        logger,
+	}
+}
+
+// TusResumable rejects requests of tus versions other than the one implemented
+func (h *UploadHandler) TusResumable(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Tus-Resumable", tusVersion)
+		if r.Header.Get("Tus-Resumable") != tusVersion {
+			w.Header().Set("Tus-Version", tusVersion)
+			appError := appErrors.New(appErrors.CodePreconditionFailed, "unsupported tus version")
+			appErrors.HandleError(w, appError, h.logger)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Options handles OPTIONS /media/uploads, describing the supported protocol
+func (h *UploadHandler) Options(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateUpload handles POST /media/uploads with the file size in Upload-Length. Upload-Metadata
+// may carry "filename", "alt_text", comma-separated "tags" and "private" (true/false).
+func (h *UploadHandler) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		appErrors.HandleError(w, appErrors.New(appErrors.CodeBadRequest, "Upload-Length must be a file size in bytes"), h.logger)
+		return
+	}
+	metadata, err := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	var input mediaDTOs.CreateMediaRequest
+	if altText := metadata["alt_text"]; altText != "" {
+		input.AltText = &altText
+	}
+	if tags := metadata["tags"]; tags != "" {
+		input.Tags = strings.Split(tags, ",")
+	}
+	if private := metadata["private"]; private != "" {
+		if input.Private, err = strconv.ParseBool(private); err != nil {
+			appErrors.HandleError(w, appErrors.New(appErrors.CodeBadRequest, "private must be true or false"), h.logger)
+			return
+		}
+	}
+	if err := h.validator.Struct(input); err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, err.Error())
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	upload, err := h.uploadService.CreateUpload(r.Context(), userID, role, length, metadata["filename"], input)
+	if err != nil {
+		if maxSize := h.uploadService.MaxSize(role); maxSize >= 0 {
+			w.Header().Set("Tus-Max-Size", strconv.FormatInt(maxSize, 10))
+		}
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+upload.ID)
+	setUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// GetUploadOffset handles HEAD /media/uploads/{uploadID}, reporting how much was received
+func (h *UploadHandler) GetUploadOffset(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	upload, err := h.uploadService.GetUpload(r.Context(), chi.URLParam(r, "uploadID"), userID)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	setUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusOK)
+}
+
+// WriteChunk handles PATCH /media/uploads/{uploadID} with the chunk as the body and the
+// offset it starts at in Upload-Offset. The response of the last chunk carries the id
+// of the added media in Media-ID.
+func (h *UploadHandler) WriteChunk(w http.ResponseWriter, r *http.Request) {
+	userID, role, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if r.Header.Get("Content-Type") != tusChunkContentType {
+		appError := appErrors.New(appErrors.CodeUnsupportedMediaType, "Content-Type must be "+tusChunkContentType)
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		appErrors.HandleError(w, appErrors.New(appErrors.CodeBadRequest, "Upload-Offset must be a byte offset"), h.logger)
+		return
+	}
+
+	// Chunks take longer than the server's timeouts allow for ordinary requests
+	deadline := time.Now().Add(tusChunkTimeout)
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(deadline); err != nil {
+		h.logger.Warn("Failed to extend upload read deadline", "error", err)
+	}
+	if err := rc.SetWriteDeadline(deadline); err != nil {
+		h.logger.Warn("Failed to extend upload write deadline", "error", err)
+	}
+
+	upload, err := h.uploadService.WriteChunk(r.Context(), chi.URLParam(r, "uploadID"), userID, role, offset, r.Body)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	setUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteUpload handles DELETE /media/uploads/{uploadID}, cancelling the upload
+func (h *UploadHandler) DeleteUpload(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	if err := h.uploadService.DeleteUpload(r.Context(), chi.URLParam(r, "uploadID"), userID); err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func setUploadHeaders(w http.ResponseWriter, upload *mediaDTOs.ResumableUpload) {
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	if upload.Completed() {
+		w.Header().Set("Media-ID", strconv.Itoa(upload.MediaID))
+	}
+}
+
+// parseUploadMetadata decodes an Upload-Metadata header: comma-separated pairs of a key
+// and a base64 encoded value, which may be left out
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, appErrors.New(appErrors.CodeBadRequest, "Upload-Metadata values must be base64 encoded")
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"app05/internal/core/application/constants"
+	"app05/internal/core/domain/entities"
+	"context"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+func (nopLogger) Fatal(string, ...interface{}) {}
+func (nopLogger) Panic(string, ...interface{}) {}
+func (nopLogger) Sync() error                  { return nil }
+
+func authenticated(r *http.Request) *http.Request {
+	ctx := context.WithValue(r.Context(), constants.UserIdCtxKey, uuid.New())
+	ctx = context.WithValue(ctx, constants.UserRoleCtxKey, entities.RoleStudent)
+	return r.WithContext(ctx)
+}
+
+// The requests below are all refused before they reach the upload service
+func TestUploadHandler_RejectsInvalidRequests(t *testing.T) {
+	handler := NewUploadHandler(nil, nopLogger{})
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		handle  http.HandlerFunc
+		status  int
+	}{
+		{name: "create without length", method: http.MethodPost, handle: handler.CreateUpload, status: http.StatusBadRequest},
+		{name: "create with a non-numeric length", method: http.MethodPost, headers: map[string]string{"Upload-Length": "ten"}, handle: handler.CreateUpload, status: http.StatusBadRequest},
+		{name: "create with a negative length", method: http.MethodPost, headers: map[string]string{"Upload-Length": "-1"}, handle: handler.CreateUpload, status: http.StatusBadRequest},
+		{name: "create with an overflowing length", method: http.MethodPost, headers: map[string]string{"Upload-Length": "9223372036854775808"}, handle: handler.CreateUpload, status: http.StatusBadRequest},
+		{name: "create with invalid metadata", method: http.MethodPost, headers: map[string]string{"Upload-Length": "10", "Upload-Metadata": "filename !!!"}, handle: handler.CreateUpload, status: http.StatusBadRequest},
+		{name: "create with an invalid private flag", method: http.MethodPost, headers: map[string]string{"Upload-Length": "10", "Upload-Metadata": "private bWF5YmU="}, handle: handler.CreateUpload, status: http.StatusBadRequest},
+		{name: "chunk without content type", method: http.MethodPatch, headers: map[string]string{"Upload-Offset": "0"}, handle: handler.WriteChunk, status: http.StatusUnsupportedMediaType},
+		{name: "chunk without offset", method: http.MethodPatch, headers: map[string]string{"Content-Type": tusChunkContentType}, handle: handler.WriteChunk, status: http.StatusBadRequest},
+		{name: "chunk with a non-numeric offset", method: http.MethodPatch, headers: map[string]string{"Content-Type": tusChunkContentType, "Upload-Offset": "0x10"}, handle: handler.WriteChunk, status: http.StatusBadRequest},
+		{name: "chunk with a negative offset", method: http.MethodPatch, headers: map[string]string{"Content-Type": tusChunkContentType, "Upload-Offset": "-5"}, handle: handler.WriteChunk, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/media/uploads", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			tt.handle(w, authenticated(r))
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+		})
+	}
+}
+
+func TestUploadHandler_TusResumable(t *testing.T) {
+	handler := NewUploadHandler(nil, nopLogger{})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	tests := []struct {
+		version string
+		status  int
+	}{
+		{version: tusVersion, status: http.StatusNoContent},
+		{version: "0.2.2", status: http.StatusPreconditionFailed},
+		{version: "", status: http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodHead, "/media/uploads/1", nil)
+			if tt.version != "" {
+				r.Header.Set("Tus-Resumable", tt.version)
+			}
+			w := httptest.NewRecorder()
+
+			handler.TusResumable(next).ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Tus-Resumable"); got != tusVersion {
+				t.Fatalf("Tus-Resumable = %q, want %q", got, tusVersion)
+			}
+		})
+	}
+}
+
+func TestParseUploadMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty", header: "", want: map[string]string{}},
+		{name: "pairs", header: "filename cGhvdG8ucG5n,private dHJ1ZQ==", want: map[string]string{"filename": "photo.png", "private": "true"}},
+		{name: "spaces and empty pairs", header: " filename cGhvdG8ucG5n , ,tags Z28sd2Vi", want: map[string]string{"filename": "photo.png", "tags": "go,web"}},
+		{name: "key without value", header: "is_confidential,filename YQ==", want: map[string]string{"is_confidential": "", "filename": "a"}},
+		{name: "invalid base64", header: "filename photo.png", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseUploadMetadata(tt.header)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
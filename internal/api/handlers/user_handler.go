@@ -7,13 +7,15 @@ import (
 	"app05/internal/infrastructure/services"
 	"app05/pkg/appErrors"
 	"app05/pkg/utils"
+	"errors"
 	"github.com/google/uuid"
 	"net/http"
 )
 
 const (
-	maxUploadSize = 5 << 20 // 5MB
-	uploadPath    = "uploads/profile-pictures"
+	maxUploadSize     = 5 << 20 // 5MB
+	avatarFormField   = "avatar"
+	multipartOverhead = 1 << 20 // room for the multipart boundaries and headers
 )
 
 type UserHandler struct {
@@ -78,3 +80,63 @@ func (h *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 	// Return success message
 	utils.SendJSON(w, map[string]string{"message": "User email has been verified"})
 }
+
+// UpdateAvatar handles PUT /users/me/avatar with the picture in the multipart "avatar" field
+func (h *UserHandler) UpdateAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize+multipartOverhead)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		message := "invalid multipart form"
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			message = "profile picture must not exceed 5MB"
+		}
+		appErrors.HandleError(w, appErrors.New(appErrors.CodeBadRequest, message), h.logger)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile(avatarFormField)
+	if err != nil {
+		appError := appErrors.New(appErrors.CodeBadRequest, "a file is required in the 'avatar' field")
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxUploadSize {
+		appError := appErrors.New(appErrors.CodeBadRequest, "profile picture must not exceed 5MB")
+		appErrors.HandleError(w, appError, h.logger)
+		return
+	}
+
+	profile, err := h.userService.UpdateAvatar(r.Context(), userID, file)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, profile)
+}
+
+// DeleteAvatar handles DELETE /users/me/avatar
+func (h *UserHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, _, err := currentUser(r)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	profile, err := h.userService.DeleteAvatar(r.Context(), userID)
+	if err != nil {
+		appErrors.HandleError(w, err, h.logger)
+		return
+	}
+
+	utils.SendJSON(w, profile)
+}
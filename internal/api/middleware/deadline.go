@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"app05/internal/core/application/contracts"
+	"net/http"
+	"time"
+)
+
+// DeadlineMiddleware gives requests up to timeout to send their body and receive the
+// response, overriding the server's read and write timeouts. Use it on routes that
+// receive large uploads.
+func DeadlineMiddleware(timeout time.Duration, logger contracts.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deadline := time.Now().Add(timeout)
+			controller := http.NewResponseController(w)
+			if err := controller.SetReadDeadline(deadline); err != nil {
+				logger.Warn("Failed to extend request read deadline", "path", r.URL.Path, "error", err)
+			}
+			if err := controller.SetWriteDeadline(deadline); err != nil {
+				logger.Warn("Failed to extend request write deadline", "path", r.URL.Path, "error", err)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"app05/internal/core/application/constants"
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/services"
+	"app05/pkg/appErrors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+// EnrollmentMiddleware lets only users with access to the lessons of the course in the
+// slug route parameter through. It must follow AuthMiddleware.
+func EnrollmentMiddleware(enrollmentService *services.EnrollmentService, logger contracts.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value(constants.UserIdCtxKey).(uuid.UUID)
+			if !ok {
+				Error := appErrors.New(appErrors.CodeUnauthorized, "authentication required")
+				appErrors.HandleError(w, Error, logger)
+				return
+			}
+			role, _ := r.Context().Value(constants.UserRoleCtxKey).(entities.Role)
+
+			if err := enrollmentService.CheckLessonAccess(r.Context(), chi.URLParam(r, "slug"), userID, role); err != nil {
+				appErrors.HandleError(w, err, logger)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
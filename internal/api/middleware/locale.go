@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"app05/internal/core/application/constants"
+	"app05/internal/infrastructure/i18n"
+	"context"
+	"net/http"
+)
+
+// LocaleMiddleware negotiates the locale of the request from the lang query
+// parameter or the Accept-Language header and adds it to the context
+func LocaleMiddleware(locales *i18n.Locales) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := locales.Negotiate(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language"))
+
+			// Responses differ by language, so shared caches must key on it
+			w.Header().Add("Vary", "Accept-Language")
+
+			ctx := context.WithValue(r.Context(), constants.LocaleCtxKey, locale)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
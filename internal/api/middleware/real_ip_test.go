@@ -0,0 +1,71 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIPMiddleware(t *testing.T) {
+	middleware, err := RealIPMiddleware([]string{"10.0.0.0/8", "::1", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("RealIPMiddleware: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		remoteAddr     string
+		forwardedFor   []string
+		realIP         string
+		wantRemoteAddr string
+	}{
+		{name: "direct client", remoteAddr: "1.2.3.4:5", wantRemoteAddr: "1.2.3.4:5"},
+		{name: "untrusted peer's headers are ignored", remoteAddr: "1.2.3.4:5", forwardedFor: []string{"9.9.9.9"}, realIP: "9.9.9.9", wantRemoteAddr: "1.2.3.4:5"},
+		{name: "trusted peer without headers", remoteAddr: "10.0.0.1:5", wantRemoteAddr: "10.0.0.1:5"},
+		{name: "trusted peer", remoteAddr: "10.0.0.1:5", forwardedFor: []string{"9.9.9.9"}, wantRemoteAddr: "9.9.9.9"},
+		{name: "spoofed leftmost hop", remoteAddr: "10.0.0.1:5", forwardedFor: []string{"9.9.9.9, 8.8.8.8"}, wantRemoteAddr: "8.8.8.8"},
+		{name: "trusted hops are skipped", remoteAddr: "10.0.0.1:5", forwardedFor: []string{"9.9.9.9, 10.1.1.1"}, wantRemoteAddr: "9.9.9.9"},
+		{name: "repeated headers", remoteAddr: "10.0.0.1:5", forwardedFor: []string{"9.9.9.9", "8.8.8.8, 10.1.1.1"}, wantRemoteAddr: "8.8.8.8"},
+		{name: "all hops trusted", remoteAddr: "10.0.0.1:5", forwardedFor: []string{"10.2.2.2, 10.1.1.1"}, wantRemoteAddr: "10.2.2.2"},
+		{name: "malformed hop stops the walk", remoteAddr: "10.0.0.1:5", forwardedFor: []string{"9.9.9.9, junk, 7.7.7.7"}, wantRemoteAddr: "7.7.7.7"},
+		{name: "only malformed hops", remoteAddr: "10.0.0.1:5", forwardedFor: []string{"junk"}, wantRemoteAddr: "10.0.0.1:5"},
+		{name: "ipv6 peer", remoteAddr: "[::1]:5", forwardedFor: []string{"2001:db8::1"}, wantRemoteAddr: "2001:db8::1"},
+		{name: "ipv4 mapped peer", remoteAddr: "[::ffff:10.0.0.1]:5", forwardedFor: []string{"9.9.9.9"}, wantRemoteAddr: "9.9.9.9"},
+		{name: "single trusted address", remoteAddr: "192.168.1.1:5", forwardedFor: []string{"9.9.9.9"}, wantRemoteAddr: "9.9.9.9"},
+		{name: "neighbour of a trusted address", remoteAddr: "192.168.1.2:5", forwardedFor: []string{"9.9.9.9"}, wantRemoteAddr: "192.168.1.2:5"},
+		{name: "x-real-ip", remoteAddr: "10.0.0.1:5", realIP: "9.9.9.9", wantRemoteAddr: "9.9.9.9"},
+		{name: "x-forwarded-for wins over x-real-ip", remoteAddr: "10.0.0.1:5", forwardedFor: []string{"8.8.8.8"}, realIP: "9.9.9.9", wantRemoteAddr: "8.8.8.8"},
+		{name: "unparsable remote address", remoteAddr: "pipe", forwardedFor: []string{"9.9.9.9"}, wantRemoteAddr: "pipe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			var got string
+			middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			})).ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.wantRemoteAddr {
+				t.Fatalf("RemoteAddr = %q, want %q", got, tt.wantRemoteAddr)
+			}
+		})
+	}
+}
+
+func TestRealIPMiddleware_InvalidProxies(t *testing.T) {
+	for _, proxy := range []string{"", "localhost", "10.0.0.0/33", "10.0.0"} {
+		t.Run(proxy, func(t *testing.T) {
+			if _, err := RealIPMiddleware([]string{"127.0.0.1", proxy}); err == nil {
+				t.Fatalf("expected an error for trusted proxy %q", proxy)
+			}
+		})
+	}
+}
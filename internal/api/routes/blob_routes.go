@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/blob"
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterBlobRoutes serves the files of the local blob store: public files under
+// /uploads, any file through the presigned download URLs under /blobs. Presigned
+// requests are authorized by their signature alone.
+func RegisterBlobRoutes(r chi.Router, store *blob.LocalStore, links *blob.Links, logger contracts.Logger) {
+	h := handlers.NewBlobHandler(store, links, logger)
+
+	r.Get("/uploads/*", h.GetPublicBlob)
+
+	r.Route("/blobs", func(r chi.Router) {
+		r.Get("/*", h.GetBlob)
+	})
+}
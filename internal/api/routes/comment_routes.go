@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterCommentRoutes(r chi.Router, sessionCache *cache.SessionCache, commentService *services.CommentService, logger contracts.Logger) {
+	h := handlers.NewCommentHandler(commentService, logger)
+	auth := middlewares.NewAuthMiddleware(sessionCache, logger)
+	admins := []entities.Role{entities.RoleSuperUser, entities.RoleAdmin}
+
+	r.Route("/posts/{slug}/comments", func(r chi.Router) {
+		r.With(auth.OptionalAuth).Get("/", h.GetPostComments)
+		r.With(middlewares.AuthMiddleware(sessionCache, logger)).Post("/", h.CreateComment)
+	})
+
+	r.Route("/comments", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+		r.Put("/{id}", h.UpdateComment)
+		r.Delete("/{id}", h.DeleteComment)
+
+		// Moderation is restricted to admins
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RoleMiddleware(admins, logger, sessionCache))
+			r.Get("/moderation", h.GetModerationQueue)
+			r.Put("/{id}/moderation", h.ModerateComment)
+		})
+	})
+}
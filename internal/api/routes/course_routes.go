@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterCourseRoutes(r chi.Router, sessionCache *cache.SessionCache, courseService *services.CourseService, enrollmentService *services.EnrollmentService, progressService *services.ProgressService, logger contracts.Logger) {
+	h := handlers.NewCourseHandler(courseService, logger)
+	enrollments := handlers.NewEnrollmentHandler(enrollmentService, logger)
+	progress := handlers.NewProgressHandler(progressService, logger)
+
+	authors := []entities.Role{entities.RoleSuperUser, entities.RoleAdmin, entities.RoleInstructor}
+
+	r.With(middlewares.AuthMiddleware(sessionCache, logger)).Get("/users/me/courses", enrollments.GetMyCourses)
+
+	r.Route("/courses", func(r chi.Router) {
+		// Course outlines are public, lesson content is for enrolled students
+		r.Get("/", h.GetCourses)
+		r.Get("/{slug}", h.GetCourse)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+			r.Group(func(r chi.Router) {
+				r.Use(middlewares.EnrollmentMiddleware(enrollmentService, logger))
+				r.Get("/{slug}/lessons/{lessonId}", h.GetLesson)
+				r.Get("/{slug}/progress", progress.GetProgress)
+				r.Put("/{slug}/lessons/{lessonId}/progress", progress.SaveCheckpoint)
+				r.Post("/{slug}/lessons/{lessonId}/complete", progress.CompleteLesson)
+			})
+			r.Post("/{slug}/enrollment", enrollments.Enroll)
+			r.Delete("/{slug}/enrollment", enrollments.Unenroll)
+		})
+
+		// Instructors manage their own courses, admins any course
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+			r.Use(middlewares.RoleMiddleware(authors, logger, sessionCache))
+			r.Get("/managed", h.GetManagedCourses)
+			r.Post("/", h.CreateCourse)
+			r.Put("/{id}", h.UpdateCourse)
+			r.Delete("/{id}", h.DeleteCourse)
+			r.Put("/{id}/enrollment-settings", h.UpdateEnrollmentSettings)
+			r.Get("/{id}/enrollments", enrollments.GetCourseEnrollments)
+			r.Post("/{id}/enrollments", enrollments.EnrollUser)
+			r.Delete("/{id}/enrollments/{userId}", enrollments.RemoveEnrollment)
+			r.Get("/{id}/class-progress", progress.GetClassProgress)
+			r.Get("/{id}/modules", h.GetManagedCourse)
+			r.Post("/{id}/modules", h.CreateModule)
+			r.Put("/{id}/modules/order", h.ReorderModules)
+			r.Put("/{id}/modules/{moduleId}", h.UpdateModule)
+			r.Delete("/{id}/modules/{moduleId}", h.DeleteModule)
+			r.Post("/{id}/modules/{moduleId}/lessons", h.CreateLesson)
+			r.Put("/{id}/modules/{moduleId}/lessons/order", h.ReorderLessons)
+			r.Get("/{id}/modules/{moduleId}/lessons/{lessonId}", h.GetManagedLesson)
+			r.Put("/{id}/modules/{moduleId}/lessons/{lessonId}", h.UpdateLesson)
+			r.Delete("/{id}/modules/{moduleId}/lessons/{lessonId}", h.DeleteLesson)
+		})
+	})
+}
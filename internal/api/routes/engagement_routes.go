@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterEngagementRoutes(r chi.Router, sessionCache *cache.SessionCache, engagementService *services.EngagementService, logger contracts.Logger) {
+	h := handlers.NewEngagementHandler(engagementService, logger)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+
+		r.Put("/posts/{slug}/reactions/{reaction}", h.AddReaction)
+		r.Delete("/posts/{slug}/reactions/{reaction}", h.RemoveReaction)
+
+		r.Put("/posts/{slug}/bookmark", h.AddBookmark)
+		r.Delete("/posts/{slug}/bookmark", h.RemoveBookmark)
+
+		r.Get("/users/me/bookmarks", h.GetMyBookmarks)
+	})
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterFeedRoutes mounts the syndication feeds. They live outside the API
+// prefix so feed readers get stable, conventional URLs.
+func RegisterFeedRoutes(r chi.Router, feedService *services.FeedService, logger contracts.Logger) {
+	h := handlers.NewFeedHandler(feedService, logger)
+
+	r.Route("/feeds", func(r chi.Router) {
+		r.Get("/posts.{format}", h.GetPostsFeed)
+	})
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+	"time"
+)
+
+func RegisterMediaRoutes(r chi.Router, sessionCache *cache.SessionCache, mediaService *services.MediaService, uploadService *services.UploadService, uploadTimeout time.Duration, logger contracts.Logger) {
+	h := handlers.NewMediaHandler(mediaService, logger)
+	u := handlers.NewUploadHandler(uploadService, logger)
+
+	authors := []entities.Role{entities.RoleSuperUser, entities.RoleAdmin, entities.RoleInstructor}
+
+	// Uploads outlast the server's read and write timeouts
+	uploadDeadline := middlewares.DeadlineMiddleware(uploadTimeout, logger)
+
+	r.Route("/media", func(r chi.Router) {
+		// tus clients discover the protocol before authenticating
+		r.Options("/uploads", u.Options)
+
+		// Authors manage their own media, admins any media
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+			r.Use(middlewares.RoleMiddleware(authors, logger, sessionCache))
+			r.With(uploadDeadline).Post("/", h.UploadMedia)
+			r.Get("/", h.GetMyMedia)
+			r.Get("/usage", h.GetMediaUsage)
+			r.Get("/{id}", h.GetMedia)
+			r.Put("/{id}", h.UpdateMedia)
+			r.Delete("/{id}", h.DeleteMedia)
+		})
+
+		// Resumable uploads of large files, over the tus protocol
+		r.Group(func(r chi.Router) {
+			r.Use(u.TusResumable)
+			r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+			r.Use(middlewares.RoleMiddleware(authors, logger, sessionCache))
+			r.Post("/uploads", u.CreateUpload)
+			r.Head("/uploads/{uploadID}", u.GetUploadOffset)
+			r.With(uploadDeadline).Patch("/uploads/{uploadID}", u.WriteChunk)
+			r.Delete("/uploads/{uploadID}", u.DeleteUpload)
+		})
+	})
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterPostAuthorRoutes(r chi.Router, sessionCache *cache.SessionCache, authorService *services.PostAuthorService, logger contracts.Logger) {
+	h := handlers.NewPostAuthorHandler(authorService, logger)
+
+	admins := []entities.Role{entities.RoleSuperUser, entities.RoleAdmin}
+	authors := []entities.Role{entities.RoleSuperUser, entities.RoleAdmin, entities.RoleInstructor}
+
+	// Post owners manage their co-authors
+	r.Group(func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+		r.Use(middlewares.RoleMiddleware(authors, logger, sessionCache))
+		r.Put("/posts/{id}/authors/{userId}", h.SetAuthor)
+		r.Delete("/posts/{id}/authors/{userId}", h.RemoveAuthor)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+		r.Use(middlewares.RoleMiddleware(admins, logger, sessionCache))
+		r.Post("/admin/users/{id}/transfer-posts", h.TransferPosts)
+	})
+}
@@ -2,14 +2,34 @@ package routes
 
 import (
 	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
 	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/cache"
 	"app05/internal/infrastructure/services"
 	"github.com/go-chi/chi/v5"
 )
 
-func RegisterPostRoutes(r chi.Router, postService *services.PostService, logger contracts.Logger) {
+func RegisterPostRoutes(r chi.Router, sessionCache *cache.SessionCache, postService *services.PostService, logger contracts.Logger) {
 	h := handlers.NewPostHandler(postService, logger)
+	auth := middlewares.NewAuthMiddleware(sessionCache, logger)
+
+	authors := []entities.Role{entities.RoleSuperUser, entities.RoleAdmin, entities.RoleInstructor}
+
 	r.Route("/posts", func(r chi.Router) {
-		r.Get("/", h.GetAllPosts)
+		r.With(auth.OptionalAuth).Get("/", h.GetAllPosts)
+		r.With(auth.OptionalAuth).Get("/search", h.SearchPosts)
+		r.With(auth.OptionalAuth).Get("/{slug}", h.GetPostBySlug)
+
+		// Editing is further limited by the user's role on each post
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+			r.Use(middlewares.RoleMiddleware(authors, logger, sessionCache))
+			r.Post("/", h.CreatePost)
+			r.Put("/{id}", h.UpdatePost)
+			r.Put("/{id}/status", h.UpdatePostStatus)
+			r.Put("/{id}/visibility", h.UpdatePostVisibility)
+			r.Put("/{id}/translation", h.UpdatePostTranslation)
+		})
 	})
 }
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterPostTransferRoutes(r chi.Router, sessionCache *cache.SessionCache, transferService *services.PostTransferService, logger contracts.Logger) {
+	h := handlers.NewPostTransferHandler(transferService, logger)
+
+	admins := []entities.Role{entities.RoleSuperUser, entities.RoleAdmin}
+
+	r.Route("/admin/posts", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+		r.Use(middlewares.RoleMiddleware(admins, logger, sessionCache))
+		r.Post("/import", h.ImportPosts)
+		r.Get("/export", h.ExportPosts)
+	})
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterRelatedPostRoutes(r chi.Router, sessionCache *cache.SessionCache, relatedService *services.RelatedPostService, logger contracts.Logger) {
+	h := handlers.NewRelatedPostHandler(relatedService, logger)
+	auth := middlewares.NewAuthMiddleware(sessionCache, logger)
+
+	r.With(auth.OptionalAuth).Get("/posts/{slug}/related", h.GetRelatedPosts)
+}
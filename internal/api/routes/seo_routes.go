@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterSitemapRoutes mounts the sitemaps at the site root where crawlers expect them
+func RegisterSitemapRoutes(r chi.Router, seoService *services.SEOService, logger contracts.Logger) {
+	h := handlers.NewSEOHandler(seoService, logger)
+
+	r.Get("/sitemap.xml", h.GetSitemap)
+	r.Get("/sitemaps/posts-{page}.xml", h.GetSitemapPage)
+}
+
+func RegisterSEORoutes(r chi.Router, sessionCache *cache.SessionCache, seoService *services.SEOService, logger contracts.Logger) {
+	h := handlers.NewSEOHandler(seoService, logger)
+
+	authors := []entities.Role{entities.RoleSuperUser, entities.RoleAdmin, entities.RoleInstructor}
+
+	r.Get("/posts/{slug}/seo", h.GetPostMeta)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+		r.Use(middlewares.RoleMiddleware(authors, logger, sessionCache))
+		r.Put("/posts/{id}/seo", h.UpdatePostSEO)
+	})
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterSeriesRoutes(r chi.Router, sessionCache *cache.SessionCache, seriesService *services.SeriesService, logger contracts.Logger) {
+	h := handlers.NewSeriesHandler(seriesService, logger)
+
+	authors := []entities.Role{entities.RoleSuperUser, entities.RoleAdmin, entities.RoleInstructor}
+
+	r.Route("/series", func(r chi.Router) {
+		r.Get("/", h.GetSeriesList)
+		r.Get("/{slug}", h.GetSeries)
+
+		// Authors manage their own series, admins any series
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+			r.Use(middlewares.RoleMiddleware(authors, logger, sessionCache))
+			r.Post("/", h.CreateSeries)
+			r.Put("/{id}", h.UpdateSeries)
+			r.Delete("/{id}", h.DeleteSeries)
+			r.Get("/{id}/posts", h.GetManagedSeries)
+			r.Post("/{id}/posts", h.AddPost)
+			r.Put("/{id}/posts/order", h.ReorderPosts)
+			r.Delete("/{id}/posts/{postId}", h.RemovePost)
+		})
+	})
+}
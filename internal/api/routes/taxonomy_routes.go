@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterTaxonomyRoutes(r chi.Router, sessionCache *cache.SessionCache, taxonomyService *services.TaxonomyService, logger contracts.Logger) {
+	h := handlers.NewTaxonomyHandler(taxonomyService, logger)
+	auth := middlewares.NewAuthMiddleware(sessionCache, logger)
+
+	admins := []entities.Role{entities.RoleSuperUser, entities.RoleAdmin}
+	authors := []entities.Role{entities.RoleSuperUser, entities.RoleAdmin, entities.RoleInstructor}
+
+	r.Route("/categories", func(r chi.Router) {
+		r.Get("/", h.GetCategories)
+		r.With(auth.OptionalAuth).Get("/{slug}/posts", h.GetCategoryPosts)
+
+		// Category management is restricted to admins
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+			r.Use(middlewares.RoleMiddleware(admins, logger, sessionCache))
+			r.Post("/", h.CreateCategory)
+			r.Put("/{id}", h.UpdateCategory)
+			r.Delete("/{id}", h.DeleteCategory)
+		})
+	})
+
+	r.Route("/tags", func(r chi.Router) {
+		r.Get("/", h.GetTags)
+		r.Get("/autocomplete", h.AutocompleteTags)
+		r.With(auth.OptionalAuth).Get("/{slug}/posts", h.GetTagPosts)
+	})
+
+	// Assigning terms to posts
+	r.Group(func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(sessionCache, logger))
+		r.Use(middlewares.RoleMiddleware(authors, logger, sessionCache))
+		r.Put("/posts/{id}/categories", h.SetPostCategories)
+		r.Put("/posts/{id}/tags", h.SetPostTags)
+	})
+}
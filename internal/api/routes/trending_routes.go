@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"app05/internal/api/handlers"
+	middlewares "app05/internal/api/middleware"
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterTrendingRoutes(r chi.Router, sessionCache *cache.SessionCache, trendingService *services.TrendingService, logger contracts.Logger) {
+	h := handlers.NewTrendingHandler(trendingService, logger)
+	auth := middlewares.NewAuthMiddleware(sessionCache, logger)
+
+	r.With(auth.OptionalAuth).Get("/posts/trending", h.GetTrendingPosts)
+}
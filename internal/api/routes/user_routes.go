@@ -23,6 +23,8 @@ func RegisterUserRoutes(r chi.Router, sessionCache *cache.SessionCache, userServ
 			// used to list all users in the CMS. Only accessible by admins and superusers. superusers can list all users, admins can only list users with roles below them
 			r.Get("/profile", userHandler.GetProfile)
 			r.Post("/verify-email", userHandler.VerifyEmail)
+			r.Put("/me/avatar", userHandler.UpdateAvatar)
+			r.Delete("/me/avatar", userHandler.DeleteAvatar)
 		})
 	})
 }
@@ -5,7 +5,12 @@ type userRoleKey string
 
 type sessionKey string
 
+type localeKey string
+
 // UserCtx is the key for the user in the context of the request. don't use string directly and DO NOT MODIFY
 const UserIdCtxKey userIdKey = "UserID"
 const UserRoleCtxKey userRoleKey = "UserRole"
 const SessionCtxKey sessionKey = "Session"
+
+// LocaleCtxKey holds the locale negotiated for the request
+const LocaleCtxKey localeKey = "Locale"
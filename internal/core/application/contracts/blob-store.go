@@ -0,0 +1,31 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrBlobNotFound is returned by a BlobStore for keys that hold no file
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore stores files under slash-separated keys such as "profile-pictures/a.jpg"
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get returns the file's content, which the caller must close
+	Get(ctx context.Context, key string) (io.ReadCloser, *BlobInfo, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (*BlobInfo, error)
+	// List returns the files whose keys start with prefix
+	List(ctx context.Context, prefix string) ([]*BlobInfo, error)
+	// PresignGet returns a URL anyone can download the file from until it expires
+	PresignGet(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+type BlobInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
@@ -0,0 +1,23 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrScanTooLarge is returned for files larger than the scanner accepts
+var ErrScanTooLarge = errors.New("file exceeds the scanner's size limit")
+
+// Scanner checks files for malware
+type Scanner interface {
+	// Scan reads body to its end and reports what it found
+	Scan(ctx context.Context, body io.Reader) (*ScanResult, error)
+	// MaxSize is the largest file the scanner accepts in bytes, negative for unlimited
+	MaxSize() int64
+}
+
+type ScanResult struct {
+	Infected  bool
+	Signature string // name of the malware found in an infected file
+}
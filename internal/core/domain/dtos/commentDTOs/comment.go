@@ -0,0 +1,44 @@
+package commentDTOs
+
+import "time"
+
+type CommentDTO struct {
+	ID        int               `json:"id"`
+	PostID    int               `json:"post_id"`
+	ParentID  *int              `json:"parent_id,omitempty"`
+	Author    CommentAuthorDTO  `json:"author"`
+	Body      string            `json:"body"`
+	Status    string            `json:"status"`
+	Deleted   bool              `json:"deleted"`
+	EditedAt  *time.Time        `json:"edited_at,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Post      *CommentedPostDTO `json:"post,omitempty"` // only set in the moderation queue
+	Replies   []*CommentDTO     `json:"replies,omitempty"`
+}
+
+type CommentAuthorDTO struct {
+	ID                string  `json:"id"`
+	FirstName         string  `json:"first_name"`
+	LastName          string  `json:"last_name"`
+	ProfilePictureURL *string `json:"profile_picture_url,omitempty"`
+}
+
+type CommentedPostDTO struct {
+	ID    int     `json:"id"`
+	Title string  `json:"title"`
+	Slug  *string `json:"slug,omitempty"`
+}
+
+type CreateCommentRequest struct {
+	Body     string `json:"body" validate:"required,max=5000"`
+	ParentID *int   `json:"parent_id" validate:"omitempty,min=1"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" validate:"required,max=5000"`
+}
+
+type ModerateCommentRequest struct {
+	Status string `json:"status" validate:"required,oneof=approved rejected spam"`
+}
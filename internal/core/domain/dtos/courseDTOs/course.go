@@ -0,0 +1,77 @@
+package courseDTOs
+
+import "time"
+
+// CreateCourseRequest creates a draft course owned by the requesting instructor
+type CreateCourseRequest struct {
+	Title       string  `json:"title" validate:"required,max=200"`
+	Description *string `json:"description" validate:"omitempty,max=2000"`
+}
+
+type UpdateCourseRequest struct {
+	Title       string  `json:"title" validate:"required,max=200"`
+	Description *string `json:"description" validate:"omitempty,max=2000"`
+	Status      string  `json:"status" validate:"required,oneof=draft published"`
+}
+
+// UpdateEnrollmentSettingsRequest sets who may join a course and when. Without a
+// limit any number of students may enroll.
+type UpdateEnrollmentSettingsRequest struct {
+	IsFree          bool       `json:"is_free"`
+	EnrollmentLimit *int       `json:"enrollment_limit" validate:"omitempty,min=1"`
+	StartsAt        *time.Time `json:"starts_at"`
+	EndsAt          *time.Time `json:"ends_at"`
+}
+
+// EnrollUserRequest enrolls a user on behalf of the course instructor
+type EnrollUserRequest struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+}
+
+// CreateModuleRequest adds a draft module at Position, shifting later modules down.
+// Without a position the module is appended.
+type CreateModuleRequest struct {
+	Title       string  `json:"title" validate:"required,max=200"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+	Position    int     `json:"position" validate:"omitempty,min=1"`
+}
+
+type UpdateModuleRequest struct {
+	Title       string  `json:"title" validate:"required,max=200"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+	Status      string  `json:"status" validate:"required,oneof=draft published"`
+}
+
+// CreateLessonRequest adds a draft lesson at Position, shifting later lessons down.
+// Without a position the lesson is appended. Lessons are required unless IsRequired is false.
+type CreateLessonRequest struct {
+	Title           string `json:"title" validate:"required,max=200"`
+	Content         string `json:"content" validate:"required"`
+	DurationMinutes *int   `json:"duration_minutes" validate:"omitempty,min=1,max=1440"`
+	IsRequired      *bool  `json:"is_required"`
+	Position        int    `json:"position" validate:"omitempty,min=1"`
+}
+
+// UpdateLessonRequest replaces a lesson. Lessons are required unless IsRequired is false.
+type UpdateLessonRequest struct {
+	Title           string `json:"title" validate:"required,max=200"`
+	Content         string `json:"content" validate:"required"`
+	DurationMinutes *int   `json:"duration_minutes" validate:"omitempty,min=1,max=1440"`
+	IsRequired      *bool  `json:"is_required"`
+	Status          string `json:"status" validate:"required,oneof=draft published"`
+}
+
+// LessonCheckpointRequest records how far into a lesson's video the student got
+type LessonCheckpointRequest struct {
+	PositionSeconds int `json:"position_seconds" validate:"min=0,max=86400"`
+}
+
+// ReorderModulesRequest lists every module of the course in its new order
+type ReorderModulesRequest struct {
+	ModuleIDs []int `json:"module_ids" validate:"required,min=1,max=500,dive,min=1"`
+}
+
+// ReorderLessonsRequest lists every lesson of the module in its new order
+type ReorderLessonsRequest struct {
+	LessonIDs []int `json:"lesson_ids" validate:"required,min=1,max=500,dive,min=1"`
+}
@@ -0,0 +1,62 @@
+package mediaDTOs
+
+import (
+	"app05/internal/core/domain/entities"
+	"time"
+)
+
+// Malware scan states of media. Files are only served once scanned clean. Files that
+// could not be scanned end up failed.
+const (
+	ScanPending  = "pending"
+	ScanClean    = "clean"
+	ScanInfected = "infected"
+	ScanFailed   = "failed"
+)
+
+type MediaDTO struct {
+	ID            int                      `json:"id"`
+	UserID        string                   `json:"user_id"`
+	StorageKey    string                   `json:"-"`
+	URL           string                   `json:"url,omitempty"` // presigned and expiring for private files, empty until scanned clean
+	Private       bool                     `json:"private"`
+	FileName      string                   `json:"file_name"`
+	ContentType   string                   `json:"content_type"`
+	Size          int64                    `json:"size_bytes"`
+	AltText       *string                  `json:"alt_text,omitempty"`
+	Tags          []string                 `json:"tags"`
+	ScanStatus    string                   `json:"scan_status"`
+	ScanSignature *string                  `json:"scan_signature,omitempty"` // malware found in an infected file
+	ScanAttempts  int                      `json:"-"`                        // scans tried so far
+	UsageCount    int                      `json:"usage_count"`              // number of posts referencing the file
+	Variants      []*entities.ImageVariant `json:"variants"`                 // resized copies of images, empty until generated
+	Posts         []*MediaPostDTO          `json:"posts,omitempty"`          // set on single media requests
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
+}
+
+// MediaPostDTO is a post whose content references a media file
+type MediaPostDTO struct {
+	ID     int     `json:"id"`
+	Title  string  `json:"title"`
+	Slug   *string `json:"slug,omitempty"`
+	Status string  `json:"status"`
+}
+
+// MediaUsageDTO is a user's storage use. Quota is omitted when the user's role is unlimited.
+type MediaUsageDTO struct {
+	UsedBytes  int64  `json:"used_bytes"`
+	QuotaBytes *int64 `json:"quota_bytes,omitempty"`
+}
+
+// CreateMediaRequest holds the form fields sent with an uploaded file
+type CreateMediaRequest struct {
+	AltText *string  `validate:"omitempty,max=500"`
+	Tags    []string `validate:"max=10,dive,required,max=50"`
+	Private bool
+}
+
+type UpdateMediaRequest struct {
+	AltText *string  `json:"alt_text" validate:"omitempty,max=500"`
+	Tags    []string `json:"tags" validate:"max=10,dive,required,max=50"`
+}
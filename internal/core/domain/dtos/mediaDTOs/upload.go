@@ -0,0 +1,22 @@
+package mediaDTOs
+
+import "time"
+
+// ResumableUpload is a file being uploaded in chunks. Chunks are kept in the blob store
+// until the last one arrives and the file is added to the media library.
+type ResumableUpload struct {
+	ID        string
+	UserID    string
+	Length    int64 // total size of the file in bytes
+	Offset    int64 // bytes received so far
+	FileName  string
+	Media     CreateMediaRequest
+	Chunks    []string // blob keys of the received chunks, in order
+	MediaID   int      // set once the file is in the media library
+	ExpiresAt time.Time
+}
+
+// Completed reports whether the file has been added to the media library
+func (u *ResumableUpload) Completed() bool {
+	return u.MediaID != 0
+}
@@ -0,0 +1,21 @@
+package postDTOs
+
+type PostAuthorDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// SetPostAuthorRequest adds a co-author or changes their role. Ownership is only moved by a transfer.
+type SetPostAuthorRequest struct {
+	Role string `json:"role" validate:"required,oneof=editor contributor"`
+}
+
+// TransferPostsRequest moves the ownership of all of a deactivated user's posts to another user
+type TransferPostsRequest struct {
+	ToUserID string `json:"to_user_id" validate:"required,uuid"`
+}
+
+type TransferPostsResultDTO struct {
+	Transferred int `json:"transferred"`
+}
@@ -0,0 +1,22 @@
+package postDTOs
+
+import "time"
+
+// PostViewerDTO is the signed-in viewer's own engagement with a post
+type PostViewerDTO struct {
+	Reactions  []string `json:"reactions"`
+	Bookmarked bool     `json:"bookmarked"`
+}
+
+// ReactionSummaryDTO is returned after a viewer adds or removes a reaction
+type ReactionSummaryDTO struct {
+	PostID          int            `json:"post_id"`
+	Reactions       map[string]int `json:"reactions"`
+	ViewerReactions []string       `json:"viewer_reactions"`
+}
+
+// BookmarkDTO is a post on a user's reading list
+type BookmarkDTO struct {
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+	Post         *PostDTO  `json:"post"`
+}
@@ -0,0 +1,16 @@
+package postDTOs
+
+import "github.com/google/uuid"
+
+// PublishedPostFilter narrows a listing of published posts. Empty fields are ignored.
+type PublishedPostFilter struct {
+	TagSlug      string
+	CategorySlug string     // includes posts in all descendant categories
+	AuthorID     *uuid.UUID // matches owners and co-authors
+	// Locale keeps posts in this locale, and posts in FallbackLocale whose article
+	// has no listed translation in Locale
+	Locale         string
+	FallbackLocale string
+	Limit          int
+	Offset         int
+}
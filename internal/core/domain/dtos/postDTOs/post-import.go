@@ -0,0 +1,51 @@
+package postDTOs
+
+import "time"
+
+// ImportedPostDTO is a post read from an import file, before it is stored
+type ImportedPostDTO struct {
+	Source      string // file (and item) the post was read from, used in reports
+	Title       string
+	Slug        string
+	Content     string
+	Excerpt     *string
+	Status      string
+	PublishedAt *time.Time
+	Tags        []string
+	Categories  []string // category slugs, matched against existing categories
+}
+
+// Import conflict strategies
+const (
+	ImportConflictSkip   = "skip"   // posts whose slug is taken are not imported
+	ImportConflictRename = "rename" // posts whose slug is taken get a new unique slug
+)
+
+type ImportOptions struct {
+	DryRun     bool
+	OnConflict string
+}
+
+// Actions reported for each imported post
+const (
+	ImportActionCreate = "create"
+	ImportActionSkip   = "skip"
+)
+
+type ImportReportDTO struct {
+	DryRun  bool                   `json:"dry_run"`
+	Total   int                    `json:"total"`
+	Created int                    `json:"created"`
+	Skipped int                    `json:"skipped"`
+	Items   []*ImportItemReportDTO `json:"items"`
+}
+
+type ImportItemReportDTO struct {
+	Source   string   `json:"source"`
+	Title    string   `json:"title"`
+	Slug     string   `json:"slug"`
+	Action   string   `json:"action"`
+	PostID   *int     `json:"post_id,omitempty"`
+	Errors   []string `json:"errors,omitempty"`   // reasons the post was skipped
+	Warnings []string `json:"warnings,omitempty"` // problems that did not prevent the import
+}
@@ -0,0 +1,9 @@
+package postDTOs
+
+// PostSearchResultDTO is a post matched by a full-text search, with its rank and highlighted fragments
+type PostSearchResultDTO struct {
+	PostDTO
+	Rank           float64 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
@@ -0,0 +1,64 @@
+package postDTOs
+
+import "time"
+
+// PostSEODTO holds the optional search and social sharing overrides of a post
+type PostSEODTO struct {
+	MetaTitle       *string `json:"meta_title"`
+	MetaDescription *string `json:"meta_description"`
+	CanonicalURL    *string `json:"canonical_url"`
+	OGImageURL      *string `json:"og_image_url"`
+}
+
+// UpdatePostSEORequest replaces all SEO fields of a post. Omitted or blank fields are cleared.
+type UpdatePostSEORequest struct {
+	MetaTitle       *string `json:"meta_title" validate:"omitempty,max=100"`
+	MetaDescription *string `json:"meta_description" validate:"omitempty,max=300"`
+	CanonicalURL    *string `json:"canonical_url" validate:"omitempty,url,max=2048"`
+	OGImageURL      *string `json:"og_image_url" validate:"omitempty,url,max=2048"`
+}
+
+// PostMetaDTO is the resolved metadata a page needs to render a post's head tags
+type PostMetaDTO struct {
+	Title        string         `json:"title"`
+	Description  string         `json:"description"`
+	CanonicalURL string         `json:"canonical_url"`
+	Alternates   []*HreflangDTO `json:"alternates,omitempty"` // translations of the post, for hreflang links
+	OpenGraph    OpenGraphDTO   `json:"open_graph"`
+	Twitter      TwitterCardDTO `json:"twitter"`
+}
+
+// HreflangDTO is an alternate language version of a page. Hreflang is a locale, or
+// "x-default" for the version shown when no locale matches.
+type HreflangDTO struct {
+	Hreflang string `json:"hreflang"`
+	URL      string `json:"href"`
+}
+
+type OpenGraphDTO struct {
+	Type          string     `json:"type"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	URL           string     `json:"url"`
+	SiteName      string     `json:"site_name"`
+	Locale        string     `json:"locale,omitempty"`
+	Alternates    []string   `json:"locale:alternate,omitempty"`
+	Image         *string    `json:"image,omitempty"`
+	PublishedTime *time.Time `json:"article:published_time,omitempty"`
+	ModifiedTime  time.Time  `json:"article:modified_time"`
+	Section       string     `json:"article:section,omitempty"`
+	Tags          []string   `json:"article:tag,omitempty"`
+}
+
+type TwitterCardDTO struct {
+	Card        string  `json:"card"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Image       *string `json:"image,omitempty"`
+}
+
+// SitemapEntryDTO is a published post's location and last modification time
+type SitemapEntryDTO struct {
+	Slug      string
+	UpdatedAt time.Time
+}
@@ -0,0 +1,18 @@
+package postDTOs
+
+// PostSeriesDTO places a post within its series, counting published, non-private posts only
+type PostSeriesDTO struct {
+	ID       int                `json:"id"`
+	Title    string             `json:"title"`
+	Slug     string             `json:"slug"`
+	Position int                `json:"position"`
+	Total    int                `json:"total"`
+	Previous *SeriesPostLinkDTO `json:"previous,omitempty"`
+	Next     *SeriesPostLinkDTO `json:"next,omitempty"`
+}
+
+type SeriesPostLinkDTO struct {
+	ID    int     `json:"id"`
+	Title string  `json:"title"`
+	Slug  *string `json:"slug,omitempty"`
+}
@@ -0,0 +1,16 @@
+package postDTOs
+
+// PostTranslationDTO links to a translation of a post
+type PostTranslationDTO struct {
+	ID     int    `json:"id"`
+	Locale string `json:"locale"`
+	Title  string `json:"title"`
+	Slug   string `json:"slug"`
+}
+
+// UpdatePostTranslationRequest sets a post's locale and which article it translates.
+// Without TranslationOf the post is detached from its translations.
+type UpdatePostTranslationRequest struct {
+	Locale        string `json:"locale" validate:"required,max=35"`
+	TranslationOf *int   `json:"translation_of" validate:"omitempty,gt=0"`
+}
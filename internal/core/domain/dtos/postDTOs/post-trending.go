@@ -0,0 +1,15 @@
+package postDTOs
+
+// TrendingWeights is how much a single view, reaction or approved comment adds to a post's trending score
+type TrendingWeights struct {
+	View     float64
+	Reaction float64
+	Comment  float64
+}
+
+// TrendingScoreDTO is a post's decayed activity score and the categories it is listed under
+type TrendingScoreDTO struct {
+	PostID      int
+	CategoryIDs []int
+	Score       float64
+}
@@ -1,17 +1,71 @@
 package postDTOs
 
-import "time"
+import (
+	"app05/internal/core/domain/dtos/taxonomyDTOs"
+	"time"
+)
 
 type PostDTO struct {
-	ID          int        `json:"id"`
-	UserID      string     `json:"user_id"`
-	Title       string     `json:"title"`
-	Content     string     `json:"content"`
-	Excerpt     *string    `json:"excerpt,omitempty"`
-	Status      string     `json:"status"`
-	ViewCount   int        `json:"view_count"`
-	PublishedAt *time.Time `json:"published_at,omitempty"`
-	Slug        *string    `json:"slug,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID                 int                     `json:"id"`
+	UserID             string                  `json:"user_id"` // the owner
+	Authors            []*PostAuthorDTO        `json:"authors"` // owner first, then co-authors in the order they were added
+	Title              string                  `json:"title"`
+	Content            string                  `json:"content"`      // Markdown source
+	ContentHTML        string                  `json:"content_html"` // sanitized HTML rendered from Content
+	Excerpt            *string                 `json:"excerpt,omitempty"`
+	ReadingTime        int                     `json:"reading_time_minutes"`
+	TOC                []*TOCEntryDTO          `json:"table_of_contents"`
+	Status             string                  `json:"status"`
+	Visibility         string                  `json:"visibility"`
+	VisibleRoles       []string                `json:"visible_roles,omitempty"` // roles allowed to read posts with "roles" visibility
+	Locked             bool                    `json:"locked"`                  // content withheld from the caller, only the excerpt is shown
+	Locale             string                  `json:"locale"`
+	TranslationGroupID string                  `json:"translation_group_id"`   // shared by translations of the same article
+	Translations       []*PostTranslationDTO   `json:"translations,omitempty"` // set on single post requests for translated posts
+	ViewCount          int                     `json:"view_count"`
+	CommentCount       int                     `json:"comment_count"`
+	Reactions          map[string]int          `json:"reactions"` // count per reaction type
+	PublishedAt        *time.Time              `json:"published_at,omitempty"`
+	Slug               *string                 `json:"slug,omitempty"`
+	Categories         []*taxonomyDTOs.TermDTO `json:"categories"`
+	Tags               []*taxonomyDTOs.TermDTO `json:"tags"`
+	SEO                PostSEODTO              `json:"seo"`
+	Series             *PostSeriesDTO          `json:"series,omitempty"` // set on single post requests for posts in a series
+	Viewer             *PostViewerDTO          `json:"viewer,omitempty"` // set on a signed-in viewer's single post requests
+	CreatedAt          time.Time               `json:"created_at"`
+	UpdatedAt          time.Time               `json:"updated_at"`
+}
+
+// TOCEntryDTO is a heading in a post's table of contents. ID is the heading's anchor in ContentHTML.
+type TOCEntryDTO struct {
+	ID       string         `json:"id"`
+	Text     string         `json:"text"`
+	Level    int            `json:"level"`
+	Children []*TOCEntryDTO `json:"children,omitempty"`
+}
+
+// CreatePostRequest creates a draft. Locale defaults to the site's default locale;
+// TranslationOf makes the post a translation of an existing post.
+type CreatePostRequest struct {
+	Title         string  `json:"title" validate:"required,max=255"`
+	Content       string  `json:"content" validate:"required"`
+	Excerpt       *string `json:"excerpt" validate:"omitempty,max=500"`
+	Locale        string  `json:"locale" validate:"omitempty,max=35"`
+	TranslationOf *int    `json:"translation_of" validate:"omitempty,gt=0"`
+}
+
+type UpdatePostRequest struct {
+	Title   string  `json:"title" validate:"required,max=255"`
+	Content string  `json:"content" validate:"required"`
+	Excerpt *string `json:"excerpt" validate:"omitempty,max=500"`
+}
+
+type UpdatePostStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=draft published archived"`
+}
+
+// UpdatePostVisibilityRequest sets who may read a post. Roles is required for "roles" visibility.
+type UpdatePostVisibilityRequest struct {
+	Visibility string   `json:"visibility" validate:"required,oneof=public registered roles private"`
+	Roles      []string `json:"roles" validate:"max=4,dive,oneof=superuser admin instructor student"`
 }
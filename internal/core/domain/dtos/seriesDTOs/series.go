@@ -0,0 +1,48 @@
+package seriesDTOs
+
+import "time"
+
+type SeriesDTO struct {
+	ID          int               `json:"id"`
+	UserID      string            `json:"user_id"`
+	Title       string            `json:"title"`
+	Slug        string            `json:"slug"`
+	Description *string           `json:"description,omitempty"`
+	PostCount   int               `json:"post_count"` // published, non-private posts only
+	Posts       []*SeriesEntryDTO `json:"posts,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// SeriesEntryDTO is a post listed in a series. Position is 1-based and contiguous
+// over the listed posts, so drafts left out of a public listing leave no gaps.
+type SeriesEntryDTO struct {
+	PostID      int        `json:"post_id"`
+	Position    int        `json:"position"`
+	Title       string     `json:"title"`
+	Slug        *string    `json:"slug,omitempty"`
+	Excerpt     *string    `json:"excerpt,omitempty"`
+	Status      string     `json:"status"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+type CreateSeriesRequest struct {
+	Title       string  `json:"title" validate:"required,max=200"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+}
+
+type UpdateSeriesRequest struct {
+	Title       string  `json:"title" validate:"required,max=200"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+}
+
+// AddSeriesPostRequest adds a post at Position, shifting later posts down. Without a position the post is appended.
+type AddSeriesPostRequest struct {
+	PostID   int `json:"post_id" validate:"required,min=1"`
+	Position int `json:"position" validate:"omitempty,min=1"`
+}
+
+// ReorderSeriesRequest lists every post of the series in its new order
+type ReorderSeriesRequest struct {
+	PostIDs []int `json:"post_ids" validate:"required,min=1,max=500,dive,min=1"`
+}
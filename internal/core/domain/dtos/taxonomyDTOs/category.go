@@ -0,0 +1,31 @@
+package taxonomyDTOs
+
+import "time"
+
+type CategoryDTO struct {
+	ID          int            `json:"id"`
+	ParentID    *int           `json:"parent_id,omitempty"`
+	Name        string         `json:"name"`
+	Slug        string         `json:"slug"`
+	Description *string        `json:"description,omitempty"`
+	PostCount   int            `json:"post_count"`
+	Children    []*CategoryDTO `json:"children,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+type CreateCategoryRequest struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	ParentID    *int    `json:"parent_id" validate:"omitempty,min=1"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+}
+
+type UpdateCategoryRequest struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	ParentID    *int    `json:"parent_id" validate:"omitempty,min=1"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+}
+
+type SetPostCategoriesRequest struct {
+	CategoryIDs []int `json:"category_ids" validate:"max=10,dive,min=1"`
+}
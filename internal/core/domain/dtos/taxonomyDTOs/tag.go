@@ -0,0 +1,15 @@
+package taxonomyDTOs
+
+import "time"
+
+type TagDTO struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	PostCount int       `json:"post_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SetPostTagsRequest struct {
+	Tags []string `json:"tags" validate:"max=20,dive,required,max=50"`
+}
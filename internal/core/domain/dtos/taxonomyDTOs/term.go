@@ -0,0 +1,8 @@
+package taxonomyDTOs
+
+// TermDTO is the compact form of a tag or category embedded in other resources
+type TermDTO struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
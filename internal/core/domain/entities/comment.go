@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+type CommentStatus string
+
+const (
+	CommentStatusPending  CommentStatus = "pending"
+	CommentStatusApproved CommentStatus = "approved"
+	CommentStatusRejected CommentStatus = "rejected"
+	CommentStatusSpam     CommentStatus = "spam"
+)
+
+// CommentEditWindow is how long after posting an author may still edit a comment
+const CommentEditWindow = 15 * time.Minute
@@ -0,0 +1,93 @@
+package entities
+
+import "time"
+
+// CourseStatus is the publication state of a course, module or lesson. Content is
+// public only when it and everything it belongs to is published.
+type CourseStatus string
+
+const (
+	CourseStatusDraft     CourseStatus = "draft"
+	CourseStatusPublished CourseStatus = "published"
+)
+
+func (s CourseStatus) IsValid() bool {
+	switch s {
+	case CourseStatusDraft, CourseStatusPublished:
+		return true
+	}
+	return false
+}
+
+// Course is owned by the instructor who created it and made of ordered modules
+type Course struct {
+	ID              int          `json:"id"`
+	UserID          string       `json:"user_id"`
+	Title           string       `json:"title"`
+	Slug            string       `json:"slug"`
+	Description     *string      `json:"description,omitempty"`
+	Status          CourseStatus `json:"status"`
+	LessonCount     int          `json:"lesson_count"` // published lessons of published modules only
+	IsFree          bool         `json:"is_free"`      // students may enroll themselves
+	EnrollmentLimit *int         `json:"enrollment_limit,omitempty"`
+	EnrollmentCount int          `json:"enrollment_count"`
+	StartsAt        *time.Time   `json:"starts_at,omitempty"` // lessons open to students from
+	EndsAt          *time.Time   `json:"ends_at,omitempty"`   // self-enrollment closes at
+	Modules         []*Module    `json:"modules,omitempty"`
+	PublishedAt     *time.Time   `json:"published_at,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// EnrollmentClosed reports whether students may no longer enroll themselves at t
+func (c *Course) EnrollmentClosed(t time.Time) bool {
+	return c.EndsAt != nil && !t.Before(*c.EndsAt)
+}
+
+// Started reports whether the lessons are open to enrolled students at t
+func (c *Course) Started(t time.Time) bool {
+	return c.StartsAt == nil || !t.Before(*c.StartsAt)
+}
+
+// Module is a section of a course. Position is 1-based and contiguous over the listed
+// modules, so drafts left out of a public outline leave no gaps.
+type Module struct {
+	ID          int          `json:"id"`
+	CourseID    int          `json:"course_id"`
+	Title       string       `json:"title"`
+	Description *string      `json:"description,omitempty"`
+	Position    int          `json:"position"`
+	Status      CourseStatus `json:"status"`
+	Lessons     []*Lesson    `json:"lessons"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Lesson is a unit of a module. Content is left out of course outlines.
+type Lesson struct {
+	ID              int          `json:"id"`
+	ModuleID        int          `json:"module_id"`
+	CourseID        int          `json:"course_id"`
+	Title           string       `json:"title"`
+	Content         string       `json:"content,omitempty"`
+	DurationMinutes *int         `json:"duration_minutes,omitempty"`
+	IsRequired      bool         `json:"is_required"` // counts towards completing the course
+	Position        int          `json:"position"`
+	Status          CourseStatus `json:"status"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// Enrollment gives a user access to the lessons of a course
+type Enrollment struct {
+	CourseID    int        `json:"course_id"`
+	UserID      string     `json:"user_id"`
+	FirstName   string     `json:"first_name,omitempty"` // set on a course's enrollment list
+	LastName    string     `json:"last_name,omitempty"`
+	Email       string     `json:"email,omitempty"`
+	EnrolledBy  *string    `json:"enrolled_by,omitempty"` // the instructor or admin who enrolled the user, nil for self-enrollment
+	EnrolledAt  time.Time  `json:"enrolled_at"`
+	Progress    int        `json:"progress"`               // percent of required lessons completed
+	CompletedAt *time.Time `json:"completed_at,omitempty"` // set once all required lessons were completed
+	Course      *Course    `json:"course,omitempty"`       // set on a user's course list
+}
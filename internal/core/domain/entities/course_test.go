@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCourse_EnrollmentWindow(t *testing.T) {
+	now := time.Now()
+	before, after := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		startsAt *time.Time
+		endsAt   *time.Time
+		started  bool
+		closed   bool
+	}{
+		{name: "no dates", started: true},
+		{name: "starts later", startsAt: &after, started: false},
+		{name: "started", startsAt: &before, started: true},
+		{name: "starts now", startsAt: &now, started: true},
+		{name: "ends later", endsAt: &after, started: true},
+		{name: "ended", endsAt: &before, started: true, closed: true},
+		{name: "ends now", endsAt: &now, started: true, closed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			course := &Course{StartsAt: tt.startsAt, EndsAt: tt.endsAt}
+			if got := course.Started(now); got != tt.started {
+				t.Errorf("Started() = %v, want %v", got, tt.started)
+			}
+			if got := course.EnrollmentClosed(now); got != tt.closed {
+				t.Errorf("EnrollmentClosed() = %v, want %v", got, tt.closed)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package entities
+
+// ImageVariant is a resized copy of an uploaded image. Width variants of one content
+// type together form a srcset; the thumbnail is a square crop and is left out of it.
+type ImageVariant struct {
+	Key         string `json:"-"` // blob key
+	URL         string `json:"url"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Thumbnail   bool   `json:"thumbnail,omitempty"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size_bytes"`
+}
@@ -0,0 +1,67 @@
+package entities
+
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+func (s PostStatus) IsValid() bool {
+	switch s {
+	case PostStatusDraft, PostStatusPublished, PostStatusArchived:
+		return true
+	}
+	return false
+}
+
+// PostAuthorRole is what an author may do with a post they are credited on
+type PostAuthorRole string
+
+const (
+	PostAuthorOwner       PostAuthorRole = "owner"
+	PostAuthorEditor      PostAuthorRole = "editor"
+	PostAuthorContributor PostAuthorRole = "contributor"
+)
+
+func (r PostAuthorRole) IsValid() bool {
+	switch r {
+	case PostAuthorOwner, PostAuthorEditor, PostAuthorContributor:
+		return true
+	}
+	return false
+}
+
+// CanEdit reports whether the author may change the post's content, terms and SEO fields
+func (r PostAuthorRole) CanEdit() bool {
+	return r.IsValid()
+}
+
+// CanPublish reports whether the author may change the post's status
+func (r PostAuthorRole) CanPublish() bool {
+	return r == PostAuthorOwner || r == PostAuthorEditor
+}
+
+// CanManageAuthors reports whether the author may add and remove co-authors
+func (r PostAuthorRole) CanManageAuthors() bool {
+	return r == PostAuthorOwner
+}
+
+// PostVisibility controls who may read a post's full content
+type PostVisibility string
+
+const (
+	PostVisibilityPublic     PostVisibility = "public"
+	PostVisibilityRegistered PostVisibility = "registered" // any signed-in user
+	PostVisibilityRoles      PostVisibility = "roles"      // signed-in users with one of the post's visible roles
+	PostVisibilityPrivate    PostVisibility = "private"    // the post's authors only
+)
+
+func (v PostVisibility) IsValid() bool {
+	switch v {
+	case PostVisibilityPublic, PostVisibilityRegistered, PostVisibilityRoles, PostVisibilityPrivate:
+		return true
+	}
+	return false
+}
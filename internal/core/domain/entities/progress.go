@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+// LessonProgress is a student's progress through a lesson
+type LessonProgress struct {
+	LessonID        int        `json:"lesson_id"`
+	PositionSeconds int        `json:"position_seconds"` // last video checkpoint
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	LastViewedAt    *time.Time `json:"last_viewed_at,omitempty"`
+}
+
+// CourseProgress is a student's progress through the published lessons of a course.
+// Percent counts required lessons only.
+type CourseProgress struct {
+	CourseID         int               `json:"course_id"`
+	Percent          int               `json:"percent"`
+	RequiredLessons  int               `json:"required_lessons"`
+	CompletedLessons int               `json:"completed_lessons"` // required lessons completed
+	CompletedAt      *time.Time        `json:"completed_at,omitempty"`
+	Resume           *LessonProgress   `json:"resume,omitempty"` // the lesson to continue with, nil once all are completed
+	Lessons          []*LessonProgress `json:"lessons"`
+}
+
+// ClassProgress summarizes the progress of all students of a course
+type ClassProgress struct {
+	CourseID        int                 `json:"course_id"`
+	Enrolled        int                 `json:"enrolled"`
+	Completed       int                 `json:"completed"`
+	AverageProgress int                 `json:"average_progress"` // percent
+	Lessons         []*LessonCompletion `json:"lessons"`
+}
+
+// LessonCompletion is how many enrolled students completed a lesson
+type LessonCompletion struct {
+	LessonID   int          `json:"lesson_id"`
+	ModuleID   int          `json:"module_id"`
+	Title      string       `json:"title"`
+	Status     CourseStatus `json:"status"`
+	IsRequired bool         `json:"is_required"`
+	Completed  int          `json:"completed"`
+}
@@ -0,0 +1,29 @@
+package entities
+
+type ReactionType string
+
+const (
+	ReactionLike       ReactionType = "like"
+	ReactionInsightful ReactionType = "insightful"
+	ReactionHelpful    ReactionType = "helpful"
+	ReactionCelebrate  ReactionType = "celebrate"
+	ReactionCurious    ReactionType = "curious"
+)
+
+// ReactionTypes is the fixed set of reactions a post can receive
+var ReactionTypes = []ReactionType{
+	ReactionLike,
+	ReactionInsightful,
+	ReactionHelpful,
+	ReactionCelebrate,
+	ReactionCurious,
+}
+
+func (r ReactionType) IsValid() bool {
+	for _, reaction := range ReactionTypes {
+		if r == reaction {
+			return true
+		}
+	}
+	return false
+}
@@ -32,24 +32,25 @@ const (
 )
 
 type User struct {
-	ID                     uuid.UUID  `json:"id"`
-	Email                  string     `json:"email"`
-	HashedPassword         string     `json:"-"`
-	FirstName              string     `json:"first_name"`
-	LastName               string     `json:"last_name"`
-	ProfilePictureURL      *string    `json:"profile_picture_url"`
-	Title                  string     `json:"title"`
-	Bio                    string     `json:"bio"`
-	Role                   Role       `json:"role"`
-	Active                 bool       `json:"active"`
-	EmailVerified          bool       `json:"email_verified"`
-	SubscribedToNewsletter bool       `json:"subscribed_to_newsletter"`
-	CreatedAt              time.Time  `json:"created_at"`
-	UpdatedAt              time.Time  `json:"updated_at"`
-	LastLoginAt            *time.Time `json:"last_login_at,omitempty"`
-	CurrentSession         *Session   `json:"current_session,omitempty"`
-	PasswordResetToken     string     `json:"-"`
-	ResetTokenExpiresAt    time.Time  `json:"-"`
+	ID                     uuid.UUID       `json:"id"`
+	Email                  string          `json:"email"`
+	HashedPassword         string          `json:"-"`
+	FirstName              string          `json:"first_name"`
+	LastName               string          `json:"last_name"`
+	ProfilePictureURL      *string         `json:"profile_picture_url"`
+	ProfilePictureVariants []*ImageVariant `json:"profile_picture_variants,omitempty"` // set on profile requests once generated
+	Title                  string          `json:"title"`
+	Bio                    string          `json:"bio"`
+	Role                   Role            `json:"role"`
+	Active                 bool            `json:"active"`
+	EmailVerified          bool            `json:"email_verified"`
+	SubscribedToNewsletter bool            `json:"subscribed_to_newsletter"`
+	CreatedAt              time.Time       `json:"created_at"`
+	UpdatedAt              time.Time       `json:"updated_at"`
+	LastLoginAt            *time.Time      `json:"last_login_at,omitempty"`
+	CurrentSession         *Session        `json:"current_session,omitempty"`
+	PasswordResetToken     string          `json:"-"`
+	ResetTokenExpiresAt    time.Time       `json:"-"`
 }
 
 type Session struct {
@@ -124,3 +125,13 @@ func (r Role) String() string {
 		return "Unknown"
 	}
 }
+
+// IsStaff reports whether the role belongs to platform staff (superusers, admins and instructors)
+func (r Role) IsStaff() bool {
+	return r == RoleSuperUser || r == RoleAdmin || r == RoleInstructor
+}
+
+// IsAdmin reports whether the role has administrative privileges
+func (r Role) IsAdmin() bool {
+	return r == RoleSuperUser || r == RoleAdmin
+}
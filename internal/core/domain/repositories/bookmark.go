@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"context"
+	"github.com/google/uuid"
+)
+
+type BookmarkRepository interface {
+	// AddBookmark saves a post to the user's reading list. Saving it again has no effect.
+	AddBookmark(ctx context.Context, userID uuid.UUID, postID int) error
+	// RemoveBookmark takes a post off the user's reading list. Removing it again has no effect.
+	RemoveBookmark(ctx context.Context, userID uuid.UUID, postID int) error
+	IsBookmarked(ctx context.Context, userID uuid.UUID, postID int) (bool, error)
+	// GetUserBookmarks returns a page of the user's bookmarked published, non-private posts, most recently saved first
+	GetUserBookmarks(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*postDTOs.BookmarkDTO, int, error)
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/dtos/commentDTOs"
+	"app05/internal/core/domain/entities"
+	"context"
+	"github.com/google/uuid"
+)
+
+type CommentRepository interface {
+	CreateComment(ctx context.Context, comment *commentDTOs.CommentDTO) error
+	GetCommentByID(ctx context.Context, id int) (*commentDTOs.CommentDTO, error)
+	// GetPostThreads returns a page of approved top-level comments with their approved replies, and the total number of threads
+	GetPostThreads(ctx context.Context, postID int, limit, offset int) ([]*commentDTOs.CommentDTO, int, error)
+	// UpdateCommentBody replaces the body of a comment and sets its moderation status
+	UpdateCommentBody(ctx context.Context, id int, body string, status entities.CommentStatus) error
+	SoftDeleteComment(ctx context.Context, id int) error
+	// GetCommentsByStatus returns a page of comments in the given moderation status, oldest first
+	GetCommentsByStatus(ctx context.Context, status entities.CommentStatus, limit, offset int) ([]*commentDTOs.CommentDTO, int, error)
+	ModerateComment(ctx context.Context, id int, status entities.CommentStatus, moderatorID uuid.UUID) error
+}
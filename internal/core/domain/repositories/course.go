@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/entities"
+	"context"
+	"github.com/google/uuid"
+)
+
+type CourseRepository interface {
+	CreateCourse(ctx context.Context, course *entities.Course) error
+	// UpdateCourse saves a course's title, slug, description and status. PublishedAt is
+	// set the first time the course is published.
+	UpdateCourse(ctx context.Context, course *entities.Course) error
+	// DeleteCourse removes a course with its modules and lessons
+	DeleteCourse(ctx context.Context, id int) error
+	GetCourseByID(ctx context.Context, id int) (*entities.Course, error)
+	GetCourseBySlug(ctx context.Context, slug string) (*entities.Course, error)
+	// GetPublishedCourses returns a page of published courses, most recently published first
+	GetPublishedCourses(ctx context.Context, limit, offset int) ([]*entities.Course, int, error)
+	// GetCoursesByUser returns a page of the courses a user owns, drafts included, most recently updated first
+	GetCoursesByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Course, int, error)
+	CourseSlugExists(ctx context.Context, slug string) (bool, error)
+	// UpdateEnrollmentSettings saves whether a course is free, its enrollment limit and its dates
+	UpdateEnrollmentSettings(ctx context.Context, course *entities.Course) error
+
+	// CreateModule inserts a module at position, shifting later modules down. A position
+	// of 0 or past the end appends the module.
+	CreateModule(ctx context.Context, module *entities.Module, position int) error
+	UpdateModule(ctx context.Context, module *entities.Module) error
+	// DeleteModule removes a module with its lessons and closes the gap it leaves
+	DeleteModule(ctx context.Context, id int) error
+	GetModuleByID(ctx context.Context, id int) (*entities.Module, error)
+	// GetModules lists the modules of a course in order, optionally only the published ones
+	GetModules(ctx context.Context, courseID int, publishedOnly bool) ([]*entities.Module, error)
+	// ReorderModules sets the order of a course. moduleIDs must list every module of the course exactly once.
+	ReorderModules(ctx context.Context, courseID int, moduleIDs []int) error
+
+	// CreateLesson inserts a lesson at position, shifting later lessons down. A position
+	// of 0 or past the end appends the lesson.
+	CreateLesson(ctx context.Context, lesson *entities.Lesson, position int) error
+	UpdateLesson(ctx context.Context, lesson *entities.Lesson) error
+	// DeleteLesson removes a lesson and closes the gap it leaves
+	DeleteLesson(ctx context.Context, id int) error
+	GetLessonByID(ctx context.Context, id int) (*entities.Lesson, error)
+	// GetLessons lists the lessons of a course without their content, ordered by module and
+	// position, optionally only the published lessons of published modules
+	GetLessons(ctx context.Context, courseID int, publishedOnly bool) ([]*entities.Lesson, error)
+	// ReorderLessons sets the order of a module. lessonIDs must list every lesson of the module exactly once.
+	ReorderLessons(ctx context.Context, moduleID int, lessonIDs []int) error
+}
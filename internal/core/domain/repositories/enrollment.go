@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/entities"
+	"context"
+	"github.com/google/uuid"
+)
+
+type EnrollmentRepository interface {
+	// CreateEnrollment enrolls a user, failing with a conflict when the course is full.
+	// Enrolling a user twice keeps the first enrollment.
+	CreateEnrollment(ctx context.Context, enrollment *entities.Enrollment) error
+	DeleteEnrollment(ctx context.Context, courseID int, userID uuid.UUID) error
+	// GetEnrollment returns a user's enrollment in a course with their progress, or a not found error
+	GetEnrollment(ctx context.Context, courseID int, userID uuid.UUID) (*entities.Enrollment, error)
+	IsEnrolled(ctx context.Context, courseID int, userID uuid.UUID) (bool, error)
+	// GetCourseEnrollments returns a page of a course's students, most recently enrolled first
+	GetCourseEnrollments(ctx context.Context, courseID int, limit, offset int) ([]*entities.Enrollment, int, error)
+	// GetUserEnrollments returns a page of a user's published courses, most recently enrolled first
+	GetUserEnrollments(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Enrollment, int, error)
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/entities"
+	"context"
+	"time"
+)
+
+type ImageVariantRepository interface {
+	// CreateImageVariantSet queues an image for variant generation. Queued images are left as they are.
+	CreateImageVariantSet(ctx context.Context, sourceKey string) error
+	// ClaimImageVariantSet takes a pending image for processing unless another worker
+	// took it less than lease ago, returning the attempt number
+	ClaimImageVariantSet(ctx context.Context, sourceKey string, lease time.Duration) (int, bool, error)
+	// CompleteImageVariantSet stores the generated variants of an image
+	CompleteImageVariantSet(ctx context.Context, sourceKey string, variants []*entities.ImageVariant) error
+	// FailImageVariantSet records a failed attempt. A final failure is not retried.
+	FailImageVariantSet(ctx context.Context, sourceKey, message string, final bool) error
+	// GetImageVariants returns the variants of those images whose variants are ready, keyed by source key
+	GetImageVariants(ctx context.Context, sourceKeys []string) (map[string][]*entities.ImageVariant, error)
+	// GetPendingImageVariantSets returns pending images that no worker has claimed within lease, oldest first
+	GetPendingImageVariantSets(ctx context.Context, lease time.Duration, limit int) ([]string, error)
+	DeleteImageVariantSet(ctx context.Context, sourceKey string) error
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/dtos/mediaDTOs"
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+type MediaRepository interface {
+	// CreateMedia stores a file's record with its tags, failing when it would take the
+	// owner's storage use past quota. A negative quota is unlimited.
+	CreateMedia(ctx context.Context, media *mediaDTOs.MediaDTO, quota int64) error
+	// UpdateMedia sets the alt text and replaces the tags of a file
+	UpdateMedia(ctx context.Context, media *mediaDTOs.MediaDTO) error
+	// DeleteMedia removes a file's record unless a post references it
+	DeleteMedia(ctx context.Context, id int) error
+	GetMediaByID(ctx context.Context, id int) (*mediaDTOs.MediaDTO, error)
+	// GetMediaByUser returns a page of a user's files, newest first, optionally only those with a tag
+	GetMediaByUser(ctx context.Context, userID uuid.UUID, tag string, limit, offset int) ([]*mediaDTOs.MediaDTO, int, error)
+	// GetMediaUsage returns the total size of a user's files in bytes
+	GetMediaUsage(ctx context.Context, userID uuid.UUID) (int64, error)
+	// GetMediaPosts lists the posts referencing a file
+	GetMediaPosts(ctx context.Context, mediaID int) ([]*mediaDTOs.MediaPostDTO, error)
+	// SetPostMedia replaces the files a post references with those stored under keys, or
+	// whose key without extension is one of keys. Unknown keys are ignored.
+	SetPostMedia(ctx context.Context, postID int, keys []string) error
+	// ClaimMediaScan marks a pending file as being scanned, counting the attempt, and returns it.
+	// It reports false when the file is not pending or its last claim has not expired, as for
+	// GetPendingMediaScans.
+	ClaimMediaScan(ctx context.Context, id int, lease time.Duration) (*mediaDTOs.MediaDTO, bool, error)
+	// CompleteMediaScan records the result of a pending file's scan and the key it is stored under since
+	CompleteMediaScan(ctx context.Context, id int, status string, signature *string, storageKey string) error
+	// GetPendingMediaScans returns up to limit files awaiting a scan whose last claim expired.
+	// Claims last lease, doubled for every attempt after the first.
+	GetPendingMediaScans(ctx context.Context, lease time.Duration, limit int) ([]int, error)
+	// GetPendingMediaOutside is GetPendingMediaScans for files whose key does not start with keyPrefix
+	GetPendingMediaOutside(ctx context.Context, keyPrefix string, lease time.Duration, limit int) ([]int, error)
+	// MoveMedia records that a pending file claimed for scanning moved from fromKey to toKey.
+	// It releases the claim without counting it as an attempt.
+	MoveMedia(ctx context.Context, id int, fromKey, toKey string) error
+}
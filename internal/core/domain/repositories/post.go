@@ -3,9 +3,46 @@ package repositories
 import (
 	"app05/internal/core/domain/dtos/postDTOs"
 	"context"
+	"github.com/google/uuid"
 )
 
 type PostRepository interface {
-	// GetAllPosts retrieves all posts
-	GetAllPosts(ctx context.Context) ([]*postDTOs.PostDTO, error)
+	// GetAllPosts retrieves all published posts, plus all unpublished ones when allUnpublished
+	// is set or else those written by authorID (uuid.Nil for none)
+	GetAllPosts(ctx context.Context, allUnpublished bool, authorID uuid.UUID) ([]*postDTOs.PostDTO, error)
+	GetPostByID(ctx context.Context, id int) (*postDTOs.PostDTO, error)
+	// GetPublishedPostBySlug returns a published post whatever its visibility; callers check access
+	GetPublishedPostBySlug(ctx context.Context, slug string) (*postDTOs.PostDTO, error)
+	// GetPublishedPosts returns a page of listed (published, not private) posts matching the filter, newest first, with the total match count
+	GetPublishedPosts(ctx context.Context, filter postDTOs.PublishedPostFilter) ([]*postDTOs.PostDTO, int, error)
+	// SearchPosts runs a ranked full-text search over listed posts and returns a page of results with the total match count
+	// A non-empty locale narrows results the way PublishedPostFilter.Locale does.
+	SearchPosts(ctx context.Context, query, locale, fallbackLocale string, limit, offset int) ([]*postDTOs.PostSearchResultDTO, int, error)
+	// CreatePost stores a post and makes its user the owner
+	CreatePost(ctx context.Context, post *postDTOs.PostDTO) error
+	// ImportPost is CreatePost that also assigns tags and categories in the same transaction
+	ImportPost(ctx context.Context, post *postDTOs.PostDTO, tagIDs, categoryIDs []int) error
+	// UpdatePost saves a post's title, content, excerpt and slug
+	UpdatePost(ctx context.Context, post *postDTOs.PostDTO) error
+	// UpdatePostTranslation sets a post's locale and translation group; an empty groupID starts a new group
+	UpdatePostTranslation(ctx context.Context, id int, locale, groupID string) error
+	// GetPostTranslations returns the listed posts of a translation group
+	GetPostTranslations(ctx context.Context, groupID string) ([]*postDTOs.PostTranslationDTO, error)
+	// GetPublishedTranslation returns the published post of a translation group in a locale
+	GetPublishedTranslation(ctx context.Context, groupID, locale string) (*postDTOs.PostDTO, error)
+	// UpdatePostVisibility sets who may read a post; roles only apply to "roles" visibility
+	UpdatePostVisibility(ctx context.Context, id int, visibility string, roles []string) error
+	// UpdatePostStatus changes a post's status, setting its publication date the first time it is published
+	UpdatePostStatus(ctx context.Context, id int, status string) error
+	PostSlugExists(ctx context.Context, slug string) (bool, error)
+	// UpdatePostSEO replaces the SEO fields of a post
+	UpdatePostSEO(ctx context.Context, id int, seo postDTOs.PostSEODTO) error
+	// GetSitemapEntries returns a page of listed posts for the sitemap with the total count
+	GetSitemapEntries(ctx context.Context, limit, offset int) ([]*postDTOs.SitemapEntryDTO, int, error)
+	// GetRelatedPostIDs ranks listed posts similar to a post, boosted by the given co-view scores keyed by post id
+	GetRelatedPostIDs(ctx context.Context, postID int, coViews map[int]float64, limit int) ([]int, error)
+	// GetListedPostsByIDs returns the listed posts among ids, keeping their order
+	GetListedPostsByIDs(ctx context.Context, ids []int) ([]*postDTOs.PostDTO, error)
+	// IncrementViewCounts adds the given number of views to each post and to its views of the day, keyed by post id
+	IncrementViewCounts(ctx context.Context, counts map[int]int64) error
 }
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/entities"
+	"context"
+	"github.com/google/uuid"
+)
+
+type PostAuthorRepository interface {
+	// GetPostAuthorRole returns the user's role on a post, or an empty role if they are not one of its authors
+	GetPostAuthorRole(ctx context.Context, postID int, userID uuid.UUID) (entities.PostAuthorRole, error)
+	// SetPostAuthor adds a co-author or changes their role. The owner's role cannot be changed.
+	SetPostAuthor(ctx context.Context, postID int, userID uuid.UUID, role entities.PostAuthorRole) error
+	// RemovePostAuthor removes a co-author. The owner cannot be removed.
+	RemovePostAuthor(ctx context.Context, postID int, userID uuid.UUID) error
+	// TransferOwnedPosts makes toUserID the owner of every post owned by fromUserID and
+	// returns the number of posts transferred. fromUserID stays credited as a contributor.
+	TransferOwnedPosts(ctx context.Context, fromUserID, toUserID uuid.UUID) (int, error)
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/entities"
+	"context"
+	"github.com/google/uuid"
+)
+
+type ProgressRepository interface {
+	// SaveCheckpoint records the video position a user reached in a lesson and makes it the
+	// lesson they last viewed
+	SaveCheckpoint(ctx context.Context, userID uuid.UUID, lessonID, positionSeconds int) (*entities.LessonProgress, error)
+	// CompleteLesson marks a lesson completed. Completing it again keeps the first completion.
+	CompleteLesson(ctx context.Context, userID uuid.UUID, lessonID int) (*entities.LessonProgress, error)
+	// GetLessonProgress lists a user's progress through the lessons of a course
+	GetLessonProgress(ctx context.Context, userID uuid.UUID, courseID int) ([]*entities.LessonProgress, error)
+	// CompleteCourse marks a user's enrollment completed when they completed every required,
+	// published lesson of the course, reporting whether it did
+	CompleteCourse(ctx context.Context, courseID int, userID uuid.UUID) (bool, error)
+	// GetClassProgress summarizes the progress of the students of a course, with completion
+	// counts for every lesson, drafts included
+	GetClassProgress(ctx context.Context, courseID int) (*entities.ClassProgress, error)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/entities"
+	"context"
+	"github.com/google/uuid"
+)
+
+type ReactionRepository interface {
+	// AddReaction records a user's reaction and reports whether it was new
+	AddReaction(ctx context.Context, postID int, userID uuid.UUID, reaction entities.ReactionType) (bool, error)
+	// RemoveReaction deletes a user's reaction and reports whether it existed
+	RemoveReaction(ctx context.Context, postID int, userID uuid.UUID, reaction entities.ReactionType) (bool, error)
+	// GetReactionCounts returns the number of each reaction per post, keyed by post id. Posts without reactions are omitted.
+	GetReactionCounts(ctx context.Context, postIDs []int) (map[int]map[string]int, error)
+	GetUserReactions(ctx context.Context, postID int, userID uuid.UUID) ([]string, error)
+}
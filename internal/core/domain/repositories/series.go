@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/dtos/seriesDTOs"
+	"context"
+)
+
+type SeriesRepository interface {
+	CreateSeries(ctx context.Context, series *seriesDTOs.SeriesDTO) error
+	UpdateSeries(ctx context.Context, series *seriesDTOs.SeriesDTO) error
+	// DeleteSeries removes a series; its posts are kept
+	DeleteSeries(ctx context.Context, id int) error
+	GetSeriesByID(ctx context.Context, id int) (*seriesDTOs.SeriesDTO, error)
+	GetSeriesBySlug(ctx context.Context, slug string) (*seriesDTOs.SeriesDTO, error)
+	// GetSeriesByPostID returns the series a post belongs to, or a not found error
+	GetSeriesByPostID(ctx context.Context, postID int) (*seriesDTOs.SeriesDTO, error)
+	// GetPublishedSeries returns a page of series with at least one published, non-private post, most recently updated first
+	GetPublishedSeries(ctx context.Context, limit, offset int) ([]*seriesDTOs.SeriesDTO, int, error)
+	SeriesSlugExists(ctx context.Context, slug string) (bool, error)
+
+	// GetSeriesEntries lists the posts of a series in order, optionally only the published, non-private ones
+	GetSeriesEntries(ctx context.Context, seriesID int, publishedOnly bool) ([]*seriesDTOs.SeriesEntryDTO, error)
+	// AddSeriesPost inserts a post at position, shifting later posts down. A position of 0
+	// or past the end appends the post.
+	AddSeriesPost(ctx context.Context, seriesID, postID, position int) error
+	// RemoveSeriesPost takes a post out of a series and closes the gap it leaves
+	RemoveSeriesPost(ctx context.Context, seriesID, postID int) error
+	// ReorderSeriesPosts sets the order of a series. postIDs must list every post of the series exactly once.
+	ReorderSeriesPosts(ctx context.Context, seriesID int, postIDs []int) error
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/dtos/taxonomyDTOs"
+	"context"
+)
+
+type TaxonomyRepository interface {
+	// Categories
+	CreateCategory(ctx context.Context, category *taxonomyDTOs.CategoryDTO) error
+	UpdateCategory(ctx context.Context, category *taxonomyDTOs.CategoryDTO) error
+	// DeleteCategory removes a category and re-parents its children to the deleted category's parent
+	DeleteCategory(ctx context.Context, id int) error
+	GetCategoryByID(ctx context.Context, id int) (*taxonomyDTOs.CategoryDTO, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*taxonomyDTOs.CategoryDTO, error)
+	// GetAllCategories returns a flat list of categories with their published post counts
+	GetAllCategories(ctx context.Context) ([]*taxonomyDTOs.CategoryDTO, error)
+	CategorySlugExists(ctx context.Context, slug string) (bool, error)
+	// IsCategoryDescendant reports whether categoryID is ancestorID itself or one of its descendants
+	IsCategoryDescendant(ctx context.Context, ancestorID, categoryID int) (bool, error)
+
+	// Tags
+	CreateTag(ctx context.Context, tag *taxonomyDTOs.TagDTO) error
+	GetTagByName(ctx context.Context, name string) (*taxonomyDTOs.TagDTO, error)
+	GetTagBySlug(ctx context.Context, slug string) (*taxonomyDTOs.TagDTO, error)
+	// GetAllTags returns a page of tags ordered by published post count with the total number of tags
+	GetAllTags(ctx context.Context, limit, offset int) ([]*taxonomyDTOs.TagDTO, int, error)
+	// SearchTagsByPrefix returns tags whose name starts with prefix, most used first
+	SearchTagsByPrefix(ctx context.Context, prefix string, limit int) ([]*taxonomyDTOs.TagDTO, error)
+	TagSlugExists(ctx context.Context, slug string) (bool, error)
+
+	// Post assignments, each call replaces the existing set
+	SetPostCategories(ctx context.Context, postID int, categoryIDs []int) error
+	SetPostTags(ctx context.Context, postID int, tagIDs []int) error
+}
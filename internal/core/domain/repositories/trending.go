@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"context"
+	"time"
+)
+
+type TrendingRepository interface {
+	// GetTrendingScores sums the weighted activity of listed posts between since and until,
+	// each activity halving in weight every halfLife. Views not yet stored are passed in
+	// pendingViews, keyed by post id, and count as happening now.
+	GetTrendingScores(ctx context.Context, since, until time.Time, pendingViews map[int]int64, halfLife time.Duration, weights postDTOs.TrendingWeights) ([]*postDTOs.TrendingScoreDTO, error)
+	// PruneDailyViews deletes the daily view counts of days before the given time
+	PruneDailyViews(ctx context.Context, before time.Time) error
+}
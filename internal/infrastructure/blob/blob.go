@@ -0,0 +1,57 @@
+package blob
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/config"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// New creates the blob store selected by the configuration
+func New(ctx context.Context, cfg config.BlobConfig) (contracts.BlobStore, error) {
+	switch cfg.Driver {
+	case "local":
+		// Presigned URLs could be forged with a guessable key
+		if cfg.SigningKey == "" {
+			return nil, errors.New("BLOB_SIGNING_KEY must be set for the local blob store")
+		}
+		return NewLocalStore(cfg.Local.Root, cfg.Local.URL, cfg.SigningKey), nil
+	case "s3":
+		return NewS3Store(ctx, cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob driver %q", cfg.Driver)
+	}
+}
+
+// PublicURL returns the URL a public file is served under
+func PublicURL(baseURL, key string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/" + key
+}
+
+// KeyFromURL returns the key of a file served under baseURL, or "" for any other URL
+func KeyFromURL(baseURL, url string) string {
+	key, found := strings.CutPrefix(url, strings.TrimSuffix(baseURL, "/")+"/")
+	if !found || validateKey(key) != nil {
+		return ""
+	}
+	return key
+}
+
+// validateKey accepts clean relative slash-separated keys. Segments may not start
+// with a dot, which keeps keys out of hidden files and parent directories.
+func validateKey(key string) error {
+	if key == "" || strings.ContainsRune(key, '\\') || path.Clean(key) != key || path.IsAbs(key) {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
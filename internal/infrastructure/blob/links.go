@@ -0,0 +1,53 @@
+package blob
+
+import (
+	"app05/internal/core/application/contracts"
+	"context"
+	"strings"
+	"time"
+)
+
+// Links builds the URLs files are served under. Only files under one of the public
+// prefixes are public and linked on the public URL; any other file is private and
+// only reachable through a presigned URL that expires.
+type Links struct {
+	store          contracts.BlobStore
+	publicURL      string
+	publicPrefixes []string
+	expiry         time.Duration
+}
+
+func NewLinks(store contracts.BlobStore, publicURL string, publicPrefixes []string, expiry time.Duration) *Links {
+	return &Links{
+		store:          store,
+		publicURL:      publicURL,
+		publicPrefixes: publicPrefixes,
+		expiry:         expiry,
+	}
+}
+
+// PublicURL is the URL public files are served under
+func (l *Links) PublicURL() string {
+	return l.publicURL
+}
+
+// IsPublic reports whether a file may be served without a signature
+func (l *Links) IsPublic(key string) bool {
+	if validateKey(key) != nil {
+		return false
+	}
+	for _, prefix := range l.publicPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// URL returns the public URL of a public file, or a presigned URL for a private one
+func (l *Links) URL(ctx context.Context, key string) (string, error) {
+	if l.IsPublic(key) {
+		return PublicURL(l.publicURL, key), nil
+	}
+	return l.store.PresignGet(ctx, key, l.expiry)
+}
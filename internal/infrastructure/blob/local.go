@@ -0,0 +1,242 @@
+package blob
+
+import (
+	"app05/internal/core/application/contracts"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+	errSizeMismatch     = errors.New("blob size does not match the declared size")
+)
+
+// LocalStore keeps files in a directory on disk. Content types are derived from the
+// key's extension. Presigned URLs point at an endpoint serving Verify-ed downloads.
+type LocalStore struct {
+	root       string
+	url        string
+	signingKey []byte
+}
+
+func NewLocalStore(root, url, signingKey string) *LocalStore {
+	return &LocalStore{
+		root:       root,
+		url:        strings.TrimSuffix(url, "/"),
+		signingKey: []byte(signingKey),
+	}
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+
+	// Write through a temporary file so a partially written file is never served
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, body)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if size >= 0 && written != size {
+		return errSizeMismatch
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, *contracts.BlobInfo, error) {
+	file, err := s.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, mapFileError(err)
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	if stat.IsDir() {
+		f.Close()
+		return nil, nil, contracts.ErrBlobNotFound
+	}
+	return f, localInfo(key, stat), nil
+}
+
+// Delete removes a file. Deleting a missing file succeeds, as it does on S3.
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) Stat(ctx context.Context, key string) (*contracts.BlobInfo, error) {
+	file, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(file)
+	if err != nil {
+		return nil, mapFileError(err)
+	}
+	if stat.IsDir() {
+		return nil, contracts.ErrBlobNotFound
+	}
+	return localInfo(key, stat), nil
+}
+
+func (s *LocalStore) List(ctx context.Context, prefix string) ([]*contracts.BlobInfo, error) {
+	// Only the directory holding the prefix needs to be walked
+	dir := path.Dir(prefix)
+	if strings.HasSuffix(prefix, "/") {
+		dir = strings.TrimSuffix(prefix, "/")
+	}
+	if dir != "." && validateKey(dir) != nil {
+		return nil, ErrInvalidKey
+	}
+
+	blobs := []*contracts.BlobInfo{}
+	err := filepath.WalkDir(filepath.Join(s.root, filepath.FromSlash(dir)), func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, file)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		stat, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, localInfo(key, stat))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return blobs, nil
+}
+
+func (s *LocalStore) PresignGet(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	expiresAt := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expiresAt)
+	query.Set("signature", s.sign(key, expiresAt))
+	return s.url + "/" + (&url.URL{Path: key}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// Verify checks the signature of a request to a presigned URL
+func (s *LocalStore) Verify(key string, query url.Values) error {
+	expiresAt := query.Get("expires")
+	expires, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return ErrInvalidSignature
+	}
+
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	expected, _ := hex.DecodeString(s.sign(key, expiresAt))
+	if !hmac.Equal(signature, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *LocalStore) sign(key, expiresAt string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "%s\n%s", key, expiresAt)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path returns the file holding key, which never escapes the root directory
+func (s *LocalStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func localInfo(key string, stat fs.FileInfo) *contracts.BlobInfo {
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &contracts.BlobInfo{
+		Key:         key,
+		Size:        stat.Size(),
+		ContentType: contentType,
+		ModTime:     stat.ModTime(),
+	}
+}
+
+func mapFileError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return contracts.ErrBlobNotFound
+	}
+	return err
+}
@@ -0,0 +1,201 @@
+package blob
+
+import (
+	"app05/internal/core/application/contracts"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) (*LocalStore, string) {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "blobs")
+	return NewLocalStore(root, "http://localhost/blobs/", "secret"), root
+}
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "media/a/photo.png", strings.NewReader("image"), 5, "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	body, info, err := store.Get(ctx, "media/a/photo.png")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "image" || info.Size != 5 || info.ContentType != "image/png" {
+		t.Fatalf("Get returned %q, %+v", data, info)
+	}
+
+	if err := store.Delete(ctx, "media/a/photo.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Stat(ctx, "media/a/photo.png"); !errors.Is(err, contracts.ErrBlobNotFound) {
+		t.Fatalf("Stat after Delete: expected ErrBlobNotFound, got %v", err)
+	}
+	// Deleting a missing file succeeds
+	if err := store.Delete(ctx, "media/a/photo.png"); err != nil {
+		t.Fatalf("Delete of a missing file: %v", err)
+	}
+}
+
+func TestLocalStore_PutSizeMismatch(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "media/short.txt", strings.NewReader("abc"), 10, "text/plain"); err == nil {
+		t.Fatal("expected an error for a body shorter than its declared size")
+	}
+	// The partial file is never stored
+	if _, err := store.Stat(ctx, "media/short.txt"); !errors.Is(err, contracts.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound, got %v", err)
+	}
+}
+
+func TestLocalStore_InvalidKeys(t *testing.T) {
+	store, root := newTestStore(t)
+	ctx := context.Background()
+
+	keys := []string{
+		"",
+		"/etc/passwd",
+		"../outside.txt",
+		"media/../../outside.txt",
+		"media/./file.txt",
+		"media//file.txt",
+		"media/",
+		"media/.hidden",
+		".upload-123",
+		`media\..\outside.txt`,
+	}
+	for _, key := range keys {
+		t.Run(key, func(t *testing.T) {
+			if err := store.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Put: expected ErrInvalidKey, got %v", err)
+			}
+			if _, _, err := store.Get(ctx, key); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Get: expected ErrInvalidKey, got %v", err)
+			}
+			if err := store.Delete(ctx, key); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Delete: expected ErrInvalidKey, got %v", err)
+			}
+			if _, err := store.PresignGet(ctx, key, time.Minute); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("PresignGet: expected ErrInvalidKey, got %v", err)
+			}
+		})
+	}
+
+	// Nothing was written outside the root
+	entries, err := os.ReadDir(filepath.Dir(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != filepath.Base(root) {
+			t.Errorf("unexpected file %q outside the store's root", entry.Name())
+		}
+	}
+}
+
+func TestLocalStore_ListSkipsTemporaryFiles(t *testing.T) {
+	store, root := newTestStore(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"media/a.txt", "media/b/c.txt", "other/d.txt"} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err != nil {
+			t.Fatalf("Put %s: %v", key, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "media", ".upload-1"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	blobs, err := store.List(ctx, "media/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	got := map[string]bool{}
+	for _, blob := range blobs {
+		got[blob.Key] = true
+	}
+	if len(got) != 2 || !got["media/a.txt"] || !got["media/b/c.txt"] {
+		t.Fatalf("List returned %v", got)
+	}
+
+	if _, err := store.List(ctx, "../"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("List outside the root: expected ErrInvalidKey, got %v", err)
+	}
+}
+
+func TestLocalStore_SignAndVerify(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	signed, err := store.PresignGet(ctx, "media/a b.png", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet: %v", err)
+	}
+	if !strings.HasPrefix(signed, "http://localhost/blobs/media/a%20b.png?") {
+		t.Fatalf("unexpected URL %q", signed)
+	}
+	query := parseQuery(t, signed)
+
+	expired, err := store.PresignGet(ctx, "media/a b.png", -time.Second)
+	if err != nil {
+		t.Fatalf("PresignGet: %v", err)
+	}
+
+	// Extending the expiry invalidates the signature
+	extended := url.Values{"signature": query["signature"]}
+	extended.Set("expires", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+
+	other := NewLocalStore(t.TempDir(), "http://localhost/blobs", "other secret")
+
+	tests := []struct {
+		name  string
+		store *LocalStore
+		key   string
+		query url.Values
+		valid bool
+	}{
+		{name: "valid", store: store, key: "media/a b.png", query: query, valid: true},
+		{name: "another key", store: store, key: "media/other.png", query: query},
+		{name: "another signing key", store: other, key: "media/a b.png", query: query},
+		{name: "expired", store: store, key: "media/a b.png", query: parseQuery(t, expired)},
+		{name: "extended expiry", store: store, key: "media/a b.png", query: extended},
+		{name: "malformed signature", store: store, key: "media/a b.png", query: url.Values{"expires": query["expires"], "signature": {"not hex"}}},
+		{name: "no expiry", store: store, key: "media/a b.png", query: url.Values{"signature": query["signature"]}},
+		{name: "no query", store: store, key: "media/a b.png", query: url.Values{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.store.Verify(tt.key, tt.query)
+			if tt.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func parseQuery(t *testing.T, signed string) url.Values {
+	t.Helper()
+	parsed, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("invalid URL %q: %v", signed, err)
+	}
+	return parsed.Query()
+}
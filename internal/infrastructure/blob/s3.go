@@ -0,0 +1,274 @@
+package blob
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/config"
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// Larger objects must be uploaded in parts
+	s3MaxPutSize = 5 << 30
+	// Parts are at least this large; an upload has at most s3MaxParts of them
+	s3MinPartSize = 64 << 20
+	s3MaxParts    = 10000
+)
+
+// S3Store keeps files in a bucket of AWS S3 or any S3-compatible service
+type S3Store struct {
+	client    *s3.Client
+	presigner *s3.PresignClient
+	bucket    string
+}
+
+func NewS3Store(ctx context.Context, cfg config.S3BlobConfig) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("an S3 bucket is required")
+	}
+
+	options := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(cfg.Region)}
+	if cfg.AccessKeyID != "" {
+		options = append(options, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+		o.UsePathStyle = cfg.UsePathStyle
+		// Not every S3-compatible service supports the newer default checksums
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+	})
+
+	return &S3Store{
+		client:    client,
+		presigner: s3.NewPresignClient(client),
+		bucket:    cfg.Bucket,
+	}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	// Requests are signed over the payload, which needs a body that can be read twice
+	seeker, ok := body.(io.ReadSeeker)
+	if !ok {
+		tmp, err := os.CreateTemp("", "blob-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+
+		written, err := io.Copy(tmp, body)
+		if err != nil {
+			return err
+		}
+		if size >= 0 && written != size {
+			return errSizeMismatch
+		}
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		seeker, size = tmp, written
+	}
+
+	if size > s3MaxPutSize {
+		file, ok := seeker.(io.ReaderAt)
+		if !ok {
+			return errors.New("objects larger than 5GB need a body that supports ReadAt")
+		}
+		return s.putMultipart(ctx, key, file, size, contentType)
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   seeker,
+	}
+	if size >= 0 {
+		input.ContentLength = aws.Int64(size)
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	_, err := s.client.PutObject(ctx, input)
+	return err
+}
+
+// putMultipart uploads an object in parts read from body, aborting the upload on failure
+func (s *S3Store) putMultipart(ctx context.Context, key string, body io.ReaderAt, size int64, contentType string) error {
+	input := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	created, err := s.client.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	err = s.uploadParts(ctx, key, created.UploadId, body, size)
+	if err != nil {
+		_, abortErr := s.client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(s.bucket),
+			Key:      aws.String(key),
+			UploadId: created.UploadId,
+		})
+		return errors.Join(err, abortErr)
+	}
+	return nil
+}
+
+func (s *S3Store) uploadParts(ctx context.Context, key string, uploadID *string, body io.ReaderAt, size int64) error {
+	partSize := max(s3MinPartSize, (size+s3MaxParts-1)/s3MaxParts)
+
+	parts := []types.CompletedPart{}
+	for number, offset := int32(1), int64(0); offset < size; number, offset = number+1, offset+partSize {
+		length := min(partSize, size-offset)
+		uploaded, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:        aws.String(s.bucket),
+			Key:           aws.String(key),
+			UploadId:      uploadID,
+			PartNumber:    aws.Int32(number),
+			Body:          io.NewSectionReader(body, offset, length),
+			ContentLength: aws.Int64(length),
+		})
+		if err != nil {
+			return err
+		}
+		parts = append(parts, types.CompletedPart{ETag: uploaded.ETag, PartNumber: aws.Int32(number)})
+	}
+
+	_, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		UploadId:        uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	return err
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, *contracts.BlobInfo, error) {
+	if err := validateKey(key); err != nil {
+		return nil, nil, err
+	}
+
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, nil, mapS3Error(err)
+	}
+	return output.Body, &contracts.BlobInfo{
+		Key:         key,
+		Size:        aws.ToInt64(output.ContentLength),
+		ContentType: aws.ToString(output.ContentType),
+		ModTime:     aws.ToTime(output.LastModified),
+	}, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (s *S3Store) Stat(ctx context.Context, key string) (*contracts.BlobInfo, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, mapS3Error(err)
+	}
+	return &contracts.BlobInfo{
+		Key:         key,
+		Size:        aws.ToInt64(output.ContentLength),
+		ContentType: aws.ToString(output.ContentType),
+		ModTime:     aws.ToTime(output.LastModified),
+	}, nil
+}
+
+// List returns the files under prefix. Listings carry no content types.
+func (s *S3Store) List(ctx context.Context, prefix string) ([]*contracts.BlobInfo, error) {
+	blobs := []*contracts.BlobInfo{}
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			blobs = append(blobs, &contracts.BlobInfo{
+				Key:     aws.ToString(object.Key),
+				Size:    aws.ToInt64(object.Size),
+				ModTime: aws.ToTime(object.LastModified),
+			})
+		}
+	}
+	return blobs, nil
+}
+
+func (s *S3Store) PresignGet(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	request, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+	return request.URL, nil
+}
+
+// mapS3Error turns missing object errors into contracts.ErrBlobNotFound. HEAD
+// responses have no body, so only the status-derived code is available for them.
+func mapS3Error(err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NoSuchKey", "NotFound":
+			return contracts.ErrBlobNotFound
+		}
+	}
+	return err
+}
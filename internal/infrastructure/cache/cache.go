@@ -35,6 +35,11 @@ func NewSessionCache(redisURL string, logger contracts.Logger) (*SessionCache, e
 	return &SessionCache{client: client, logger: logger}, nil
 }
 
+// Client exposes the underlying Redis client so other caches can share its connection pool
+func (c *SessionCache) Client() *redis.Client {
+	return c.client
+}
+
 // StoreSession stores a session in the cache
 func (c *SessionCache) StoreSession(ctx context.Context, session *entities.Session) error {
 	sessionJSON, err := json.Marshal(session)
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+const (
+	// Views of posts this recent in a visitor's history count as co-views
+	coViewHistorySize = 10
+	// Only the strongest co-views of each post are kept
+	maxCoViewsPerPost = 100
+)
+
+// CoViewTracker records which posts are read by the same visitors. Each visitor's
+// recently viewed posts are kept in a short list; a new view adds one co-view
+// between the post and every post in that list.
+type CoViewTracker struct {
+	client     *redis.Client
+	historyTTL time.Duration
+	ttl        time.Duration
+}
+
+func NewCoViewTracker(client *redis.Client, historyTTL, ttl time.Duration) *CoViewTracker {
+	return &CoViewTracker{
+		client:     client,
+		historyTTL: historyTTL,
+		ttl:        ttl,
+	}
+}
+
+func coViewHistoryKey(visitorID string) string {
+	return "post_coviews:history:" + visitorID
+}
+
+func coViewKey(postID string) string {
+	return "post_coviews:" + postID
+}
+
+// RecordView adds the view to the visitor's history and counts co-views with the posts already in it
+func (t *CoViewTracker) RecordView(ctx context.Context, visitorID string, postID int) error {
+	historyKey := coViewHistoryKey(visitorID)
+	post := strconv.Itoa(postID)
+
+	history, err := t.client.LRange(ctx, historyKey, 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read view history: %w", err)
+	}
+
+	_, err = t.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, other := range history {
+			if other == post {
+				continue
+			}
+			t.addCoView(ctx, pipe, other, post)
+			t.addCoView(ctx, pipe, post, other)
+		}
+
+		pipe.LRem(ctx, historyKey, 0, post)
+		pipe.LPush(ctx, historyKey, post)
+		pipe.LTrim(ctx, historyKey, 0, coViewHistorySize-1)
+		pipe.Expire(ctx, historyKey, t.historyTTL)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record co-views: %w", err)
+	}
+
+	return nil
+}
+
+func (t *CoViewTracker) addCoView(ctx context.Context, pipe redis.Pipeliner, postID, otherID string) {
+	key := coViewKey(postID)
+	pipe.ZIncrBy(ctx, key, 1, otherID)
+	pipe.ZRemRangeByRank(ctx, key, 0, -maxCoViewsPerPost-1)
+	pipe.Expire(ctx, key, t.ttl)
+}
+
+// TopCoViews returns the posts most often read together with the post, keyed by post id
+func (t *CoViewTracker) TopCoViews(ctx context.Context, postID, limit int) (map[int]float64, error) {
+	members, err := t.client.ZRevRangeWithScores(ctx, coViewKey(strconv.Itoa(postID)), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	coViews := make(map[int]float64, len(members))
+	for _, member := range members {
+		id, err := strconv.Atoi(fmt.Sprint(member.Member))
+		if err != nil {
+			continue
+		}
+		coViews[id] = member.Score
+	}
+
+	return coViews, nil
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"app05/internal/infrastructure/feeds"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// FeedCache stores encoded feeds so repeated polling by feed readers does not hit the database
+type FeedCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewFeedCache(client *redis.Client, ttl time.Duration) *FeedCache {
+	return &FeedCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func feedKey(key string) string {
+	return "feed:" + key
+}
+
+// Get returns the cached document, or nil when there is none
+func (c *FeedCache) Get(ctx context.Context, key string) (*feeds.Document, error) {
+	value, err := c.client.Get(ctx, feedKey(key)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var document feeds.Document
+	if err := json.Unmarshal(value, &document); err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+func (c *FeedCache) Set(ctx context.Context, key string, document *feeds.Document) error {
+	value, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feed: %w", err)
+	}
+
+	return c.client.Set(ctx, feedKey(key), value, c.ttl).Err()
+}
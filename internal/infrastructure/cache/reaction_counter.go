@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// reactionsTouchedKey holds the ids of posts whose reactions changed since the last reconciliation
+const reactionsTouchedKey = "post_reactions:touched"
+
+// cachedMarkerField is stored in every counter hash so posts without reactions are cached too
+const cachedMarkerField = "_cached"
+
+// incrementReactionScript adjusts a cached count, but only if the post's counters
+// are cached. Missing counters are loaded from Postgres on the next read instead.
+//
+// KEYS[1] counter hash, KEYS[2] touched set
+// ARGV[1] reaction, ARGV[2] delta, ARGV[3] post id
+var incrementReactionScript = redis.NewScript(`
+redis.call('SADD', KEYS[2], ARGV[3])
+if redis.call('EXISTS', KEYS[1]) == 1 then
+    return redis.call('HINCRBY', KEYS[1], ARGV[1], ARGV[2])
+end
+return nil
+`)
+
+// ReactionCounter caches per-post reaction counts in Redis hashes. Postgres stays
+// the source of truth; a reconciliation job rewrites the counters of changed posts.
+type ReactionCounter struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewReactionCounter(client *redis.Client, ttl time.Duration) *ReactionCounter {
+	return &ReactionCounter{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func reactionsKey(postID int) string {
+	return fmt.Sprintf("post_reactions:%d", postID)
+}
+
+// Get returns the cached counts of the given posts and the ids of posts that are not cached
+func (c *ReactionCounter) Get(ctx context.Context, postIDs []int) (map[int]map[string]int, []int, error) {
+	pipe := c.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(postIDs))
+	for i, postID := range postIDs {
+		cmds[i] = pipe.HGetAll(ctx, reactionsKey(postID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	counts := make(map[int]map[string]int, len(postIDs))
+	var missing []int
+	for i, postID := range postIDs {
+		fields := cmds[i].Val()
+		if _, ok := fields[cachedMarkerField]; !ok {
+			missing = append(missing, postID)
+			continue
+		}
+
+		postCounts := make(map[string]int, len(fields))
+		for reaction, value := range fields {
+			if reaction == cachedMarkerField {
+				continue
+			}
+			if count, err := strconv.Atoi(value); err == nil && count > 0 {
+				postCounts[reaction] = count
+			}
+		}
+		counts[postID] = postCounts
+	}
+
+	return counts, missing, nil
+}
+
+// Set replaces the cached counts of the given posts. Posts missing from counts are cached as having no reactions.
+func (c *ReactionCounter) Set(ctx context.Context, postIDs []int, counts map[int]map[string]int) error {
+	pipe := c.client.TxPipeline()
+	for _, postID := range postIDs {
+		key := reactionsKey(postID)
+		values := map[string]interface{}{cachedMarkerField: 1}
+		for reaction, count := range counts[postID] {
+			values[reaction] = count
+		}
+
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, values)
+		pipe.Expire(ctx, key, c.ttl)
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Increment adjusts a cached reaction count by delta and marks the post for reconciliation
+func (c *ReactionCounter) Increment(ctx context.Context, postID int, reaction string, delta int) error {
+	err := incrementReactionScript.Run(ctx, c.client,
+		[]string{reactionsKey(postID), reactionsTouchedKey},
+		reaction, delta, postID,
+	).Err()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to update reaction count: %w", err)
+	}
+	return nil
+}
+
+// PopTouched removes and returns up to count ids of posts whose reactions changed
+func (c *ReactionCounter) PopTouched(ctx context.Context, count int) ([]int, error) {
+	values, err := c.client.SPopN(ctx, reactionsTouchedKey, int64(count)).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	postIDs := make([]int, 0, len(values))
+	for _, value := range values {
+		if postID, err := strconv.Atoi(value); err == nil {
+			postIDs = append(postIDs, postID)
+		}
+	}
+
+	return postIDs, nil
+}
+
+// MarkTouched queues posts for reconciliation again, used when a reconciliation attempt fails
+func (c *ReactionCounter) MarkTouched(ctx context.Context, postIDs []int) error {
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, len(postIDs))
+	for i, postID := range postIDs {
+		members[i] = postID
+	}
+	return c.client.SAdd(ctx, reactionsTouchedKey, members...).Err()
+}
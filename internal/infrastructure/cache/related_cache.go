@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// RelatedCache stores the ranked ids of each post's related posts. Posts are loaded
+// fresh for every request so status and visibility changes apply immediately.
+type RelatedCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewRelatedCache(client *redis.Client, ttl time.Duration) *RelatedCache {
+	return &RelatedCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func relatedKey(postID int) string {
+	return fmt.Sprintf("related_posts:%d", postID)
+}
+
+// Get returns the cached related post ids and whether there were any cached
+func (c *RelatedCache) Get(ctx context.Context, postID int) ([]int, bool, error) {
+	value, err := c.client.Get(ctx, relatedKey(postID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	var postIDs []int
+	if err := json.Unmarshal(value, &postIDs); err != nil {
+		return nil, false, err
+	}
+
+	return postIDs, true, nil
+}
+
+func (c *RelatedCache) Set(ctx context.Context, postID int, relatedIDs []int) error {
+	value, err := json.Marshal(relatedIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal related posts: %w", err)
+	}
+
+	return c.client.Set(ctx, relatedKey(postID), value, c.ttl).Err()
+}
+
+// Delete drops a post's cached related posts so they are ranked again on the next request
+func (c *RelatedCache) Delete(ctx context.Context, postID int) error {
+	return c.client.Del(ctx, relatedKey(postID)).Err()
+}
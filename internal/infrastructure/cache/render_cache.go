@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"app05/internal/infrastructure/markdown"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// RenderCache stores rendered Markdown keyed by a hash of its source, so a post
+// is only re-rendered when its content or the rendering pipeline changes
+type RenderCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewRenderCache(client *redis.Client, ttl time.Duration) *RenderCache {
+	return &RenderCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func renderKey(contentHash string) string {
+	return fmt.Sprintf("post_render:v%s:%s", markdown.Version, contentHash)
+}
+
+// Get returns the cached output for the content hash, or nil when there is none
+func (c *RenderCache) Get(ctx context.Context, contentHash string) (*markdown.Rendered, error) {
+	value, err := c.client.Get(ctx, renderKey(contentHash)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var rendered markdown.Rendered
+	if err := json.Unmarshal(value, &rendered); err != nil {
+		return nil, err
+	}
+
+	return &rendered, nil
+}
+
+func (c *RenderCache) Set(ctx context.Context, contentHash string, rendered *markdown.Rendered) error {
+	value, err := json.Marshal(rendered)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rendered content: %w", err)
+	}
+
+	return c.client.Set(ctx, renderKey(contentHash), value, c.ttl).Err()
+}
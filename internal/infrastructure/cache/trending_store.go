@@ -0,0 +1,265 @@
+package cache
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	trendingEpochKey      = "trending:epoch"
+	trendingPostsKey      = "trending:posts"
+	trendingCategoriesKey = "trending:categories" // names of the per-category sorted sets
+	trendingRebuildLock   = "trending:rebuild:lock"
+	trendingJournalKey    = "trending:rebuild:journal" // activity recorded while a rebuild runs
+	trendingRebuildPrefix = "trending:rebuild:"
+	trendingRebuildTTL    = 10 * time.Minute
+	trendingWriteBatch    = 500
+)
+
+// ErrTrendingRebuildRunning is returned when another rebuild holds the rebuild lock
+var ErrTrendingRebuildRunning = errors.New("trending rebuild already running")
+
+// trendingIncrScript adds activity to the leaderboards. Scores are stored relative to the
+// epoch of the last rebuild: activity at time t adds weight * 2^((t - epoch) / halfLife),
+// so later activity outweighs earlier activity as if every score decayed continuously.
+// Nothing is added before the first rebuild, which reads the activity from Postgres.
+// While a rebuild runs the activity is also journaled, to be replayed onto the new
+// leaderboards.
+//
+// KEYS[1] epoch, KEYS[2] category set names, KEYS[3] all posts, KEYS[4] rebuild lock,
+// KEYS[5] journal, KEYS[6..] the post's categories
+// ARGV[1] weight, ARGV[2] time of the activity (unix seconds), ARGV[3] half-life (seconds),
+// ARGV[4] post id, ARGV[5] journal TTL (seconds)
+var trendingIncrScript = redis.NewScript(`
+local categories = {}
+for i = 6, #KEYS do
+    categories[#categories + 1] = KEYS[i]
+end
+
+if redis.call('EXISTS', KEYS[4]) == 1 then
+    redis.call('RPUSH', KEYS[5], cjson.encode({ARGV[4], ARGV[1], ARGV[2], categories}))
+    redis.call('EXPIRE', KEYS[5], ARGV[5])
+end
+
+local epoch = tonumber(redis.call('GET', KEYS[1]))
+if not epoch then
+    return 0
+end
+
+local boost = tonumber(ARGV[1]) * 2 ^ ((tonumber(ARGV[2]) - epoch) / tonumber(ARGV[3]))
+redis.call('ZINCRBY', KEYS[3], boost, ARGV[4])
+for _, key in ipairs(categories) do
+    redis.call('ZINCRBY', key, boost, ARGV[4])
+    redis.call('SADD', KEYS[2], key)
+end
+return 1
+`)
+
+// trendingReplaceScript swaps in the leaderboards written under the rebuild prefix, then
+// replays the journaled activity that happened from the epoch on. It fails when the
+// rebuild lost its lock.
+//
+// KEYS[1] rebuild lock, KEYS[2] journal, KEYS[3] epoch, KEYS[4] category set names,
+// KEYS[5] all posts, KEYS[6..] the new category leaderboards
+// ARGV[1] lock token, ARGV[2] epoch (unix seconds), ARGV[3] half-life (seconds), ARGV[4] rebuild prefix
+var trendingReplaceScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+    return redis.error_reply('trending rebuild lock lost')
+end
+
+local boards = {}
+for i = 5, #KEYS do
+    boards[KEYS[i]] = true
+end
+for _, key in ipairs(redis.call('SMEMBERS', KEYS[4])) do
+    if not boards[key] then
+        redis.call('DEL', key)
+    end
+end
+redis.call('DEL', KEYS[4])
+
+for i = 5, #KEYS do
+    if redis.call('EXISTS', ARGV[4] .. KEYS[i]) == 1 then
+        redis.call('RENAME', ARGV[4] .. KEYS[i], KEYS[i])
+    else
+        -- Empty sorted sets are never created, so there is nothing to rename
+        redis.call('DEL', KEYS[i])
+    end
+    if i > 5 then
+        redis.call('SADD', KEYS[4], KEYS[i])
+    end
+end
+redis.call('SET', KEYS[3], ARGV[2])
+
+local epoch = tonumber(ARGV[2])
+for _, entry in ipairs(redis.call('LRANGE', KEYS[2], 0, -1)) do
+    local activity = cjson.decode(entry)
+    local at = tonumber(activity[3])
+    if at >= epoch then
+        local boost = tonumber(activity[2]) * 2 ^ ((at - epoch) / tonumber(ARGV[3]))
+        redis.call('ZINCRBY', KEYS[5], boost, activity[1])
+        for _, key in ipairs(activity[4]) do
+            redis.call('ZINCRBY', key, boost, activity[1])
+            redis.call('SADD', KEYS[4], key)
+        end
+    end
+end
+
+redis.call('DEL', KEYS[2], KEYS[1])
+return 1
+`)
+
+// trendingUnlockScript releases the rebuild lock if it is still held with the token
+//
+// KEYS[1] rebuild lock, KEYS[2] journal
+// ARGV[1] lock token
+var trendingUnlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+    return redis.call('DEL', KEYS[1], KEYS[2])
+end
+return 0
+`)
+
+// TrendingStore keeps the trending leaderboards in Redis sorted sets: one for all posts
+// and one per category. They are updated as activity happens and replaced by rebuilds.
+type TrendingStore struct {
+	client   *redis.Client
+	halfLife time.Duration
+}
+
+func NewTrendingStore(client *redis.Client, halfLife time.Duration) *TrendingStore {
+	return &TrendingStore{
+		client:   client,
+		halfLife: halfLife,
+	}
+}
+
+func trendingCategoryKey(categoryID int) string {
+	return fmt.Sprintf("trending:category:%d", categoryID)
+}
+
+// Built reports whether the leaderboards have been rebuilt at least once
+func (s *TrendingStore) Built(ctx context.Context) (bool, error) {
+	n, err := s.client.Exists(ctx, trendingEpochKey).Result()
+	return n > 0, err
+}
+
+// Add adds weighted activity that happened at the given time on a post to the overall and
+// category leaderboards. A negative weight takes activity back.
+func (s *TrendingStore) Add(ctx context.Context, postID int, categoryIDs []int, weight float64, at time.Time) error {
+	keys := []string{trendingEpochKey, trendingCategoriesKey, trendingPostsKey, trendingRebuildLock, trendingJournalKey}
+	for _, id := range categoryIDs {
+		keys = append(keys, trendingCategoryKey(id))
+	}
+
+	return trendingIncrScript.Run(ctx, s.client, keys,
+		weight, at.Unix(), s.halfLife.Seconds(), postID, int(trendingRebuildTTL.Seconds())).Err()
+}
+
+// Top returns the ids of the highest scoring posts. With categories, posts in any of
+// them are ranked together by their score; without, all posts are ranked.
+func (s *TrendingStore) Top(ctx context.Context, categoryIDs []int, limit int) ([]int, error) {
+	var members []redis.Z
+	var err error
+
+	switch len(categoryIDs) {
+	case 0:
+		members, err = s.client.ZRevRangeWithScores(ctx, trendingPostsKey, 0, int64(limit-1)).Result()
+	case 1:
+		members, err = s.client.ZRevRangeWithScores(ctx, trendingCategoryKey(categoryIDs[0]), 0, int64(limit-1)).Result()
+	default:
+		keys := make([]string, len(categoryIDs))
+		for i, id := range categoryIDs {
+			keys[i] = trendingCategoryKey(id)
+		}
+		// A post is scored once however many of the categories it is in
+		members, err = s.client.ZUnionWithScores(ctx, redis.ZStore{Keys: keys, Aggregate: "MAX"}).Result()
+		sort.SliceStable(members, func(i, j int) bool { return members[i].Score > members[j].Score })
+		if len(members) > limit {
+			members = members[:limit]
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.Atoi(fmt.Sprint(member.Member))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Lock takes the rebuild lock, so only one rebuild runs at a time, and starts journaling
+// activity for Replace. It returns the token that Replace and Unlock take.
+func (s *TrendingStore) Lock(ctx context.Context) (string, error) {
+	token := uuid.NewString()
+	locked, err := s.client.SetNX(ctx, trendingRebuildLock, token, trendingRebuildTTL).Result()
+	if err != nil {
+		return "", err
+	}
+	if !locked {
+		return "", ErrTrendingRebuildRunning
+	}
+	// A rebuild that stopped midway may have left its journal behind
+	if err := s.client.Del(ctx, trendingJournalKey).Err(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Unlock releases the rebuild lock unless it has expired and was taken by another rebuild.
+// Releasing a lock that Replace already released does nothing.
+func (s *TrendingStore) Unlock(ctx context.Context, token string) error {
+	return trendingUnlockScript.Run(ctx, s.client, []string{trendingRebuildLock, trendingJournalKey}, token).Err()
+}
+
+// Replace swaps the leaderboards for ones built from scores decayed to epoch, which must
+// be read after Lock. The new sets are written under temporary keys and swapped in at
+// once, so readers never see a partial leaderboard. Activity journaled since Lock that
+// happened from the epoch on is added to the new leaderboards, as the scores do not
+// include it. Replace releases the lock.
+func (s *TrendingStore) Replace(ctx context.Context, token string, epoch time.Time, scores []*postDTOs.TrendingScoreDTO) error {
+	// Group the scores by leaderboard key
+	boards := map[string][]redis.Z{trendingPostsKey: {}}
+	for _, score := range scores {
+		member := redis.Z{Score: score.Score, Member: score.PostID}
+		boards[trendingPostsKey] = append(boards[trendingPostsKey], member)
+		for _, id := range score.CategoryIDs {
+			key := trendingCategoryKey(id)
+			boards[key] = append(boards[key], member)
+		}
+	}
+
+	keys := []string{trendingRebuildLock, trendingJournalKey, trendingEpochKey, trendingCategoriesKey, trendingPostsKey}
+	for key, members := range boards {
+		if key != trendingPostsKey {
+			keys = append(keys, key)
+		}
+
+		tempKey := trendingRebuildPrefix + key
+		if err := s.client.Del(ctx, tempKey).Err(); err != nil {
+			return err
+		}
+		for start := 0; start < len(members); start += trendingWriteBatch {
+			end := min(start+trendingWriteBatch, len(members))
+			if err := s.client.ZAdd(ctx, tempKey, members[start:end]...).Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return trendingReplaceScript.Run(ctx, s.client, keys,
+		token, epoch.Unix(), s.halfLife.Seconds(), trendingRebuildPrefix).Err()
+}
@@ -0,0 +1,223 @@
+package cache
+
+import (
+	"app05/internal/core/domain/dtos/mediaDTOs"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// advanceUploadScript appends a chunk to an upload, but only if the upload is still at
+// the offset the chunk was written at. Progress extends the upload's expiry.
+//
+// KEYS[1] upload hash, KEYS[2] chunk list, KEYS[3] the user's open uploads
+// ARGV[1] expected offset, ARGV[2] new offset, ARGV[3] chunk key, ARGV[4] TTL in seconds, ARGV[5] new expiry
+var advanceUploadScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'offset') ~= ARGV[1] then
+    return 0
+end
+redis.call('HSET', KEYS[1], 'offset', ARGV[2], 'expires_at', ARGV[5])
+redis.call('RPUSH', KEYS[2], ARGV[3])
+redis.call('EXPIRE', KEYS[1], ARGV[4])
+redis.call('EXPIRE', KEYS[2], ARGV[4])
+redis.call('EXPIRE', KEYS[3], ARGV[4])
+return 1
+`)
+
+// UploadStore tracks resumable uploads until they are completed and expire. Uploads
+// without progress for the TTL are dropped.
+type UploadStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewUploadStore(client *redis.Client, ttl time.Duration) *UploadStore {
+	return &UploadStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func uploadKey(id string) string {
+	return "upload:" + id
+}
+
+func uploadChunksKey(id string) string {
+	return "upload:" + id + ":chunks"
+}
+
+func uploadCompletionKey(id string) string {
+	return "upload:" + id + ":completing"
+}
+
+// uploadUserKey holds the lengths of a user's open uploads by upload id
+func uploadUserKey(userID string) string {
+	return "upload:user:" + userID
+}
+
+// uploadFile is the part of an upload that does not change while it is uploaded
+type uploadFile struct {
+	UserID   string                       `json:"user_id"`
+	Length   int64                        `json:"length"`
+	FileName string                       `json:"file_name"`
+	Media    mediaDTOs.CreateMediaRequest `json:"media"`
+}
+
+// Create stores a new upload, setting its expiry, and reserves its length for the user
+func (s *UploadStore) Create(ctx context.Context, upload *mediaDTOs.ResumableUpload) error {
+	file, err := json.Marshal(uploadFile{
+		UserID:   upload.UserID,
+		Length:   upload.Length,
+		FileName: upload.FileName,
+		Media:    upload.Media,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal upload: %w", err)
+	}
+
+	upload.ExpiresAt = time.Now().Add(s.ttl)
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, uploadKey(upload.ID),
+		"file", file,
+		"offset", upload.Offset,
+		"expires_at", upload.ExpiresAt.Unix(),
+	)
+	pipe.Expire(ctx, uploadKey(upload.ID), s.ttl)
+	pipe.HSet(ctx, uploadUserKey(upload.UserID), upload.ID, upload.Length)
+	pipe.Expire(ctx, uploadUserKey(upload.UserID), s.ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Reserved returns how many uploads the user has open and their total length. Uploads
+// that expired are forgotten.
+func (s *UploadStore) Reserved(ctx context.Context, userID string) (int, int64, error) {
+	lengths, err := s.client.HGetAll(ctx, uploadUserKey(userID)).Result()
+	if err != nil || len(lengths) == 0 {
+		return 0, 0, err
+	}
+
+	ids := make([]string, 0, len(lengths))
+	pipe := s.client.Pipeline()
+	exists := make([]*redis.IntCmd, 0, len(lengths))
+	for id := range lengths {
+		ids = append(ids, id)
+		exists = append(exists, pipe.Exists(ctx, uploadKey(id)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, err
+	}
+
+	count, total := 0, int64(0)
+	expired := []string{}
+	for i, id := range ids {
+		if exists[i].Val() == 0 {
+			expired = append(expired, id)
+			continue
+		}
+		length, _ := strconv.ParseInt(lengths[id], 10, 64)
+		count++
+		total += length
+	}
+	if len(expired) > 0 {
+		if err := s.client.HDel(ctx, uploadUserKey(userID), expired...).Err(); err != nil {
+			return 0, 0, err
+		}
+	}
+	return count, total, nil
+}
+
+// Get returns an upload, or nil when there is none with the id or it expired
+func (s *UploadStore) Get(ctx context.Context, id string) (*mediaDTOs.ResumableUpload, error) {
+	pipe := s.client.Pipeline()
+	fields := pipe.HGetAll(ctx, uploadKey(id))
+	chunks := pipe.LRange(ctx, uploadChunksKey(id), 0, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	values := fields.Val()
+	if values["file"] == "" {
+		return nil, nil
+	}
+
+	var file uploadFile
+	if err := json.Unmarshal([]byte(values["file"]), &file); err != nil {
+		return nil, err
+	}
+	upload := &mediaDTOs.ResumableUpload{
+		ID:       id,
+		UserID:   file.UserID,
+		Length:   file.Length,
+		FileName: file.FileName,
+		Media:    file.Media,
+		Chunks:   chunks.Val(),
+	}
+	upload.Offset, _ = strconv.ParseInt(values["offset"], 10, 64)
+	upload.MediaID, _ = strconv.Atoi(values["media_id"])
+	expiresAt, _ := strconv.ParseInt(values["expires_at"], 10, 64)
+	upload.ExpiresAt = time.Unix(expiresAt, 0)
+	return upload, nil
+}
+
+// Exists reports whether an upload is still tracked
+func (s *UploadStore) Exists(ctx context.Context, id string) (bool, error) {
+	count, err := s.client.Exists(ctx, uploadKey(id)).Result()
+	return count > 0, err
+}
+
+// Advance records a chunk written at the upload's offset, moving it size bytes further.
+// It reports false when the upload is no longer at that offset, e.g. after a concurrent
+// request.
+func (s *UploadStore) Advance(ctx context.Context, upload *mediaDTOs.ResumableUpload, chunkKey string, size int64) (bool, error) {
+	expiresAt := time.Now().Add(s.ttl)
+	advanced, err := advanceUploadScript.Run(ctx, s.client,
+		[]string{uploadKey(upload.ID), uploadChunksKey(upload.ID), uploadUserKey(upload.UserID)},
+		upload.Offset, upload.Offset+size, chunkKey, int(s.ttl.Seconds()), expiresAt.Unix(),
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to advance upload: %w", err)
+	}
+	if advanced == 0 {
+		return false, nil
+	}
+
+	upload.Offset += size
+	upload.Chunks = append(upload.Chunks, chunkKey)
+	upload.ExpiresAt = expiresAt
+	return true, nil
+}
+
+// ClaimCompletion reports whether the caller may add the upload's file to the media
+// library, which only one request at a time may do. The claim lapses after timeout in
+// case its holder never releases it.
+func (s *UploadStore) ClaimCompletion(ctx context.Context, id string, timeout time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, uploadCompletionKey(id), 1, timeout).Result()
+}
+
+// ReleaseCompletion allows another request to complete the upload after a failure
+func (s *UploadStore) ReleaseCompletion(ctx context.Context, id string) error {
+	return s.client.Del(ctx, uploadCompletionKey(id)).Err()
+}
+
+// Complete records the media the upload became and releases its reserved length. The
+// upload is kept until it expires so clients can still look up the result.
+func (s *UploadStore) Complete(ctx context.Context, upload *mediaDTOs.ResumableUpload, mediaID int) error {
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, uploadKey(upload.ID), "media_id", mediaID)
+	pipe.Del(ctx, uploadChunksKey(upload.ID))
+	pipe.HDel(ctx, uploadUserKey(upload.UserID), upload.ID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *UploadStore) Delete(ctx context.Context, upload *mediaDTOs.ResumableUpload) error {
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, uploadKey(upload.ID), uploadChunksKey(upload.ID), uploadCompletionKey(upload.ID))
+	pipe.HDel(ctx, uploadUserKey(upload.UserID), upload.ID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
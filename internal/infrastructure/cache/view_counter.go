@@ -0,0 +1,220 @@
+package cache
+
+import (
+	"app05/internal/core/application/contracts"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+const (
+	viewBufferKey         = "post_view_buffer"
+	viewBufferFlushingKey = "post_view_buffer:flushing"
+	viewFlushLock         = "post_view_buffer:lock"
+	viewFlushLockTTL      = 10 * time.Minute
+)
+
+// ErrViewFlushRunning is returned when another flush holds the flush lock
+var ErrViewFlushRunning = errors.New("view flush already running")
+
+// recordViewScript adds the visitor to the post's viewer set for the current
+// window and, only if they were not in it yet, buffers one view for the post.
+//
+// KEYS[1] viewer set, KEYS[2] view buffer
+// ARGV[1] visitor id, ARGV[2] set TTL in seconds, ARGV[3] post id
+var recordViewScript = redis.NewScript(`
+local added = redis.call('SADD', KEYS[1], ARGV[1])
+if added == 1 then
+    redis.call('EXPIRE', KEYS[1], ARGV[2])
+    redis.call('HINCRBY', KEYS[2], ARGV[3], 1)
+end
+return added
+`)
+
+// drainBufferScript moves the view buffer aside as the flushing snapshot, unless a
+// snapshot is still waiting to be acknowledged or no views were buffered.
+//
+// KEYS[1] view buffer, KEYS[2] flushing snapshot
+var drainBufferScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 0 and redis.call('EXISTS', KEYS[1]) == 1 then
+    redis.call('RENAME', KEYS[1], KEYS[2])
+end
+return 0
+`)
+
+// ackDrainScript discards the flushing snapshot and releases the flush lock, if the lock
+// is still held with the token. It returns 0 when the lock was lost.
+//
+// KEYS[1] flush lock, KEYS[2] flushing snapshot
+// ARGV[1] lock token
+var ackDrainScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+    return 0
+end
+redis.call('DEL', KEYS[2], KEYS[1])
+return 1
+`)
+
+// unlockFlushScript releases the flush lock if it is still held with the token
+//
+// KEYS[1] flush lock
+// ARGV[1] lock token
+var unlockFlushScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+    return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// ViewCounter de-duplicates post views per visitor and buffers the resulting
+// increments in Redis until a flush job writes them to Postgres
+type ViewCounter struct {
+	client *redis.Client
+	logger contracts.Logger
+	window time.Duration
+}
+
+// NewViewCounter creates a counter that de-duplicates views within window, counted in
+// whole seconds and at least one
+func NewViewCounter(client *redis.Client, logger contracts.Logger, window time.Duration) *ViewCounter {
+	return &ViewCounter{
+		client: client,
+		logger: logger,
+		window: max(window.Truncate(time.Second), time.Second),
+	}
+}
+
+// RecordView counts a view of the post unless the visitor already viewed it in
+// the current window. It reports whether the view was counted.
+func (v *ViewCounter) RecordView(ctx context.Context, postID int, visitorID string) (bool, error) {
+	bucket := time.Now().Unix() / int64(v.window.Seconds())
+	viewersKey := fmt.Sprintf("post_viewers:%d:%d", postID, bucket)
+
+	added, err := recordViewScript.Run(ctx, v.client,
+		[]string{viewersKey, viewBufferKey},
+		visitorID, int(v.window.Seconds()), postID,
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to record view: %w", err)
+	}
+
+	return added == 1, nil
+}
+
+// PendingViews returns the views recorded for a post that have not been flushed to the database yet
+func (v *ViewCounter) PendingViews(ctx context.Context, postID int) (int64, error) {
+	field := strconv.Itoa(postID)
+
+	pipe := v.client.Pipeline()
+	buffered := pipe.HGet(ctx, viewBufferKey, field)
+	flushing := pipe.HGet(ctx, viewBufferFlushingKey, field)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return 0, err
+	}
+
+	var pending int64
+	for _, cmd := range []*redis.StringCmd{buffered, flushing} {
+		if count, err := cmd.Int64(); err == nil {
+			pending += count
+		}
+	}
+	return pending, nil
+}
+
+// AllPendingViews returns the views of all posts that have not been flushed to the
+// database yet, keyed by post id
+func (v *ViewCounter) AllPendingViews(ctx context.Context) (map[int]int64, error) {
+	pipe := v.client.Pipeline()
+	buffered := pipe.HGetAll(ctx, viewBufferKey)
+	flushing := pipe.HGetAll(ctx, viewBufferFlushingKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	pending := map[int]int64{}
+	for _, cmd := range []*redis.MapStringStringCmd{buffered, flushing} {
+		for field, value := range cmd.Val() {
+			postID, err := strconv.Atoi(field)
+			if err != nil {
+				continue
+			}
+			count, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			pending[postID] += count
+		}
+	}
+	return pending, nil
+}
+
+// LockFlush takes the flush lock, so only one server drains the buffer at a time. It
+// returns the token that AckDrain and UnlockFlush take.
+func (v *ViewCounter) LockFlush(ctx context.Context) (string, error) {
+	token := uuid.NewString()
+	locked, err := v.client.SetNX(ctx, viewFlushLock, token, viewFlushLockTTL).Result()
+	if err != nil {
+		return "", err
+	}
+	if !locked {
+		return "", ErrViewFlushRunning
+	}
+	return token, nil
+}
+
+// UnlockFlush releases the flush lock unless it has expired and was taken by another
+// flush. Releasing a lock that AckDrain already released does nothing.
+func (v *ViewCounter) UnlockFlush(ctx context.Context, token string) error {
+	return unlockFlushScript.Run(ctx, v.client, []string{viewFlushLock}, token).Err()
+}
+
+// DrainBuffer moves the buffered view increments aside and returns them keyed by
+// post id. New views keep accumulating in a fresh buffer meanwhile. The drained
+// increments stay in Redis until AckDrain is called, so a failed flush is retried
+// by the next call instead of losing views. Callers must hold the flush lock.
+func (v *ViewCounter) DrainBuffer(ctx context.Context) (map[int]int64, error) {
+	// Only takes a new snapshot when the previous one was acknowledged
+	if err := drainBufferScript.Run(ctx, v.client, []string{viewBufferKey, viewBufferFlushingKey}).Err(); err != nil {
+		return nil, err
+	}
+
+	fields, err := v.client.HGetAll(ctx, viewBufferFlushingKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int64, len(fields))
+	for field, value := range fields {
+		postID, err := strconv.Atoi(field)
+		if err != nil {
+			v.logger.Warn("Skipping malformed view buffer entry", "field", field)
+			continue
+		}
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			v.logger.Warn("Skipping malformed view buffer entry", "field", field, "value", value)
+			continue
+		}
+		counts[postID] = count
+	}
+
+	return counts, nil
+}
+
+// AckDrain discards the snapshot returned by DrainBuffer once it has been persisted and
+// releases the flush lock. It fails when the lock expired meanwhile, as another flush
+// may then have persisted the snapshot too.
+func (v *ViewCounter) AckDrain(ctx context.Context, token string) error {
+	acked, err := ackDrainScript.Run(ctx, v.client, []string{viewFlushLock, viewBufferFlushingKey}, token).Int()
+	if err != nil {
+		return err
+	}
+	if acked == 0 {
+		return errors.New("view flush lock lost before the snapshot was acknowledged")
+	}
+	return nil
+}
@@ -27,6 +27,21 @@ type AppConfig struct {
 	Auth        AuthConfig
 	RateLimiter LimiterConfig
 	Redis       RedisConfig
+	Views       ViewConfig
+	Comments    CommentConfig
+	Content     ContentConfig
+	Site        SiteConfig
+	Feeds       FeedConfig
+	Reactions   ReactionConfig
+	Related     RelatedConfig
+	Trending    TrendingConfig
+	Blob        BlobConfig
+	Media       MediaConfig
+	Uploads     UploadConfig
+	Scanner     ScannerConfig
+
+	// Addresses or CIDR ranges of reverse proxies whose X-Forwarded-For header is trusted
+	TrustedProxies []string
 }
 
 // AuthConfig holds authentication-related configuration.
@@ -54,6 +69,124 @@ type RedisConfig struct {
 	DB       int
 }
 
+// ViewConfig controls post view de-duplication and buffering.
+type ViewConfig struct {
+	DedupWindow   time.Duration // A visitor is counted at most once per post within this window
+	FlushInterval time.Duration // How often buffered view counts are written to the database
+}
+
+// ContentConfig controls how rendered post content is cached.
+type ContentConfig struct {
+	RenderCacheTTL time.Duration
+}
+
+// SiteConfig describes the public site used when building absolute links.
+type SiteConfig struct {
+	Name        string
+	Description string
+	Language    string   // Default locale of posts and feeds
+	Locales     []string // Locales posts may be written in, including the default
+	URL         string   // Public URL of the frontend where posts are read
+	APIURL      string   // Public URL of this server
+}
+
+// FeedConfig controls the syndication feeds.
+type FeedConfig struct {
+	ItemLimit int           // Number of posts included in a feed
+	CacheTTL  time.Duration // How long an encoded feed is served from cache
+}
+
+// ReactionConfig controls the Redis cache of post reaction counts.
+type ReactionConfig struct {
+	CacheTTL          time.Duration // How long cached counts live without being read back from Postgres
+	ReconcileInterval time.Duration // How often cached counts of changed posts are rewritten from Postgres
+}
+
+// RelatedConfig controls related post recommendations.
+type RelatedConfig struct {
+	Limit           int           // Number of related posts returned for a post
+	CacheTTL        time.Duration // How long a post's ranked related posts are reused
+	CoViewWindow    time.Duration // Posts a visitor reads within this window count as read together
+	CoViewRetention time.Duration // How long co-view counts live after the last co-view
+}
+
+// TrendingConfig controls the trending posts leaderboards.
+type TrendingConfig struct {
+	Window         time.Duration // Activity older than this is dropped by the nightly rebuild
+	HalfLife       time.Duration // Activity counts half as much after this long
+	RebuildHour    int           // Hour of the day (server time) of the nightly rebuild
+	ViewWeight     float64
+	ReactionWeight float64
+	CommentWeight  float64
+}
+
+// BlobConfig selects where uploaded files are stored: "local" keeps them on disk,
+// "s3" in a bucket of any S3-compatible service. Files outside the public prefixes are
+// private and only served through presigned URLs; with S3 the bucket policy must allow
+// anonymous reads of the public prefixes only.
+type BlobConfig struct {
+	Driver          string
+	PublicURL       string        // URL that public files are served under, followed by their key
+	PublicPrefixes  []string      // Key prefixes of the files that are public
+	SigningKey      string        // Secret used to sign URLs of the local store, required by it
+	SignedURLExpiry time.Duration // How long presigned URLs of private files are valid
+	Local           LocalBlobConfig
+	S3              S3BlobConfig
+}
+
+type LocalBlobConfig struct {
+	Root string // Directory files are stored in
+	URL  string // URL the signed file endpoint is served under
+}
+
+type S3BlobConfig struct {
+	Endpoint        string // Empty for AWS, the service URL for other S3-compatible stores
+	Region          string
+	Bucket          string
+	AccessKeyID     string // Empty to use the default AWS credential chain
+	SecretAccessKey string
+	UsePathStyle    bool // Address the bucket in the path rather than the host name, as most self-hosted stores require
+}
+
+// MediaConfig limits the media library. Quotas are per user in bytes, negative for unlimited.
+type MediaConfig struct {
+	MaxFileSize     int64
+	InstructorQuota int64
+	AdminQuota      int64
+	SuperUserQuota  int64
+	VariantWorkers  int // images resized at the same time
+}
+
+// UploadConfig limits resumable uploads. Sizes are per file in bytes, negative for unlimited.
+type UploadConfig struct {
+	InstructorMaxSize int64
+	AdminMaxSize      int64
+	SuperUserMaxSize  int64
+	Expiry            time.Duration // Unfinished uploads are dropped after this long without progress
+	RequestTimeout    time.Duration // Longest a single upload request or tus chunk may take
+}
+
+// ScannerConfig selects how uploaded media is scanned for malware: "clamd" with a ClamAV
+// daemon, "none" to accept every file unscanned.
+type ScannerConfig struct {
+	Driver  string
+	Workers int // files scanned at the same time
+	Clamd   ClamdConfig
+}
+
+type ClamdConfig struct {
+	Network string        // "tcp" or "unix"
+	Address string        // host:port, or the socket path for unix
+	Timeout time.Duration // Longest a single scan may take
+	MaxSize int64         // Largest file scanned in bytes; must not exceed the daemon's StreamMaxLength
+}
+
+// CommentConfig holds the per-user limit on how fast comments can be posted.
+type CommentConfig struct {
+	RequestPerTimeFrame int
+	TimeFrame           time.Duration
+}
+
 // Database connection constants
 const (
 	maxOpenConns    = 25
@@ -79,12 +212,17 @@ func LoadConfig() *AppConfig {
 		env.GetString("POSTGRES_DB_PORT", ""),
 	)
 
+	serverHost := env.GetString("SERVER_HOST", defaultHost)
+	frontendURL := env.GetString("FRONTEND_URL", "")
+	siteLanguage := env.GetString("SITE_LANGUAGE", "en")
+	apiURL := env.GetString("SITE_API_URL", "http://"+serverHost)
+
 	return &AppConfig{
 		DatabaseURL: env.GetString("DB_URL", dbURL),
 		ServerPort:  fmt.Sprintf(":%s", env.GetString("SERVER_PORT", defaultPort)),
-		ServerHost:  env.GetString("SERVER_HOST", defaultHost),
+		ServerHost:  serverHost,
 		AppVersion:  env.GetString("APP_VERSION", defaultVersion),
-		FrontendURL: env.GetString("FRONTEND_URL", ""),
+		FrontendURL: frontendURL,
 		Env:         env.GetString("ENV", defaultEnv),
 		LogLevel:    env.GetString("LOG_LEVEL", defaultLogLevel),
 		Auth: AuthConfig{
@@ -105,7 +243,100 @@ func LoadConfig() *AppConfig {
 			Password: env.GetString("REDIS_PASSWORD", ""),
 			DB:       env.GetInt("REDIS_DB", 0),
 		},
+		Views: ViewConfig{
+			DedupWindow:   env.GetDuration("VIEW_DEDUP_WINDOW", 30*time.Minute),
+			FlushInterval: env.GetDuration("VIEW_FLUSH_INTERVAL", time.Minute),
+		},
+		Comments: CommentConfig{
+			RequestPerTimeFrame: env.GetInt("COMMENT_RATE_LIMIT_PER_TIME_FRAME", 5),
+			TimeFrame:           env.GetDuration("COMMENT_RATE_LIMIT_TIME_FRAME", time.Minute),
+		},
+		Content: ContentConfig{
+			RenderCacheTTL: env.GetDuration("CONTENT_RENDER_CACHE_TTL", 7*24*time.Hour),
+		},
+		Site: SiteConfig{
+			Name:        env.GetString("SITE_NAME", "SomoLabs"),
+			Description: env.GetString("SITE_DESCRIPTION", "Latest posts"),
+			Language:    siteLanguage,
+			Locales:     env.GetStrings("SITE_LOCALES", []string{siteLanguage}),
+			URL:         env.GetString("SITE_URL", frontendURL),
+			APIURL:      apiURL,
+		},
+		Feeds: FeedConfig{
+			ItemLimit: env.GetInt("FEED_ITEM_LIMIT", 20),
+			CacheTTL:  env.GetDuration("FEED_CACHE_TTL", 10*time.Minute),
+		},
+		Reactions: ReactionConfig{
+			CacheTTL:          env.GetDuration("REACTION_CACHE_TTL", 24*time.Hour),
+			ReconcileInterval: env.GetDuration("REACTION_RECONCILE_INTERVAL", 5*time.Minute),
+		},
+		Related: RelatedConfig{
+			Limit:           env.GetInt("RELATED_POSTS_LIMIT", 6),
+			CacheTTL:        env.GetDuration("RELATED_POSTS_CACHE_TTL", 6*time.Hour),
+			CoViewWindow:    env.GetDuration("RELATED_COVIEW_WINDOW", 24*time.Hour),
+			CoViewRetention: env.GetDuration("RELATED_COVIEW_RETENTION", 30*24*time.Hour),
+		},
+		Trending: TrendingConfig{
+			Window:         env.GetDuration("TRENDING_WINDOW", 7*24*time.Hour),
+			HalfLife:       env.GetDuration("TRENDING_HALF_LIFE", 24*time.Hour),
+			RebuildHour:    env.GetInt("TRENDING_REBUILD_HOUR", 3),
+			ViewWeight:     env.GetFloat64("TRENDING_VIEW_WEIGHT", 1),
+			ReactionWeight: env.GetFloat64("TRENDING_REACTION_WEIGHT", 3),
+			CommentWeight:  env.GetFloat64("TRENDING_COMMENT_WEIGHT", 5),
+		},
+		Blob: BlobConfig{
+			Driver:          env.GetString("BLOB_DRIVER", "local"),
+			PublicURL:       env.GetString("BLOB_PUBLIC_URL", "/uploads"),
+			PublicPrefixes:  env.GetStrings("BLOB_PUBLIC_PREFIXES", []string{"profile-pictures/", "media/"}),
+			SigningKey:      env.GetString("BLOB_SIGNING_KEY", ""),
+			SignedURLExpiry: env.GetDuration("BLOB_SIGNED_URL_EXPIRY", 15*time.Minute),
+			Local: LocalBlobConfig{
+				Root: env.GetString("BLOB_LOCAL_ROOT", "uploads"),
+				URL:  env.GetString("BLOB_LOCAL_URL", apiURL+"/blobs"),
+			},
+			S3: S3BlobConfig{
+				Endpoint:        env.GetString("BLOB_S3_ENDPOINT", ""),
+				Region:          env.GetString("BLOB_S3_REGION", "us-east-1"),
+				Bucket:          env.GetString("BLOB_S3_BUCKET", ""),
+				AccessKeyID:     env.GetString("BLOB_S3_ACCESS_KEY_ID", ""),
+				SecretAccessKey: env.GetString("BLOB_S3_SECRET_ACCESS_KEY", ""),
+				UsePathStyle:    env.GetBool("BLOB_S3_USE_PATH_STYLE", false),
+			},
+		},
+		Media: MediaConfig{
+			MaxFileSize:     megabytes(env.GetInt("MEDIA_MAX_FILE_SIZE_MB", 25)),
+			InstructorQuota: megabytes(env.GetInt("MEDIA_QUOTA_INSTRUCTOR_MB", 500)),
+			AdminQuota:      megabytes(env.GetInt("MEDIA_QUOTA_ADMIN_MB", 2048)),
+			SuperUserQuota:  megabytes(env.GetInt("MEDIA_QUOTA_SUPERUSER_MB", -1)),
+			VariantWorkers:  env.GetInt("MEDIA_VARIANT_WORKERS", 2),
+		},
+		Uploads: UploadConfig{
+			InstructorMaxSize: megabytes(env.GetInt("UPLOAD_MAX_SIZE_INSTRUCTOR_MB", 2048)),
+			AdminMaxSize:      megabytes(env.GetInt("UPLOAD_MAX_SIZE_ADMIN_MB", 5120)),
+			SuperUserMaxSize:  megabytes(env.GetInt("UPLOAD_MAX_SIZE_SUPERUSER_MB", -1)),
+			Expiry:            env.GetDuration("UPLOAD_EXPIRY", 24*time.Hour),
+			RequestTimeout:    env.GetDuration("UPLOAD_REQUEST_TIMEOUT", time.Hour),
+		},
+		Scanner: ScannerConfig{
+			Driver:  env.GetString("SCANNER_DRIVER", "none"),
+			Workers: env.GetInt("SCANNER_WORKERS", 2),
+			Clamd: ClamdConfig{
+				Network: env.GetString("CLAMD_NETWORK", "tcp"),
+				Address: env.GetString("CLAMD_ADDRESS", "localhost:3310"),
+				Timeout: env.GetDuration("CLAMD_TIMEOUT", 2*time.Minute),
+				MaxSize: megabytes(env.GetInt("CLAMD_MAX_SIZE_MB", 25)),
+			},
+		},
+		TrustedProxies: env.GetStrings("TRUSTED_PROXIES", []string{"127.0.0.1", "::1"}),
+	}
+}
+
+// megabytes converts a size in megabytes to bytes, keeping negative values as unlimited
+func megabytes(mb int) int64 {
+	if mb < 0 {
+		return -1
 	}
+	return int64(mb) << 20
 }
 
 // buildDatabaseURL constructs a PostgreSQL connection string from individual components.
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -79,3 +80,23 @@ func GetFloat64(key string, fallback float64) float64 {
 
 	return floatVal
 }
+
+// GetStrings reads a comma-separated list, dropping blank entries
+func GetStrings(key string, fallback []string) []string {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+
+	values := []string{}
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	if len(values) == 0 {
+		return fallback
+	}
+
+	return values
+}
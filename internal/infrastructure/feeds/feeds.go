@@ -0,0 +1,100 @@
+// Package feeds encodes lists of published content as RSS 2.0, Atom 1.0 and JSON Feed 1.1 documents.
+package feeds
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatRSS  Format = "rss"
+	FormatAtom Format = "atom"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat maps a feed file extension to its format
+func ParseFormat(extension string) (Format, bool) {
+	switch format := Format(extension); format {
+	case FormatRSS, FormatAtom, FormatJSON:
+		return format, true
+	}
+	return "", false
+}
+
+func (f Format) ContentType() string {
+	return f.mimeType() + "; charset=utf-8"
+}
+
+func (f Format) mimeType() string {
+	switch f {
+	case FormatRSS:
+		return "application/rss+xml"
+	case FormatAtom:
+		return "application/atom+xml"
+	default:
+		return "application/feed+json"
+	}
+}
+
+// Feed is the format independent description of a feed
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // page the feed belongs to
+	FeedURL     string // URL the feed itself is served from
+	Language    string
+	Updated     time.Time
+	Items       []*Item
+}
+
+type Item struct {
+	ID          string // stable, globally unique identifier
+	Title       string
+	Link        string
+	Summary     string
+	ContentHTML string
+	Author      string
+	Categories  []string
+	Published   time.Time
+	Updated     time.Time
+}
+
+// Document is an encoded feed along with the validators used for conditional requests
+type Document struct {
+	Body         []byte    `json:"body"`
+	ContentType  string    `json:"content_type"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Encode renders the feed in the given format
+func Encode(feed *Feed, format Format) (*Document, error) {
+	var body []byte
+	var err error
+
+	switch format {
+	case FormatRSS:
+		body, err = encodeRSS(feed)
+	case FormatAtom:
+		body, err = encodeAtom(feed)
+	case FormatJSON:
+		body, err = encodeJSON(feed)
+	default:
+		return nil, fmt.Errorf("unsupported feed format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s feed: %w", format, err)
+	}
+
+	sum := sha256.Sum256(body)
+	return &Document{
+		Body:         body,
+		ContentType:  format.ContentType(),
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: feed.Updated.UTC().Truncate(time.Second),
+	}, nil
+}
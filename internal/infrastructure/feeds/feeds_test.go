@@ -0,0 +1,273 @@
+package feeds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	return &Feed{
+		Title:       "Blog & News",
+		Description: "Posts about <Go>",
+		Link:        "https://example.com/",
+		FeedURL:     "https://example.com/feed.xml",
+		Language:    "de",
+		Updated:     published.Add(time.Hour + 500*time.Millisecond),
+		Items: []*Item{
+			{
+				ID:          "urn:uuid:1",
+				Title:       "First <post>",
+				Link:        "https://example.com/posts/first",
+				Summary:     "A summary",
+				ContentHTML: "<p>Body with ]]> inside</p>",
+				Author:      "Jane",
+				Categories:  []string{"go", "web"},
+				Published:   published,
+				Updated:     published.Add(time.Hour),
+			},
+			{
+				ID:      "urn:uuid:2",
+				Title:   "Second",
+				Link:    "https://example.com/posts/second",
+				Summary: "Summary only",
+			},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		extension string
+		format    Format
+		ok        bool
+	}{
+		{extension: "rss", format: FormatRSS, ok: true},
+		{extension: "atom", format: FormatAtom, ok: true},
+		{extension: "json", format: FormatJSON, ok: true},
+		{extension: "xml"},
+		{extension: "RSS"},
+		{extension: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.extension, func(t *testing.T) {
+			format, ok := ParseFormat(tt.extension)
+			if format != tt.format || ok != tt.ok {
+				t.Fatalf("ParseFormat(%q) = %q, %v, want %q, %v", tt.extension, format, ok, tt.format, tt.ok)
+			}
+		})
+	}
+}
+
+func TestEncode_Document(t *testing.T) {
+	feed := testFeed()
+
+	tests := []struct {
+		format      Format
+		contentType string
+	}{
+		{format: FormatRSS, contentType: "application/rss+xml; charset=utf-8"},
+		{format: FormatAtom, contentType: "application/atom+xml; charset=utf-8"},
+		{format: FormatJSON, contentType: "application/feed+json; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			document, err := Encode(feed, tt.format)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if document.ContentType != tt.contentType {
+				t.Errorf("ContentType = %q, want %q", document.ContentType, tt.contentType)
+			}
+			if want := feed.Updated.UTC().Truncate(time.Second); !document.LastModified.Equal(want) {
+				t.Errorf("LastModified = %v, want %v", document.LastModified, want)
+			}
+			if !strings.HasPrefix(document.ETag, `"`) || !strings.HasSuffix(document.ETag, `"`) || len(document.ETag) != 34 {
+				t.Errorf("malformed ETag %q", document.ETag)
+			}
+
+			// The same feed always gets the same ETag, a changed one another
+			again, err := Encode(testFeed(), tt.format)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if again.ETag != document.ETag {
+				t.Errorf("ETag changed between identical feeds: %q, %q", document.ETag, again.ETag)
+			}
+			changed := testFeed()
+			changed.Items[0].Title = "Edited"
+			edited, err := Encode(changed, tt.format)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if edited.ETag == document.ETag {
+				t.Error("ETag did not change with the feed")
+			}
+		})
+	}
+
+	if _, err := Encode(feed, Format("xml")); err == nil {
+		t.Fatal("expected an error for an unsupported format")
+	}
+}
+
+func TestEncode_RSS(t *testing.T) {
+	document, err := Encode(testFeed(), FormatRSS)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	var rss struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			Description   string `xml:"description"`
+			Language      string `xml:"language"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			SelfLink      struct {
+				Href string `xml:"href,attr"`
+				Rel  string `xml:"rel,attr"`
+			} `xml:"http://www.w3.org/2005/Atom link"`
+			Items []struct {
+				Title string `xml:"title"`
+				GUID  struct {
+					Value       string `xml:",chardata"`
+					IsPermaLink string `xml:"isPermaLink,attr"`
+				} `xml:"guid"`
+				Content    string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+				Creator    string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				Categories []string `xml:"category"`
+				PubDate    string   `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(document.Body, &rss); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, document.Body)
+	}
+
+	channel := rss.Channel
+	if rss.Version != "2.0" || channel.Title != "Blog & News" || channel.Description != "Posts about <Go>" || channel.Language != "de" {
+		t.Fatalf("unexpected channel: %+v", channel)
+	}
+	if channel.LastBuildDate != "Fri, 01 Mar 2024 12:00:00 +0000" {
+		t.Errorf("lastBuildDate = %q", channel.LastBuildDate)
+	}
+	if channel.SelfLink.Href != "https://example.com/feed.xml" || channel.SelfLink.Rel != "self" {
+		t.Errorf("unexpected self link: %+v", channel.SelfLink)
+	}
+	if len(channel.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(channel.Items))
+	}
+
+	first := channel.Items[0]
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "title", got: first.Title, want: "First <post>"},
+		{name: "guid", got: first.GUID.Value, want: "urn:uuid:1"},
+		{name: "guid is not a permalink", got: first.GUID.IsPermaLink, want: "false"},
+		{name: "content", got: first.Content, want: "<p>Body with ]]> inside</p>"},
+		{name: "creator", got: first.Creator, want: "Jane"},
+		{name: "categories", got: strings.Join(first.Categories, ","), want: "go,web"},
+		{name: "pubDate in UTC", got: first.PubDate, want: "Fri, 01 Mar 2024 11:00:00 +0000"},
+		{name: "no content", got: channel.Items[1].Content, want: ""},
+		{name: "no pubDate", got: channel.Items[1].PubDate, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Fatalf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncode_Atom(t *testing.T) {
+	document, err := Encode(testFeed(), FormatAtom)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	var atom atomFeed
+	if err := xml.Unmarshal(document.Body, &atom); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, document.Body)
+	}
+	if atom.XMLName.Space != "http://www.w3.org/2005/Atom" {
+		t.Errorf("namespace = %q", atom.XMLName.Space)
+	}
+	if atom.ID != "https://example.com/feed.xml" || atom.Updated != "2024-03-01T12:00:00Z" {
+		t.Errorf("unexpected feed: id=%q updated=%q", atom.ID, atom.Updated)
+	}
+	if len(atom.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(atom.Entries))
+	}
+
+	first, second := atom.Entries[0], atom.Entries[1]
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "id", got: first.ID, want: "urn:uuid:1"},
+		{name: "published in UTC", got: first.Published, want: "2024-03-01T11:00:00Z"},
+		{name: "updated", got: first.Updated, want: "2024-03-01T12:00:00Z"},
+		{name: "link", got: first.Link.Href, want: "https://example.com/posts/first"},
+		{name: "summary", got: first.Summary.Value, want: "A summary"},
+		{name: "content", got: first.Content.Value, want: "<p>Body with ]]> inside</p>"},
+		{name: "content type", got: first.Content.Type, want: "html"},
+		{name: "categories", got: first.Categories[0].Term + "," + first.Categories[1].Term, want: "go,web"},
+		{name: "author", got: first.Author.Name, want: "Jane"},
+		{name: "no published date", got: second.Published, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Fatalf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+	if second.Author != nil || second.Content != nil {
+		t.Errorf("entry without author or content: %+v", second)
+	}
+}
+
+func TestEncode_JSON(t *testing.T) {
+	document, err := Encode(testFeed(), FormatJSON)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	var feed jsonFeed
+	if err := json.Unmarshal(document.Body, &feed); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, document.Body)
+	}
+	if feed.Version != jsonFeedVersion || feed.HomePageURL != "https://example.com/" || feed.Language != "de" {
+		t.Fatalf("unexpected feed: %+v", feed)
+	}
+	if len(feed.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(feed.Items))
+	}
+
+	first, second := feed.Items[0], feed.Items[1]
+	if first.ContentHTML != "<p>Body with ]]> inside</p>" || first.ContentText != "" {
+		t.Errorf("unexpected content: html=%q text=%q", first.ContentHTML, first.ContentText)
+	}
+	if first.DatePublished == nil || first.DatePublished.Location() != time.UTC || first.DatePublished.Hour() != 11 {
+		t.Errorf("date_published = %v, want 11:00 UTC", first.DatePublished)
+	}
+	if len(first.Authors) != 1 || first.Authors[0].Name != "Jane" || strings.Join(first.Tags, ",") != "go,web" {
+		t.Errorf("unexpected authors or tags: %+v, %v", first.Authors, first.Tags)
+	}
+	// Items without HTML content fall back to their summary as text
+	if second.ContentHTML != "" || second.ContentText != "Summary only" {
+		t.Errorf("unexpected fallback content: html=%q text=%q", second.ContentHTML, second.ContentText)
+	}
+	if second.DatePublished != nil || second.Authors != nil {
+		t.Errorf("unexpected date or authors on the second item: %+v", second)
+	}
+}
@@ -0,0 +1,92 @@
+// Package i18n resolves the locales posts are written in and read in.
+package i18n
+
+import (
+	"fmt"
+	"golang.org/x/text/language"
+)
+
+// Locales is the set of locales the site publishes in. Locales are BCP 47 tags
+// such as "en" or "pt-BR"; the default locale is used when nothing else matches.
+type Locales struct {
+	tags    []language.Tag
+	names   []string
+	matcher language.Matcher
+}
+
+// NewLocales builds the locale set. The default locale is added when missing from supported.
+func NewLocales(defaultLocale string, supported []string) (*Locales, error) {
+	l := &Locales{}
+
+	for _, locale := range append([]string{defaultLocale}, supported...) {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, fmt.Errorf("invalid locale %q: %w", locale, err)
+		}
+		if l.index(tag) >= 0 {
+			continue
+		}
+		l.tags = append(l.tags, tag)
+		l.names = append(l.names, tag.String())
+	}
+
+	// The matcher falls back to the first tag, the default locale
+	l.matcher = language.NewMatcher(l.tags)
+	return l, nil
+}
+
+// Default returns the default locale
+func (l *Locales) Default() string {
+	return l.names[0]
+}
+
+// Canonical returns the supported locale written as locale, such as "pt-BR" for "pt-br"
+func (l *Locales) Canonical(locale string) (string, bool) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", false
+	}
+	if i := l.index(tag); i >= 0 {
+		return l.names[i], true
+	}
+	return "", false
+}
+
+// Negotiate picks the supported locale closest to an explicit lang parameter or,
+// when that is empty or unusable, to an Accept-Language header
+func (l *Locales) Negotiate(lang, acceptLanguage string) string {
+	if lang != "" {
+		if tag, err := language.Parse(lang); err == nil {
+			if locale, ok := l.match(tag); ok {
+				return locale
+			}
+		}
+	}
+
+	if acceptLanguage != "" {
+		if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(tags) > 0 {
+			if locale, ok := l.match(tags...); ok {
+				return locale
+			}
+		}
+	}
+
+	return l.Default()
+}
+
+func (l *Locales) match(tags ...language.Tag) (string, bool) {
+	_, i, confidence := l.matcher.Match(tags...)
+	if confidence == language.No {
+		return "", false
+	}
+	return l.names[i], true
+}
+
+func (l *Locales) index(tag language.Tag) int {
+	for i, t := range l.tags {
+		if t == tag {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,109 @@
+package i18n
+
+import "testing"
+
+func newTestLocales(t *testing.T) *Locales {
+	t.Helper()
+	locales, err := NewLocales("en", []string{"de", "pt-br", "en", "zh-Hant"})
+	if err != nil {
+		t.Fatalf("NewLocales: %v", err)
+	}
+	return locales
+}
+
+func TestNewLocales(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultLocale string
+		supported     []string
+		want          string
+		wantErr       bool
+	}{
+		{name: "default listed", defaultLocale: "de", supported: []string{"en", "de"}, want: "de"},
+		{name: "default not listed", defaultLocale: "fr", supported: []string{"en"}, want: "fr"},
+		{name: "default written canonically", defaultLocale: "PT-br", want: "pt-BR"},
+		{name: "invalid default", defaultLocale: "not a locale", wantErr: true},
+		{name: "invalid supported locale", defaultLocale: "en", supported: []string{"en", "x"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locales, err := NewLocales(tt.defaultLocale, tt.supported)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := locales.Default(); got != tt.want {
+				t.Fatalf("Default() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocales_Canonical(t *testing.T) {
+	locales := newTestLocales(t)
+
+	tests := []struct {
+		locale string
+		want   string
+		ok     bool
+	}{
+		{locale: "en", want: "en", ok: true},
+		{locale: "pt-br", want: "pt-BR", ok: true},
+		{locale: "pt_BR", want: "pt-BR", ok: true},
+		{locale: "DE", want: "de", ok: true},
+		{locale: "zh-hant", want: "zh-Hant", ok: true},
+		// Only exact locales are canonical, close ones are for negotiation
+		{locale: "pt"},
+		{locale: "de-AT"},
+		{locale: "fr"},
+		{locale: ""},
+		{locale: "not a locale"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			got, ok := locales.Canonical(tt.locale)
+			if got != tt.want || ok != tt.ok {
+				t.Fatalf("Canonical(%q) = %q, %v, want %q, %v", tt.locale, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLocales_Negotiate(t *testing.T) {
+	locales := newTestLocales(t)
+
+	tests := []struct {
+		name           string
+		lang           string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "nothing requested", want: "en"},
+		{name: "lang parameter", lang: "de", want: "de"},
+		{name: "lang parameter in another case", lang: "PT-BR", want: "pt-BR"},
+		{name: "lang parameter with a region", lang: "de-AT", want: "de"},
+		{name: "lang parameter wins over the header", lang: "de", acceptLanguage: "pt-BR", want: "de"},
+		{name: "unsupported lang parameter falls back to the header", lang: "ja", acceptLanguage: "de", want: "de"},
+		{name: "invalid lang parameter falls back to the header", lang: "!!", acceptLanguage: "de", want: "de"},
+		{name: "header", acceptLanguage: "de-DE,de;q=0.9,en;q=0.8", want: "de"},
+		{name: "header weights", acceptLanguage: "en;q=0.5, pt-BR;q=0.9", want: "pt-BR"},
+		{name: "header with a close match", acceptLanguage: "pt-PT", want: "pt-BR"},
+		{name: "traditional chinese", acceptLanguage: "zh-TW", want: "zh-Hant"},
+		{name: "unsupported header", acceptLanguage: "ja", want: "en"},
+		{name: "wildcard header", acceptLanguage: "*", want: "en"},
+		{name: "malformed header", acceptLanguage: ";;;q=abc", want: "en"},
+		{name: "unsupported lang and header", lang: "ja", acceptLanguage: "ko", want: "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := locales.Negotiate(tt.lang, tt.acceptLanguage); got != tt.want {
+				t.Fatalf("Negotiate(%q, %q) = %q, want %q", tt.lang, tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"golang.org/x/image/webp"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("image must be a JPEG, PNG or WebP file")
+	ErrTooLarge          = errors.New("image dimensions are too large")
+)
+
+type decoder struct {
+	decode       func(io.Reader) (image.Image, error)
+	decodeConfig func(io.Reader) (image.Config, error)
+}
+
+// decoders maps the content types detected by http.DetectContentType to their decoders
+var decoders = map[string]decoder{
+	"image/jpeg": {jpeg.Decode, jpeg.DecodeConfig},
+	"image/png":  {png.Decode, png.DecodeConfig},
+	"image/gif":  {gif.Decode, gif.DecodeConfig},
+	"image/webp": {webp.Decode, webp.DecodeConfig},
+}
+
+// Decode decodes a JPEG, PNG, GIF or WebP image identified by its content, returning
+// it with its content type. JPEGs are turned upright according to their EXIF
+// orientation. Only the first frame of an animated GIF is decoded.
+func Decode(data []byte, maxDimension int) (image.Image, string, error) {
+	format := http.DetectContentType(data)
+	d, ok := decoders[format]
+	if !ok {
+		return nil, "", ErrUnsupportedFormat
+	}
+
+	// Check the dimensions from the header before allocating the pixels
+	config, err := d.decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	if config.Width > maxDimension || config.Height > maxDimension {
+		return nil, "", ErrTooLarge
+	}
+
+	img, err := d.decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	if format == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return img, format, nil
+}
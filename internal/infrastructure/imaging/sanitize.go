@@ -0,0 +1,55 @@
+package imaging
+
+import (
+	"bytes"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+const jpegQuality = 90
+
+// Image is an uploaded image re-encoded without its metadata
+type Image struct {
+	Data        []byte
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// Sanitize decodes a JPEG, PNG or WebP image, identified by its content rather than
+// its name, and re-encodes it so that EXIF and any other embedded metadata is dropped.
+// JPEG orientation is applied to the pixels first. WebP images are re-encoded as PNG,
+// as re-encoding them losslessly would only make them larger.
+func Sanitize(r io.Reader, maxDimension int) (*Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	img, format, err := Decode(data, maxDimension)
+	if err != nil {
+		return nil, err
+	}
+	if format == "image/gif" {
+		return nil, ErrUnsupportedFormat
+	}
+
+	var out bytes.Buffer
+	result := &Image{}
+	if format == "image/jpeg" {
+		err = jpeg.Encode(&out, img, &jpeg.Options{Quality: jpegQuality})
+		result.ContentType, result.Ext = "image/jpeg", ".jpg"
+	} else {
+		err = png.Encode(&out, img)
+		result.ContentType, result.Ext = "image/png", ".png"
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Data = out.Bytes()
+	result.Width, result.Height = img.Bounds().Dx(), img.Bounds().Dy()
+	return result, nil
+}
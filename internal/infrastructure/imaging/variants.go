@@ -0,0 +1,94 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+const variantJPEGQuality = 82
+
+// Variant is a resized copy of an image, encoded and ready to store
+type Variant struct {
+	Name        string // "thumb" for the thumbnail, "w{width}" for width variants
+	Width       int
+	Height      int
+	Thumbnail   bool
+	ContentType string
+	Ext         string
+	Data        []byte
+}
+
+// Variants returns a square thumbnail and a copy of the image at each of widths that
+// is narrower than the image. JPEGs are copied as JPEG, everything else as PNG to keep
+// transparency. Each copy is also encoded as WebP and kept when that is smaller; the
+// WebP encoder is lossless, so it mostly pays off for graphics rather than photos.
+func Variants(img image.Image, format string, widths []int, thumbnailSize int) ([]*Variant, error) {
+	bounds := img.Bounds()
+	variants := []*Variant{}
+
+	// The thumbnail is a centered square crop
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(bounds.Min).Add(image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2))
+	thumbSide := min(side, thumbnailSize)
+	thumb, err := encodeVariant("thumb", scale(img, crop, thumbSide, thumbSide), format, true)
+	if err != nil {
+		return nil, err
+	}
+	variants = append(variants, thumb...)
+
+	for _, width := range widths {
+		if width >= bounds.Dx() {
+			continue
+		}
+		height := max(1, bounds.Dy()*width/bounds.Dx())
+		resized, err := encodeVariant(fmt.Sprintf("w%d", width), scale(img, bounds, width, height), format, false)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, resized...)
+	}
+
+	return variants, nil
+}
+
+func scale(img image.Image, src image.Rectangle, width, height int) image.Image {
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+	return dst
+}
+
+func encodeVariant(name string, img image.Image, format string, thumbnail bool) ([]*Variant, error) {
+	bounds := img.Bounds()
+	fallback := &Variant{Name: name, Width: bounds.Dx(), Height: bounds.Dy(), Thumbnail: thumbnail}
+
+	var out bytes.Buffer
+	var err error
+	if format == "image/jpeg" {
+		err = jpeg.Encode(&out, img, &jpeg.Options{Quality: variantJPEGQuality})
+		fallback.ContentType, fallback.Ext = "image/jpeg", ".jpg"
+	} else {
+		err = png.Encode(&out, img)
+		fallback.ContentType, fallback.Ext = "image/png", ".png"
+	}
+	if err != nil {
+		return nil, err
+	}
+	fallback.Data = out.Bytes()
+
+	var webpOut bytes.Buffer
+	if err := nativewebp.Encode(&webpOut, img, nil); err != nil {
+		return nil, err
+	}
+	if webpOut.Len() >= len(fallback.Data) {
+		return []*Variant{fallback}, nil
+	}
+
+	webpVariant := *fallback
+	webpVariant.ContentType, webpVariant.Ext, webpVariant.Data = "image/webp", ".webp", webpOut.Bytes()
+	return []*Variant{fallback, &webpVariant}, nil
+}
@@ -0,0 +1,267 @@
+// Package markdown renders post content written in Markdown to sanitized HTML
+// and derives the metadata shown alongside it (excerpt, reading time and table of contents).
+package markdown
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"bytes"
+	"fmt"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Version identifies the rendering pipeline. Bump it whenever the Markdown
+// extensions or the sanitization policy change so cached output is discarded.
+const Version = "2"
+
+const (
+	wordsPerMinute   = 200
+	maxExcerptLength = 280
+	maxTOCLevel      = 4
+)
+
+// Rendered is the output of rendering a Markdown document
+type Rendered struct {
+	HTML               string                  `json:"html"`
+	Excerpt            string                  `json:"excerpt"`
+	WordCount          int                     `json:"word_count"`
+	ReadingTimeMinutes int                     `json:"reading_time_minutes"`
+	TableOfContents    []*postDTOs.TOCEntryDTO `json:"table_of_contents"`
+}
+
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+func NewRenderer() *Renderer {
+	md := goldmark.New(
+		// CommonMark plus tables, strikethrough, autolinks, task lists and footnotes
+		goldmark.WithExtensions(extension.GFM, extension.Footnote),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		// Raw HTML is passed through here and stripped by the sanitizer below
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	return &Renderer{
+		md:     md,
+		policy: newPolicy(),
+	}
+}
+
+// newPolicy builds the allow-list applied to rendered HTML. It starts from the
+// user generated content policy and adds what the Markdown extensions emit.
+func newPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+
+	// Heading anchors for the table of contents and footnote back-references
+	policy.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_:-]+$`)).
+		OnElements("h1", "h2", "h3", "h4", "h5", "h6", "li", "sup")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^footnote(s|-ref|-backref)?$`)).
+		OnElements("a", "div", "sup")
+	policy.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-(noteref|backlink|endnotes)$`)).
+		OnElements("a", "div")
+
+	// Syntax highlighting hints on fenced code blocks
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[a-zA-Z0-9+#_-]+$`)).OnElements("code")
+
+	// Task list checkboxes
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+
+	// Table cell alignment
+	policy.AllowAttrs("style").Matching(regexp.MustCompile(`^text-align:(left|right|center)$`)).OnElements("th", "td")
+
+	policy.RequireNoFollowOnLinks(true)
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+
+	return policy
+}
+
+// Render converts Markdown to sanitized HTML and extracts the document's metadata
+func (r *Renderer) Render(source string) (*Rendered, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := r.md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := r.md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	words := countWords(doc, src)
+
+	return &Rendered{
+		HTML:               r.policy.Sanitize(buf.String()),
+		Excerpt:            excerpt(doc, src),
+		WordCount:          words,
+		ReadingTimeMinutes: int(math.Max(1, math.Ceil(float64(words)/wordsPerMinute))),
+		TableOfContents:    tableOfContents(doc, src),
+	}, nil
+}
+
+// tableOfContents nests the document's headings by level
+func tableOfContents(doc ast.Node, src []byte) []*postDTOs.TOCEntryDTO {
+	toc := []*postDTOs.TOCEntryDTO{}
+	var stack []*postDTOs.TOCEntryDTO
+
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		heading, ok := node.(*ast.Heading)
+		if !ok || heading.Level > maxTOCLevel {
+			continue
+		}
+
+		entry := &postDTOs.TOCEntryDTO{
+			Text:  strings.TrimSpace(plainText(heading, src)),
+			Level: heading.Level,
+		}
+		if id, ok := heading.AttributeString("id"); ok {
+			if value, ok := id.([]byte); ok {
+				entry.ID = string(value)
+			}
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= entry.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			toc = append(toc, entry)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, entry)
+		}
+		stack = append(stack, entry)
+	}
+
+	return toc
+}
+
+// excerpt builds a plain-text summary from the leading paragraphs, cut at a word boundary
+func excerpt(doc ast.Node, src []byte) string {
+	var parts []string
+	length := 0
+
+	for node := doc.FirstChild(); node != nil && length < maxExcerptLength; node = node.NextSibling() {
+		if node.Kind() != ast.KindParagraph {
+			continue
+		}
+		paragraph := strings.Join(strings.Fields(plainText(node, src)), " ")
+		if paragraph == "" {
+			continue
+		}
+		parts = append(parts, paragraph)
+		length += utf8.RuneCountInString(paragraph) + 1
+	}
+
+	summary := strings.Join(parts, " ")
+	if utf8.RuneCountInString(summary) <= maxExcerptLength {
+		return summary
+	}
+
+	runes := []rune(summary)[:maxExcerptLength]
+	cut := string(runes)
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// countWords counts the words of all text in the document, including code
+func countWords(doc ast.Node, src []byte) int {
+	words := 0
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.Text:
+			words += len(strings.Fields(string(n.Segment.Value(src))))
+		case *ast.String:
+			words += len(strings.Fields(string(n.Value)))
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				segment := lines.At(i)
+				words += len(strings.Fields(string(segment.Value(src))))
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return words
+}
+
+// plainText concatenates the inline text below node
+func plainText(node ast.Node, src []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := n.(type) {
+		case *ast.Text:
+			sb.Write(t.Segment.Value(src))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return sb.String()
+}
+
+// headingIDs generates heading anchors that keep letters and digits of any script, where
+// goldmark's default drops everything but ASCII
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: map[string]bool{}}
+}
+
+func (s *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	var id strings.Builder
+	for _, r := range strings.TrimSpace(string(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			id.WriteRune(unicode.ToLower(r))
+		case r == '-' || r == '_':
+			id.WriteRune(r)
+		case unicode.IsSpace(r):
+			id.WriteByte('-')
+		}
+	}
+
+	base := id.String()
+	if base == "" {
+		base = "heading"
+		if kind != ast.KindHeading {
+			base = "id"
+		}
+	}
+
+	result := base
+	for i := 1; s.used[result]; i++ {
+		result = base + "-" + strconv.Itoa(i)
+	}
+	s.used[result] = true
+	return []byte(result)
+}
+
+func (s *headingIDs) Put(value []byte) {
+	s.used[string(value)] = true
+}
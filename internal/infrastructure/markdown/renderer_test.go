@@ -0,0 +1,226 @@
+package markdown
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_Sanitization(t *testing.T) {
+	renderer := NewRenderer()
+
+	tests := []struct {
+		name    string
+		source  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "script tags are stripped",
+			source:  "Hello <script>alert(1)</script> world",
+			want:    []string{"Hello", "world"},
+			notWant: []string{"<script", "alert(1)"},
+		},
+		{
+			name:    "event handlers are stripped",
+			source:  `<img src="x.png" onerror="alert(1)">`,
+			notWant: []string{"onerror"},
+		},
+		{
+			name:    "javascript links are stripped",
+			source:  "[click](javascript:alert(1))",
+			notWant: []string{"javascript:"},
+		},
+		{
+			name:   "links get rel nofollow",
+			source: "[site](https://example.com)",
+			want:   []string{`href="https://example.com"`, `rel="nofollow noopener"`, `target="_blank"`},
+		},
+		{
+			name:   "code block language is kept",
+			source: "```go\nfmt.Println(1)\n```",
+			want:   []string{`<code class="language-go">`},
+		},
+		{
+			name:    "code block language cannot inject attributes",
+			source:  "```go\" onclick=\"x\nfmt.Println(1)\n```",
+			notWant: []string{"onclick"},
+		},
+		{
+			name:   "tables keep their alignment",
+			source: "| a | b |\n|:--|--:|\n| 1 | 2 |",
+			want:   []string{`<th style="text-align:left">`, `<td style="text-align:right">`},
+		},
+		{
+			name:    "styles other than alignment are stripped",
+			source:  `<p style="position:fixed">x</p>`,
+			notWant: []string{"position:fixed"},
+		},
+		{
+			name:   "task lists keep their checkboxes",
+			source: "- [x] done\n- [ ] todo",
+			want:   []string{`type="checkbox"`, `checked`},
+		},
+		{
+			name:   "footnotes keep their references",
+			source: "text[^1]\n\n[^1]: note",
+			want:   []string{`id="fnref:1"`, `class="footnote-ref"`, `role="doc-endnotes"`, `<li id="fn:1">`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := renderer.Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(rendered.HTML, want) {
+					t.Errorf("HTML does not contain %q:\n%s", want, rendered.HTML)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(rendered.HTML, notWant) {
+					t.Errorf("HTML contains %q:\n%s", notWant, rendered.HTML)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderer_HeadingAnchors(t *testing.T) {
+	renderer := NewRenderer()
+
+	tests := []struct {
+		name   string
+		source string
+		ids    []string
+	}{
+		{name: "ascii", source: "# Hello World", ids: []string{"hello-world"}},
+		{name: "accented", source: "# Überblick", ids: []string{"überblick"}},
+		{name: "cyrillic", source: "# Привет мир", ids: []string{"привет-мир"}},
+		{name: "cjk", source: "# 日本語の見出し", ids: []string{"日本語の見出し"}},
+		{name: "digits and punctuation", source: "# Step 1: Install!", ids: []string{"step-1-install"}},
+		{name: "duplicates", source: "# Intro\n\n## Intro\n\n## Intro", ids: []string{"intro", "intro-1", "intro-2"}},
+		{name: "no letters", source: "# !!!\n\n## ???", ids: []string{"heading", "heading-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := renderer.Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			for _, id := range tt.ids {
+				// The anchor must survive sanitization
+				if !strings.Contains(rendered.HTML, `id="`+id+`"`) {
+					t.Errorf("HTML has no anchor %q:\n%s", id, rendered.HTML)
+				}
+			}
+			if got := tocIDs(rendered.TableOfContents); strings.Join(got, ",") != strings.Join(tt.ids, ",") {
+				t.Errorf("table of contents ids = %v, want %v", got, tt.ids)
+			}
+		})
+	}
+}
+
+func TestRenderer_AnchorsAreNotSharedBetweenRenders(t *testing.T) {
+	renderer := NewRenderer()
+
+	for i := 0; i < 2; i++ {
+		rendered, err := renderer.Render("# Intro")
+		if err != nil {
+			t.Fatalf("Render: %v", err)
+		}
+		if !strings.Contains(rendered.HTML, `id="intro"`) {
+			t.Fatalf("render %d: expected anchor intro:\n%s", i, rendered.HTML)
+		}
+	}
+}
+
+func TestRenderer_TableOfContents(t *testing.T) {
+	rendered, err := NewRenderer().Render("# A\n\n## B\n\n### C\n\n##### Too deep\n\n## D\n\n# E")
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	toc := rendered.TableOfContents
+	if len(toc) != 2 || toc[0].Text != "A" || toc[1].Text != "E" {
+		t.Fatalf("unexpected top level: %+v", toc)
+	}
+	children := toc[0].Children
+	if len(children) != 2 || children[0].Text != "B" || children[1].Text != "D" {
+		t.Fatalf("unexpected children of A: %+v", children)
+	}
+	if len(children[0].Children) != 1 || children[0].Children[0].Text != "C" {
+		t.Fatalf("unexpected children of B: %+v", children[0].Children)
+	}
+	if len(children[0].Children[0].Children) != 0 {
+		t.Fatalf("headings below level %d must be left out", maxTOCLevel)
+	}
+}
+
+func TestRenderer_ExcerptAndReadingTime(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+
+	tests := []struct {
+		name        string
+		source      string
+		excerpt     string
+		words       int
+		readingTime int
+	}{
+		{
+			name:        "leading paragraphs as plain text",
+			source:      "# Title\n\nFirst *emphasised* paragraph.\n\nSecond [linked](https://example.com) one.",
+			excerpt:     "First emphasised paragraph. Second linked one.",
+			words:       7,
+			readingTime: 1,
+		},
+		{
+			name:        "empty document",
+			source:      "",
+			excerpt:     "",
+			words:       0,
+			readingTime: 1,
+		},
+		{
+			name:        "code counts towards reading time",
+			source:      "Intro\n\n```\none two three\n```",
+			excerpt:     "Intro",
+			words:       4,
+			readingTime: 1,
+		},
+		{
+			name:        "long documents",
+			source:      strings.Repeat(long+"\n\n", 5),
+			excerpt:     strings.TrimSpace(strings.Repeat("word ", maxExcerptLength/len("word "))) + "…",
+			words:       500,
+			readingTime: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := NewRenderer().Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if rendered.Excerpt != tt.excerpt {
+				t.Errorf("Excerpt = %q, want %q", rendered.Excerpt, tt.excerpt)
+			}
+			if rendered.WordCount != tt.words {
+				t.Errorf("WordCount = %d, want %d", rendered.WordCount, tt.words)
+			}
+			if rendered.ReadingTimeMinutes != tt.readingTime {
+				t.Errorf("ReadingTimeMinutes = %d, want %d", rendered.ReadingTimeMinutes, tt.readingTime)
+			}
+		})
+	}
+}
+
+func tocIDs(entries []*postDTOs.TOCEntryDTO) []string {
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+		ids = append(ids, tocIDs(entry.Children)...)
+	}
+	return ids
+}
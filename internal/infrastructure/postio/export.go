@@ -0,0 +1,46 @@
+package postio
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"archive/zip"
+	"fmt"
+	"io"
+	"time"
+)
+
+// WriteMarkdownZip writes each post as a Markdown file named after its slug into a zip archive
+func WriteMarkdownZip(w io.Writer, posts []*postDTOs.PostDTO) error {
+	archive := zip.NewWriter(w)
+	used := make(map[string]bool, len(posts))
+
+	for _, post := range posts {
+		base := fmt.Sprintf("post-%d", post.ID)
+		if post.Slug != nil && *post.Slug != "" {
+			base = *post.Slug
+		}
+		name := base + ".md"
+		for i := 1; used[name]; i++ {
+			name = fmt.Sprintf("%s-%d.md", base, i)
+		}
+		used[name] = true
+
+		content, err := EncodeMarkdown(post)
+		if err != nil {
+			return fmt.Errorf("failed to export post %d: %w", post.ID, err)
+		}
+
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: post.UpdatedAt.In(time.UTC),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(content); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
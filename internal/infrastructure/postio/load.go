@@ -0,0 +1,195 @@
+package postio
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	maxFileSize     = 10 << 20 // largest single Markdown or WXR file read
+	maxArchiveFiles = 10000
+	maxArchiveSize  = 200 << 20 // most bytes read from all files of a zip archive together
+)
+
+// Loaded is the result of reading an import source. Files that could not be
+// parsed are listed in Failures instead of aborting the whole import.
+type Loaded struct {
+	Posts    []*postDTOs.ImportedPostDTO
+	Failures []*postDTOs.ImportItemReportDTO
+}
+
+// LoadPath reads posts from a Markdown file, a WXR export, a zip of either, or a directory of them
+func LoadPath(path string) (*Loaded, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := &Loaded{}
+	if !info.IsDir() {
+		data, err := readLimited(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := loaded.add(filepath.Base(path), data); err != nil {
+			return nil, err
+		}
+		return loaded, nil
+	}
+
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !isImportable(entry.Name()) {
+			return nil
+		}
+
+		data, err := readLimited(file)
+		if err != nil {
+			return err
+		}
+		name, _ := filepath.Rel(path, file)
+		return loaded.add(filepath.ToSlash(name), data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return loaded, nil
+}
+
+// LoadFile reads posts from an uploaded file, dispatching on its extension
+func LoadFile(name string, data []byte) (*Loaded, error) {
+	loaded := &Loaded{}
+	if err := loaded.add(name, data); err != nil {
+		return nil, err
+	}
+	return loaded, nil
+}
+
+func (l *Loaded) add(name string, data []byte) error {
+	switch extension(name) {
+	case ".zip":
+		return l.addZip(name, data)
+	case ".xml":
+		posts, err := ParseWXR(name, data)
+		if err != nil {
+			l.fail(name, err)
+			return nil
+		}
+		l.Posts = append(l.Posts, posts...)
+	case ".md", ".markdown":
+		post, err := ParseMarkdown(name, data)
+		if err != nil {
+			l.fail(name, err)
+			return nil
+		}
+		l.Posts = append(l.Posts, post)
+	default:
+		return fmt.Errorf("unsupported import file %q: expected .md, .markdown, .xml or .zip", name)
+	}
+	return nil
+}
+
+func (l *Loaded) addZip(name string, data []byte) error {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("invalid zip archive %q: %w", name, err)
+	}
+	if len(archive.File) > maxArchiveFiles {
+		return fmt.Errorf("zip archive %q has more than %d files", name, maxArchiveFiles)
+	}
+
+	var total int64
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !isImportable(file.Name) || strings.HasPrefix(file.Name, "__MACOSX/") {
+			continue
+		}
+		// Nested archives are not expanded
+		if extension(file.Name) == ".zip" {
+			continue
+		}
+
+		source := name + "/" + file.Name
+		content, err := readZipFile(file)
+		if err != nil {
+			l.fail(source, err)
+			continue
+		}
+		// Counted as read, since the sizes in the archive's headers may be false
+		total += int64(len(content))
+		if total > maxArchiveSize {
+			return fmt.Errorf("zip archive %q expands to more than %d bytes", name, maxArchiveSize)
+		}
+		if err := l.add(source, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *Loaded) fail(source string, err error) {
+	l.Failures = append(l.Failures, &postDTOs.ImportItemReportDTO{
+		Source: source,
+		Action: postDTOs.ImportActionSkip,
+		Errors: []string{err.Error()},
+	})
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	if file.UncompressedSize64 > maxFileSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxFileSize)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// The declared size can't be trusted, so the read itself is bounded too
+	data, err := io.ReadAll(io.LimitReader(reader, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxFileSize)
+	}
+	return data, nil
+}
+
+func readLimited(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	// Archives hold many posts and are allowed to be larger than a single file
+	if extension(path) != ".zip" && info.Size() > maxFileSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", path, maxFileSize)
+	}
+	return os.ReadFile(path)
+}
+
+func isImportable(name string) bool {
+	if strings.HasPrefix(filepath.Base(name), ".") {
+		return false
+	}
+	switch extension(name) {
+	case ".md", ".markdown", ".xml", ".zip":
+		return true
+	}
+	return false
+}
+
+func extension(name string) string {
+	return strings.ToLower(filepath.Ext(name))
+}
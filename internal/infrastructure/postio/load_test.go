@@ -0,0 +1,211 @@
+package postio
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type zipEntry struct {
+	name    string
+	content string
+}
+
+func buildZip(t *testing.T, entries ...zipEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		file, err := archive.Create(entry.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadFile(t *testing.T) {
+	nested := buildZip(t, zipEntry{"inner.md", "# Inner"})
+
+	tests := []struct {
+		name     string
+		file     string
+		data     []byte
+		posts    []string // sources of the loaded posts
+		failures []string // sources of the files that could not be parsed
+		wantErr  string
+	}{
+		{
+			name:  "markdown",
+			file:  "post.md",
+			data:  []byte("# Title"),
+			posts: []string{"post.md"},
+		},
+		{
+			name:  "extension is case insensitive",
+			file:  "POST.MARKDOWN",
+			data:  []byte("# Title"),
+			posts: []string{"POST.MARKDOWN"},
+		},
+		{
+			name:     "unparsable markdown is reported, not fatal",
+			file:     "post.md",
+			data:     []byte("no title"),
+			failures: []string{"post.md"},
+		},
+		{
+			name:  "wxr",
+			file:  "export.xml",
+			data:  []byte(wxrExport),
+			posts: []string{"export.xml#item-1", "export.xml#item-2", "export.xml#item-3"},
+		},
+		{
+			name:     "unparsable wxr is reported, not fatal",
+			file:     "export.xml",
+			data:     []byte("<rss>"),
+			failures: []string{"export.xml"},
+		},
+		{
+			name:    "unsupported extension",
+			file:    "post.txt",
+			data:    []byte("# Title"),
+			wantErr: "unsupported import file",
+		},
+		{
+			name: "zip",
+			file: "posts.zip",
+			data: buildZip(t,
+				zipEntry{"a.md", "# A"},
+				zipEntry{"dir/b.markdown", "# B"},
+				zipEntry{"dir/broken.md", "no title"},
+				zipEntry{"notes.txt", "ignored"},
+				zipEntry{".hidden.md", "# Hidden"},
+				zipEntry{"__MACOSX/._a.md", "resource fork"},
+				zipEntry{"__MACOSX/c.md", "# Metadata"},
+				zipEntry{"nested.zip", string(nested)},
+			),
+			posts:    []string{"posts.zip/a.md", "posts.zip/dir/b.markdown"},
+			failures: []string{"posts.zip/dir/broken.md"},
+		},
+		{
+			name:    "invalid zip",
+			file:    "posts.zip",
+			data:    []byte("not a zip"),
+			wantErr: "invalid zip archive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := LoadFile(tt.file, tt.data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertSources(t, loaded, tt.posts, tt.failures)
+		})
+	}
+}
+
+func TestLoadFile_ZipLimits(t *testing.T) {
+	oversized := zipEntry{"big.md", "# Big\n" + strings.Repeat("a", maxFileSize)}
+	loaded, err := LoadFile("posts.zip", buildZip(t, oversized, zipEntry{"small.md", "# Small"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A single oversized file is skipped, the rest is imported
+	assertSources(t, loaded, []string{"posts.zip/small.md"}, []string{"posts.zip/big.md"})
+
+	// Every file is within its own limit, but together they expand past the archive's
+	content := "# Title\n" + strings.Repeat("a", maxFileSize-len("# Title\n"))
+	var entries []zipEntry
+	for i := 0; i <= maxArchiveSize/maxFileSize; i++ {
+		entries = append(entries, zipEntry{fmt.Sprintf("post-%d.md", i), content})
+	}
+	if _, err := LoadFile("posts.zip", buildZip(t, entries...)); err == nil || !strings.Contains(err.Error(), "expands to more than") {
+		t.Fatalf("expected the archive size limit to be enforced, got %v", err)
+	}
+
+	many := make([]zipEntry, maxArchiveFiles+1)
+	for i := range many {
+		many[i] = zipEntry{name: fmt.Sprintf("notes-%d.txt", i)}
+	}
+	if _, err := LoadFile("posts.zip", buildZip(t, many...)); err == nil || !strings.Contains(err.Error(), "more than") {
+		t.Fatalf("expected the file count limit to be enforced, got %v", err)
+	}
+}
+
+func TestLoadPath(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.md":           "# A",
+		"sub/b.md":       "# B",
+		"sub/notes.txt":  "ignored",
+		".draft.md":      "# Hidden",
+		"sub/broken.md":  "no title",
+		"sub/export.xml": wxrExport,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := LoadPath(dir)
+	if err != nil {
+		t.Fatalf("LoadPath: %v", err)
+	}
+	assertSources(t, loaded,
+		[]string{"a.md", "sub/b.md", "sub/export.xml#item-1", "sub/export.xml#item-2", "sub/export.xml#item-3"},
+		[]string{"sub/broken.md"})
+
+	single, err := LoadPath(filepath.Join(dir, "sub", "b.md"))
+	if err != nil {
+		t.Fatalf("LoadPath: %v", err)
+	}
+	assertSources(t, single, []string{"b.md"}, nil)
+
+	if _, err := LoadPath(filepath.Join(dir, "sub", "notes.txt")); err == nil {
+		t.Fatal("expected an error for an unsupported file")
+	}
+}
+
+func assertSources(t *testing.T, loaded *Loaded, posts, failures []string) {
+	t.Helper()
+
+	var gotPosts, gotFailures []string
+	for _, post := range loaded.Posts {
+		gotPosts = append(gotPosts, post.Source)
+	}
+	for _, failure := range loaded.Failures {
+		gotFailures = append(gotFailures, failure.Source)
+	}
+	sort.Strings(gotPosts)
+	sort.Strings(gotFailures)
+
+	if strings.Join(gotPosts, ",") != strings.Join(posts, ",") {
+		t.Errorf("posts = %v, want %v", gotPosts, posts)
+	}
+	if strings.Join(gotFailures, ",") != strings.Join(failures, ",") {
+		t.Errorf("failures = %v, want %v", gotFailures, failures)
+	}
+}
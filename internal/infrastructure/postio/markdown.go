@@ -0,0 +1,142 @@
+// Package postio reads posts from portable formats (Markdown files with YAML front
+// matter and WordPress WXR exports, loose or zipped) and writes them back out as Markdown.
+package postio
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"bytes"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"strings"
+	"time"
+)
+
+const frontMatterDelimiter = "---"
+
+// frontMatter is the YAML header of a Markdown post
+type frontMatter struct {
+	Title       string     `yaml:"title"`
+	Slug        string     `yaml:"slug,omitempty"`
+	Status      string     `yaml:"status,omitempty"`
+	PublishedAt *time.Time `yaml:"published_at,omitempty"`
+	Excerpt     string     `yaml:"excerpt,omitempty"`
+	Tags        stringList `yaml:"tags,omitempty"`
+	Categories  stringList `yaml:"categories,omitempty"`
+}
+
+// stringList accepts either a YAML sequence or a single comma separated string
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = nil
+		for _, value := range strings.Split(node.Value, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				*l = append(*l, value)
+			}
+		}
+		return nil
+	}
+
+	var values []string
+	if err := node.Decode(&values); err != nil {
+		return err
+	}
+	*l = values
+	return nil
+}
+
+// ParseMarkdown reads a Markdown post. Front matter is optional; without a title in
+// it, the first level one heading is used.
+func ParseMarkdown(source string, data []byte) (*postDTOs.ImportedPostDTO, error) {
+	text := strings.ReplaceAll(string(bytes.TrimPrefix(data, []byte("\ufeff"))), "\r\n", "\n")
+
+	var meta frontMatter
+	body := text
+	if strings.HasPrefix(text, frontMatterDelimiter+"\n") {
+		// Keep the newline ending the opening delimiter so an empty header is found too
+		rest := text[len(frontMatterDelimiter):]
+		end := strings.Index(rest, "\n"+frontMatterDelimiter)
+		if end < 0 {
+			return nil, fmt.Errorf("front matter is not closed")
+		}
+		if err := yaml.Unmarshal([]byte(rest[:end]), &meta); err != nil {
+			return nil, fmt.Errorf("invalid front matter: %w", err)
+		}
+		body = rest[end+len(frontMatterDelimiter)+1:]
+		// Drop the remainder of the closing delimiter line
+		if i := strings.IndexByte(body, '\n'); i >= 0 {
+			body = body[i+1:]
+		} else {
+			body = ""
+		}
+	}
+
+	title := strings.TrimSpace(meta.Title)
+	if title == "" {
+		title = firstHeading(body)
+	}
+	if title == "" {
+		return nil, fmt.Errorf("post has no title")
+	}
+
+	post := &postDTOs.ImportedPostDTO{
+		Source:      source,
+		Title:       title,
+		Slug:        strings.TrimSpace(meta.Slug),
+		Content:     strings.TrimSpace(body),
+		Status:      strings.ToLower(strings.TrimSpace(meta.Status)),
+		PublishedAt: meta.PublishedAt,
+		Tags:        meta.Tags,
+		Categories:  meta.Categories,
+	}
+	if excerpt := strings.TrimSpace(meta.Excerpt); excerpt != "" {
+		post.Excerpt = &excerpt
+	}
+
+	return post, nil
+}
+
+func firstHeading(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "# ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		}
+	}
+	return ""
+}
+
+// EncodeMarkdown writes a post as Markdown with front matter that ParseMarkdown reads back
+func EncodeMarkdown(post *postDTOs.PostDTO) ([]byte, error) {
+	meta := frontMatter{
+		Title:       post.Title,
+		Status:      post.Status,
+		PublishedAt: post.PublishedAt,
+	}
+	if post.Slug != nil {
+		meta.Slug = *post.Slug
+	}
+	if post.Excerpt != nil {
+		meta.Excerpt = *post.Excerpt
+	}
+	for _, tag := range post.Tags {
+		meta.Tags = append(meta.Tags, tag.Name)
+	}
+	for _, category := range post.Categories {
+		meta.Categories = append(meta.Categories, category.Slug)
+	}
+
+	header, err := yaml.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode front matter: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(header)
+	buf.WriteString(frontMatterDelimiter + "\n\n")
+	buf.WriteString(strings.TrimSpace(post.Content))
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,167 @@
+package postio
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/dtos/taxonomyDTOs"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	published := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	excerpt := "Short"
+
+	tests := []struct {
+		name    string
+		data    string
+		want    *postDTOs.ImportedPostDTO
+		wantErr string
+	}{
+		{
+			name: "full front matter",
+			data: "---\ntitle: Hello\nslug: hello-world\nstatus: Published\npublished_at: 2024-03-01T09:30:00Z\n" +
+				"excerpt: Short\ntags: [go, web]\ncategories:\n  - news\n---\n\nBody text\n",
+			want: &postDTOs.ImportedPostDTO{
+				Title: "Hello", Slug: "hello-world", Content: "Body text", Excerpt: &excerpt, Status: "published",
+				PublishedAt: &published, Tags: []string{"go", "web"}, Categories: []string{"news"},
+			},
+		},
+		{
+			name: "comma separated lists",
+			data: "---\ntitle: Hello\ntags: go, web ,, \ncategories: news\n---\nBody",
+			want: &postDTOs.ImportedPostDTO{Title: "Hello", Content: "Body", Tags: []string{"go", "web"}, Categories: []string{"news"}},
+		},
+		{
+			name: "no front matter uses the first heading",
+			data: "Intro\n\n# The Title\n\nBody",
+			want: &postDTOs.ImportedPostDTO{Title: "The Title", Content: "Intro\n\n# The Title\n\nBody"},
+		},
+		{
+			name: "front matter without title uses the first heading",
+			data: "---\nslug: s\n---\n# From Heading\nBody",
+			want: &postDTOs.ImportedPostDTO{Title: "From Heading", Slug: "s", Content: "# From Heading\nBody"},
+		},
+		{
+			name: "empty front matter",
+			data: "---\n---\n# Title",
+			want: &postDTOs.ImportedPostDTO{Title: "Title", Content: "# Title"},
+		},
+		{
+			name: "byte order mark and CRLF line endings",
+			data: "\ufeff---\r\ntitle: Windows\r\n---\r\nLine one\r\nLine two\r\n",
+			want: &postDTOs.ImportedPostDTO{Title: "Windows", Content: "Line one\nLine two"},
+		},
+		{
+			name: "horizontal rules in the body are kept",
+			data: "---\ntitle: Rules\n---\nAbove\n\n---\n\nBelow",
+			want: &postDTOs.ImportedPostDTO{Title: "Rules", Content: "Above\n\n---\n\nBelow"},
+		},
+		{
+			name:    "unclosed front matter",
+			data:    "---\ntitle: Hello\nBody",
+			wantErr: "front matter is not closed",
+		},
+		{
+			name:    "invalid front matter",
+			data:    "---\ntitle: [unclosed\n---\nBody",
+			wantErr: "invalid front matter",
+		},
+		{
+			name:    "no title",
+			data:    "Just a paragraph\n\n## Second level",
+			wantErr: "post has no title",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post, err := ParseMarkdown("post.md", []byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.want.Source = "post.md"
+			assertImportedPost(t, post, tt.want)
+		})
+	}
+}
+
+func TestEncodeMarkdown_RoundTrip(t *testing.T) {
+	slug := "hello-world"
+	excerpt := "Short: with a colon"
+	published := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	post := &postDTOs.PostDTO{
+		Title:       "Hello: World",
+		Slug:        &slug,
+		Content:     "\n---\n\nBody with a rule\n",
+		Excerpt:     &excerpt,
+		Status:      "published",
+		PublishedAt: &published,
+		Tags:        []*taxonomyDTOs.TermDTO{{Name: "Go"}, {Name: "Web Dev"}},
+		Categories:  []*taxonomyDTOs.TermDTO{{Name: "News", Slug: "news"}},
+	}
+
+	data, err := EncodeMarkdown(post)
+	if err != nil {
+		t.Fatalf("EncodeMarkdown: %v", err)
+	}
+	parsed, err := ParseMarkdown("hello-world.md", data)
+	if err != nil {
+		t.Fatalf("ParseMarkdown: %v\n%s", err, data)
+	}
+
+	assertImportedPost(t, parsed, &postDTOs.ImportedPostDTO{
+		Source:      "hello-world.md",
+		Title:       "Hello: World",
+		Slug:        slug,
+		Content:     "---\n\nBody with a rule",
+		Excerpt:     &excerpt,
+		Status:      "published",
+		PublishedAt: &published,
+		Tags:        []string{"Go", "Web Dev"},
+		Categories:  []string{"news"},
+	})
+}
+
+func assertImportedPost(t *testing.T, got, want *postDTOs.ImportedPostDTO) {
+	t.Helper()
+
+	fields := []struct {
+		name      string
+		got, want string
+	}{
+		{"Source", got.Source, want.Source},
+		{"Title", got.Title, want.Title},
+		{"Slug", got.Slug, want.Slug},
+		{"Content", got.Content, want.Content},
+		{"Status", got.Status, want.Status},
+		{"Excerpt", stringValue(got.Excerpt), stringValue(want.Excerpt)},
+		{"PublishedAt", timeValue(got.PublishedAt), timeValue(want.PublishedAt)},
+		{"Tags", strings.Join(got.Tags, "|"), strings.Join(want.Tags, "|")},
+		{"Categories", strings.Join(got.Categories, "|"), strings.Join(want.Categories, "|")},
+	}
+	for _, field := range fields {
+		if field.got != field.want {
+			t.Errorf("%s = %q, want %q", field.name, field.got, field.want)
+		}
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
+
+func timeValue(t *time.Time) string {
+	if t == nil {
+		return "<nil>"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,129 @@
+package postio
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/entities"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	contentNamespace = "http://purl.org/rss/1.0/modules/content/"
+	wxrDateLayout    = "2006-01-02 15:04:05"
+)
+
+// wxrDocument is the subset of a WordPress eXtended RSS export needed to import posts.
+// Elements are matched by local name so exports of any WXR version are accepted.
+type wxrDocument struct {
+	Channel struct {
+		Items []wxrItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrItem struct {
+	Title       string        `xml:"title"`
+	PubDate     string        `xml:"pubDate"`
+	Encoded     []wxrEncoded  `xml:"encoded"` // content:encoded and excerpt:encoded
+	PostName    string        `xml:"post_name"`
+	PostType    string        `xml:"post_type"`
+	Status      string        `xml:"status"`
+	PostDateGMT string        `xml:"post_date_gmt"`
+	Categories  []wxrCategory `xml:"category"`
+}
+
+type wxrEncoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type wxrCategory struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+// ParseWXR reads the posts of a WordPress export. Pages, attachments and trashed
+// posts are ignored. WordPress stores post bodies as HTML, which is kept as is
+// since Markdown passes HTML through to the renderer's sanitizer.
+func ParseWXR(source string, data []byte) ([]*postDTOs.ImportedPostDTO, error) {
+	var document wxrDocument
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// WordPress declares encodings such as ISO-8859-1 but exports are UTF-8 in practice
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid WXR export: %w", err)
+	}
+
+	var posts []*postDTOs.ImportedPostDTO
+	for i, item := range document.Channel.Items {
+		if item.PostType != "" && item.PostType != "post" {
+			continue
+		}
+
+		status, ok := wxrStatus(item.Status)
+		if !ok {
+			continue
+		}
+
+		post := &postDTOs.ImportedPostDTO{
+			Source: fmt.Sprintf("%s#item-%d", source, i+1),
+			Title:  strings.TrimSpace(item.Title),
+			Slug:   strings.TrimSpace(item.PostName),
+			Status: string(status),
+		}
+
+		for _, encoded := range item.Encoded {
+			value := strings.TrimSpace(encoded.Value)
+			switch {
+			case encoded.XMLName.Space == contentNamespace:
+				post.Content = value
+			case strings.HasSuffix(encoded.XMLName.Space, "/excerpt/") && value != "":
+				post.Excerpt = &value
+			}
+		}
+
+		for _, category := range item.Categories {
+			switch category.Domain {
+			case "post_tag":
+				post.Tags = append(post.Tags, strings.TrimSpace(category.Name))
+			case "category":
+				post.Categories = append(post.Categories, strings.TrimSpace(category.Nicename))
+			}
+		}
+
+		if status == entities.PostStatusPublished {
+			post.PublishedAt = wxrPublishedAt(item)
+		}
+
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
+
+// wxrStatus maps a WordPress post status. Posts that should not be imported report false.
+func wxrStatus(status string) (entities.PostStatus, bool) {
+	switch status {
+	case "publish":
+		return entities.PostStatusPublished, true
+	case "trash", "auto-draft", "inherit":
+		return "", false
+	default:
+		// draft, pending, future and private posts are imported as drafts for review
+		return entities.PostStatusDraft, true
+	}
+}
+
+func wxrPublishedAt(item wxrItem) *time.Time {
+	if t, err := time.ParseInLocation(wxrDateLayout, strings.TrimSpace(item.PostDateGMT), time.UTC); err == nil && t.Year() > 1 {
+		return &t
+	}
+	if t, err := time.Parse(time.RFC1123Z, strings.TrimSpace(item.PubDate)); err == nil {
+		return &t
+	}
+	return nil
+}
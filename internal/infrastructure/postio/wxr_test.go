@@ -0,0 +1,120 @@
+package postio
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"strings"
+	"testing"
+	"time"
+)
+
+const wxrExport = `<?xml version="1.0" encoding="ISO-8859-1"?>
+<rss version="2.0"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<title>Blog</title>
+	<item>
+		<title> Published post </title>
+		<pubDate>Fri, 01 Mar 2024 10:00:00 +0000</pubDate>
+		<content:encoded><![CDATA[<p>Hello <b>world</b></p>]]></content:encoded>
+		<excerpt:encoded><![CDATA[The excerpt]]></excerpt:encoded>
+		<wp:post_name>published-post</wp:post_name>
+		<wp:post_type>post</wp:post_type>
+		<wp:status>publish</wp:status>
+		<wp:post_date_gmt>2024-03-01 09:30:00</wp:post_date_gmt>
+		<category domain="category" nicename="news"><![CDATA[News]]></category>
+		<category domain="post_tag" nicename="go"><![CDATA[ Go ]]></category>
+	</item>
+	<item>
+		<title>Draft</title>
+		<content:encoded><![CDATA[Draft body]]></content:encoded>
+		<excerpt:encoded><![CDATA[]]></excerpt:encoded>
+		<wp:post_type>post</wp:post_type>
+		<wp:status>pending</wp:status>
+		<wp:post_date_gmt>0000-00-00 00:00:00</wp:post_date_gmt>
+	</item>
+	<item>
+		<title>No GMT date</title>
+		<pubDate>Fri, 01 Mar 2024 10:00:00 +0000</pubDate>
+		<wp:status>publish</wp:status>
+		<wp:post_date_gmt>0000-00-00 00:00:00</wp:post_date_gmt>
+	</item>
+	<item>
+		<title>A page</title>
+		<wp:post_type>page</wp:post_type>
+		<wp:status>publish</wp:status>
+	</item>
+	<item>
+		<title>An attachment</title>
+		<wp:post_type>attachment</wp:post_type>
+		<wp:status>inherit</wp:status>
+	</item>
+	<item>
+		<title>Trashed</title>
+		<wp:post_type>post</wp:post_type>
+		<wp:status>trash</wp:status>
+	</item>
+</channel>
+</rss>`
+
+func TestParseWXR(t *testing.T) {
+	posts, err := ParseWXR("export.xml", []byte(wxrExport))
+	if err != nil {
+		t.Fatalf("ParseWXR: %v", err)
+	}
+
+	gmt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	pubDate := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	excerpt := "The excerpt"
+	want := []*postDTOs.ImportedPostDTO{
+		{
+			Source: "export.xml#item-1", Title: "Published post", Slug: "published-post",
+			Content: "<p>Hello <b>world</b></p>", Excerpt: &excerpt, Status: "published",
+			PublishedAt: &gmt, Tags: []string{"Go"}, Categories: []string{"news"},
+		},
+		{Source: "export.xml#item-2", Title: "Draft", Content: "Draft body", Status: "draft"},
+		{Source: "export.xml#item-3", Title: "No GMT date", Status: "published", PublishedAt: &pubDate},
+	}
+
+	if len(posts) != len(want) {
+		t.Fatalf("got %d posts, want %d", len(posts), len(want))
+	}
+	for i := range want {
+		t.Run(want[i].Source, func(t *testing.T) {
+			assertImportedPost(t, posts[i], want[i])
+		})
+	}
+}
+
+func TestParseWXR_Status(t *testing.T) {
+	tests := []struct {
+		status   string
+		want     string
+		imported bool
+	}{
+		{status: "publish", want: "published", imported: true},
+		{status: "draft", want: "draft", imported: true},
+		{status: "pending", want: "draft", imported: true},
+		{status: "future", want: "draft", imported: true},
+		{status: "private", want: "draft", imported: true},
+		{status: "trash"},
+		{status: "auto-draft"},
+		{status: "inherit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			status, ok := wxrStatus(tt.status)
+			if ok != tt.imported || string(status) != tt.want {
+				t.Fatalf("wxrStatus(%q) = %q, %v, want %q, %v", tt.status, status, ok, tt.want, tt.imported)
+			}
+		})
+	}
+}
+
+func TestParseWXR_Invalid(t *testing.T) {
+	_, err := ParseWXR("broken.xml", []byte("<rss><channel><item>"))
+	if err == nil || !strings.Contains(err.Error(), "invalid WXR export") {
+		t.Fatalf("expected an invalid WXR export error, got %v", err)
+	}
+}
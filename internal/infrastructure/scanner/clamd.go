@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"app05/internal/core/application/contracts"
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunkSize is the size of the chunks files are streamed to clamd in
+const clamdChunkSize = 64 << 10
+
+// ClamdScanner scans files with a ClamAV daemon, streaming them over its INSTREAM
+// command. Files larger than the daemon's StreamMaxLength fail to scan, so maxSize
+// should match it.
+type ClamdScanner struct {
+	network string // "tcp" or "unix"
+	address string
+	timeout time.Duration
+	maxSize int64 // negative for unlimited
+}
+
+func NewClamdScanner(network, address string, timeout time.Duration, maxSize int64) *ClamdScanner {
+	return &ClamdScanner{
+		network: network,
+		address: address,
+		timeout: timeout,
+		maxSize: maxSize,
+	}
+}
+
+func (s *ClamdScanner) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Ping checks that the daemon is reachable
+func (s *ClamdScanner) Ping(ctx context.Context) error {
+	reply, err := s.command(ctx, "PING", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("unexpected clamd reply %q", reply)
+	}
+	return nil
+}
+
+func (s *ClamdScanner) Scan(ctx context.Context, body io.Reader) (*contracts.ScanResult, error) {
+	reply, err := s.command(ctx, "INSTREAM", body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replies are "stream: OK", "stream: <signature> FOUND" or "<message> ERROR"
+	result := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case result == "OK":
+		return &contracts.ScanResult{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return &contracts.ScanResult{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	case strings.Contains(result, "size limit exceeded"):
+		return nil, contracts.ErrScanTooLarge
+	default:
+		return nil, fmt.Errorf("clamd scan failed: %s", reply)
+	}
+}
+
+// command sends a command, followed by body as a stream when there is one, and returns
+// the daemon's reply
+func (s *ClamdScanner) command(ctx context.Context, name string, body io.Reader) (string, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(s.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", err
+	}
+
+	// The z prefix selects null-terminated commands and replies
+	if _, err := io.WriteString(conn, "z"+name+"\x00"); err != nil {
+		return "", err
+	}
+	if body != nil {
+		if err := writeStream(conn, body, s.maxSize); err != nil {
+			if errors.Is(err, contracts.ErrScanTooLarge) {
+				return "", err
+			}
+			// The daemon closes the connection on streams over its limit, after replying why
+			if reply, replyErr := readReply(conn); replyErr == nil {
+				return reply, nil
+			}
+			return "", err
+		}
+	}
+
+	return readReply(conn)
+}
+
+// writeStream sends body as length-prefixed chunks, ending with an empty chunk. Bodies
+// longer than maxSize are cut off with contracts.ErrScanTooLarge.
+func writeStream(conn net.Conn, body io.Reader, maxSize int64) error {
+	chunk := make([]byte, 4+clamdChunkSize)
+	sent := int64(0)
+	for {
+		n, err := io.ReadFull(body, chunk[4:])
+		sent += int64(n)
+		if maxSize >= 0 && sent > maxSize {
+			return contracts.ErrScanTooLarge
+		}
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk[:4], uint32(n))
+			if _, writeErr := conn.Write(chunk[:4+n]); writeErr != nil {
+				return writeErr
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := conn.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+func readReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !(errors.Is(err, io.EOF) && reply != "") {
+		return "", fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return strings.TrimSpace(strings.TrimSuffix(reply, "\x00")), nil
+}
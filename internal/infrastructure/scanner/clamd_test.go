@@ -0,0 +1,179 @@
+package scanner
+
+import (
+	"app05/internal/core/application/contracts"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd serves the INSTREAM and PING commands of a ClamAV daemon, answering scans
+// with the reply returned by respond for the streamed file
+func fakeClamd(t *testing.T, respond func(file []byte) string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveClamd(conn, respond)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveClamd(conn net.Conn, respond func(file []byte) string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	command, err := reader.ReadString(0)
+	if err != nil {
+		return
+	}
+	switch strings.TrimSuffix(command, "\x00") {
+	case "zPING":
+		io.WriteString(conn, "PONG\x00")
+	case "zINSTREAM":
+		var file bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&file, reader, int64(size)); err != nil {
+				return
+			}
+		}
+		io.WriteString(conn, respond(file.Bytes())+"\x00")
+	default:
+		io.WriteString(conn, "UNKNOWN COMMAND\x00")
+	}
+}
+
+func TestClamdScanner_Scan(t *testing.T) {
+	address := fakeClamd(t, func(file []byte) string {
+		switch {
+		case bytes.Contains(file, []byte("EICAR")):
+			return "stream: Eicar-Test-Signature FOUND"
+		case bytes.Contains(file, []byte("broken")):
+			return "Can't allocate memory ERROR"
+		case len(file) > 100:
+			return "INSTREAM size limit exceeded. ERROR"
+		default:
+			return "stream: OK"
+		}
+	})
+	scanner := NewClamdScanner("tcp", address, 5*time.Second, -1)
+
+	tests := []struct {
+		name      string
+		body      string
+		infected  bool
+		signature string
+		err       error
+	}{
+		{name: "clean", body: "hello world"},
+		{name: "empty", body: ""},
+		{name: "infected", body: "X5O!P%@AP EICAR", infected: true, signature: "Eicar-Test-Signature"},
+		{name: "daemon error", body: "broken", err: errors.New("clamd scan failed")},
+		{name: "over the daemon's limit", body: strings.Repeat("a", 101), err: contracts.ErrScanTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := scanner.Scan(context.Background(), strings.NewReader(tt.body))
+			switch {
+			case tt.err == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case errors.Is(tt.err, contracts.ErrScanTooLarge):
+				if !errors.Is(err, contracts.ErrScanTooLarge) {
+					t.Fatalf("expected ErrScanTooLarge, got %v", err)
+				}
+				return
+			case tt.err != nil:
+				if err == nil || !strings.Contains(err.Error(), tt.err.Error()) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+
+			if result.Infected != tt.infected || result.Signature != tt.signature {
+				t.Fatalf("got infected=%v signature=%q, want infected=%v signature=%q",
+					result.Infected, result.Signature, tt.infected, tt.signature)
+			}
+		})
+	}
+}
+
+func TestClamdScanner_ScanStreamsLargeFiles(t *testing.T) {
+	received := make(chan int, 1)
+	address := fakeClamd(t, func(file []byte) string {
+		received <- len(file)
+		return "stream: OK"
+	})
+	scanner := NewClamdScanner("tcp", address, 5*time.Second, -1)
+
+	// Larger than one chunk, so the file is sent in several
+	body := bytes.Repeat([]byte("a"), 3*clamdChunkSize+17)
+	if _, err := scanner.Scan(context.Background(), bytes.NewReader(body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := <-received; got != len(body) {
+		t.Fatalf("daemon received %d bytes, want %d", got, len(body))
+	}
+}
+
+func TestClamdScanner_MaxSize(t *testing.T) {
+	address := fakeClamd(t, func(file []byte) string { return "stream: OK" })
+	scanner := NewClamdScanner("tcp", address, 5*time.Second, 10)
+
+	if got := scanner.MaxSize(); got != 10 {
+		t.Fatalf("MaxSize() = %d, want 10", got)
+	}
+	if _, err := scanner.Scan(context.Background(), strings.NewReader("0123456789")); err != nil {
+		t.Fatalf("file at the limit: unexpected error: %v", err)
+	}
+	if _, err := scanner.Scan(context.Background(), strings.NewReader("0123456789a")); !errors.Is(err, contracts.ErrScanTooLarge) {
+		t.Fatalf("file over the limit: expected ErrScanTooLarge, got %v", err)
+	}
+}
+
+func TestClamdScanner_Ping(t *testing.T) {
+	address := fakeClamd(t, func(file []byte) string { return "stream: OK" })
+
+	if err := NewClamdScanner("tcp", address, 5*time.Second, -1).Ping(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClamdScanner_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	_, err = NewClamdScanner("tcp", address, time.Second, -1).Scan(context.Background(), strings.NewReader("hello"))
+	if err == nil || errors.Is(err, contracts.ErrScanTooLarge) {
+		t.Fatalf("expected a connection error, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package scanner
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/config"
+	"context"
+	"fmt"
+	"io"
+)
+
+// New creates the scanner selected by the configuration
+func New(cfg config.ScannerConfig) (contracts.Scanner, error) {
+	switch cfg.Driver {
+	case "none":
+		return Disabled{}, nil
+	case "clamd":
+		return NewClamdScanner(cfg.Clamd.Network, cfg.Clamd.Address, cfg.Clamd.Timeout, cfg.Clamd.MaxSize), nil
+	default:
+		return nil, fmt.Errorf("unknown scanner driver %q", cfg.Driver)
+	}
+}
+
+// Disabled reports every file clean without scanning it, for setups without a scanner
+type Disabled struct{}
+
+func (Disabled) Scan(ctx context.Context, body io.Reader) (*contracts.ScanResult, error) {
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return nil, err
+	}
+	return &contracts.ScanResult{}, nil
+}
+
+func (Disabled) MaxSize() int64 {
+	return -1
+}
@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/services"
+	"context"
+	"time"
+)
+
+// ImageVariantRequeueJob periodically hands the image variant workers the images they
+// have not processed, e.g. those queued while they were busy or before a restart
+type ImageVariantRequeueJob struct {
+	variantService *services.ImageVariantService
+	logger         contracts.Logger
+	interval       time.Duration
+}
+
+func NewImageVariantRequeueJob(
+	variantService *services.ImageVariantService,
+	logger contracts.Logger,
+	interval time.Duration,
+) *ImageVariantRequeueJob {
+	return &ImageVariantRequeueJob{
+		variantService: variantService,
+		logger:         logger,
+		interval:       interval,
+	}
+}
+
+func (j *ImageVariantRequeueJob) Name() string {
+	return "image_variant_requeue"
+}
+
+func (j *ImageVariantRequeueJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *ImageVariantRequeueJob) Run(ctx context.Context) error {
+	requeued, err := j.variantService.RequeuePending(ctx)
+	if err != nil {
+		return err
+	}
+	if requeued > 0 {
+		j.logger.Info("Requeued pending image variants", "images", requeued)
+	}
+	return nil
+}
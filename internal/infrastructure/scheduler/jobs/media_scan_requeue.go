@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/services"
+	"context"
+	"time"
+)
+
+// MediaScanRequeueJob periodically hands the malware scan workers the media they have not
+// scanned, e.g. files uploaded while they were busy or before a restart. Unscanned files
+// stored outside quarantine are moved into it first.
+type MediaScanRequeueJob struct {
+	scanService *services.MediaScanService
+	logger      contracts.Logger
+	interval    time.Duration
+}
+
+func NewMediaScanRequeueJob(
+	scanService *services.MediaScanService,
+	logger contracts.Logger,
+	interval time.Duration,
+) *MediaScanRequeueJob {
+	return &MediaScanRequeueJob{
+		scanService: scanService,
+		logger:      logger,
+		interval:    interval,
+	}
+}
+
+func (j *MediaScanRequeueJob) Name() string {
+	return "media_scan_requeue"
+}
+
+func (j *MediaScanRequeueJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *MediaScanRequeueJob) Run(ctx context.Context) error {
+	quarantined, err := j.scanService.QuarantineUnscanned(ctx)
+	if quarantined > 0 {
+		j.logger.Info("Quarantined unscanned media", "media", quarantined)
+	}
+	if err != nil {
+		return err
+	}
+
+	requeued, err := j.scanService.RequeuePending(ctx)
+	if err != nil {
+		return err
+	}
+	if requeued > 0 {
+		j.logger.Info("Requeued pending media scans", "media", requeued)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/cache"
+	"context"
+	"time"
+)
+
+const reactionReconcileBatchSize = 500
+
+// ReactionReconcileJob periodically rewrites the cached reaction counts of recently
+// changed posts from Postgres, correcting any drift between the cache and the database
+type ReactionReconcileJob struct {
+	counter      *cache.ReactionCounter
+	reactionRepo repositories.ReactionRepository
+	logger       contracts.Logger
+	interval     time.Duration
+}
+
+func NewReactionReconcileJob(
+	counter *cache.ReactionCounter,
+	reactionRepo repositories.ReactionRepository,
+	logger contracts.Logger,
+	interval time.Duration,
+) *ReactionReconcileJob {
+	return &ReactionReconcileJob{
+		counter:      counter,
+		reactionRepo: reactionRepo,
+		logger:       logger,
+		interval:     interval,
+	}
+}
+
+func (j *ReactionReconcileJob) Name() string {
+	return "post_reaction_reconcile"
+}
+
+func (j *ReactionReconcileJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *ReactionReconcileJob) Run(ctx context.Context) error {
+	reconciled := 0
+	for {
+		postIDs, err := j.counter.PopTouched(ctx, reactionReconcileBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(postIDs) == 0 {
+			break
+		}
+
+		if err := j.reconcile(ctx, postIDs); err != nil {
+			// Put the batch back so it is retried on the next run
+			if markErr := j.counter.MarkTouched(ctx, postIDs); markErr != nil {
+				j.logger.Error("Failed to requeue posts for reaction reconciliation", "error", markErr)
+			}
+			return err
+		}
+		reconciled += len(postIDs)
+	}
+
+	if reconciled > 0 {
+		j.logger.Info("Reconciled cached reaction counts", "posts", reconciled)
+	}
+	return nil
+}
+
+func (j *ReactionReconcileJob) reconcile(ctx context.Context, postIDs []int) error {
+	counts, err := j.reactionRepo.GetReactionCounts(ctx, postIDs)
+	if err != nil {
+		return err
+	}
+
+	return j.counter.Set(ctx, postIDs, counts)
+}
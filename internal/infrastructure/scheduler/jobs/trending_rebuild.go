@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/services"
+	"context"
+	"errors"
+	"time"
+)
+
+// TrendingRebuildJob rebuilds the trending leaderboards once a night. It checks every
+// hour and rebuilds on the first check within the configured hour of the day.
+type TrendingRebuildJob struct {
+	trendingService *services.TrendingService
+	logger          contracts.Logger
+	hour            int
+	lastRun         time.Time
+}
+
+func NewTrendingRebuildJob(trendingService *services.TrendingService, logger contracts.Logger, hour int) *TrendingRebuildJob {
+	return &TrendingRebuildJob{
+		trendingService: trendingService,
+		logger:          logger,
+		hour:            hour,
+	}
+}
+
+func (j *TrendingRebuildJob) Name() string {
+	return "trending_rebuild"
+}
+
+func (j *TrendingRebuildJob) Interval() time.Duration {
+	return time.Hour
+}
+
+func (j *TrendingRebuildJob) Run(ctx context.Context) error {
+	now := time.Now()
+	if now.Hour() != j.hour || sameDay(j.lastRun, now) {
+		return nil
+	}
+
+	if err := j.trendingService.Rebuild(ctx); err != nil {
+		// Another instance is rebuilding
+		if errors.Is(err, cache.ErrTrendingRebuildRunning) {
+			j.lastRun = now
+			return nil
+		}
+		return err
+	}
+
+	j.lastRun = now
+	return nil
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/infrastructure/services"
+	"context"
+	"time"
+)
+
+// UploadCleanupJob periodically deletes the chunks of resumable uploads that expired
+// before they were completed
+type UploadCleanupJob struct {
+	uploadService *services.UploadService
+	logger        contracts.Logger
+	interval      time.Duration
+}
+
+func NewUploadCleanupJob(
+	uploadService *services.UploadService,
+	logger contracts.Logger,
+	interval time.Duration,
+) *UploadCleanupJob {
+	return &UploadCleanupJob{
+		uploadService: uploadService,
+		logger:        logger,
+		interval:      interval,
+	}
+}
+
+func (j *UploadCleanupJob) Name() string {
+	return "upload_cleanup"
+}
+
+func (j *UploadCleanupJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *UploadCleanupJob) Run(ctx context.Context) error {
+	removed, err := j.uploadService.RemoveOrphanedChunks(ctx)
+	if err != nil {
+		return err
+	}
+	if removed > 0 {
+		j.logger.Info("Removed chunks of expired uploads", "chunks", removed)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/cache"
+	"context"
+	"errors"
+	"time"
+)
+
+// ViewFlushJob periodically writes the view counts buffered in Redis to Postgres
+type ViewFlushJob struct {
+	viewCounter *cache.ViewCounter
+	postRepo    repositories.PostRepository
+	logger      contracts.Logger
+	interval    time.Duration
+}
+
+func NewViewFlushJob(
+	viewCounter *cache.ViewCounter,
+	postRepo repositories.PostRepository,
+	logger contracts.Logger,
+	interval time.Duration,
+) *ViewFlushJob {
+	return &ViewFlushJob{
+		viewCounter: viewCounter,
+		postRepo:    postRepo,
+		logger:      logger,
+		interval:    interval,
+	}
+}
+
+func (j *ViewFlushJob) Name() string {
+	return "post_view_flush"
+}
+
+func (j *ViewFlushJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *ViewFlushJob) Run(ctx context.Context) error {
+	token, err := j.viewCounter.LockFlush(ctx)
+	if err != nil {
+		// Another instance is flushing
+		if errors.Is(err, cache.ErrViewFlushRunning) {
+			return nil
+		}
+		return err
+	}
+	defer func() {
+		if err := j.viewCounter.UnlockFlush(context.WithoutCancel(ctx), token); err != nil {
+			j.logger.Warn("Failed to release view flush lock", "error", err)
+		}
+	}()
+
+	counts, err := j.viewCounter.DrainBuffer(ctx)
+	if err != nil {
+		return err
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	// On failure the drained snapshot is kept and retried on the next run
+	if err := j.postRepo.IncrementViewCounts(ctx, counts); err != nil {
+		return err
+	}
+
+	if err := j.viewCounter.AckDrain(ctx, token); err != nil {
+		return err
+	}
+
+	j.logger.Info("Flushed buffered post views", "posts", len(counts))
+	return nil
+}
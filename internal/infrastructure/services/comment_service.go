@@ -0,0 +1,226 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/commentDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"math"
+	"strings"
+	"time"
+)
+
+type CommentService struct {
+	commentRepo repositories.CommentRepository
+	postRepo    repositories.PostRepository
+	limiter     contracts.Limiter
+	trending    *TrendingTracker
+	logger      contracts.Logger
+}
+
+func NewCommentService(
+	commentRepo repositories.CommentRepository,
+	postRepo repositories.PostRepository,
+	limiter contracts.Limiter,
+	trending *TrendingTracker,
+	logger contracts.Logger,
+) *CommentService {
+	return &CommentService{
+		commentRepo: commentRepo,
+		postRepo:    postRepo,
+		limiter:     limiter,
+		trending:    trending,
+		logger:      logger,
+	}
+}
+
+// GetPostComments returns a page of approved comment threads on a published post
+func (s *CommentService) GetPostComments(ctx context.Context, postSlug string, userID uuid.UUID, role entities.Role, page, perPage int) ([]*commentDTOs.CommentDTO, int, error) {
+	post, err := s.postRepo.GetPublishedPostBySlug(ctx, postSlug)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := checkPostVisible(post, userID, role); err != nil {
+		return nil, 0, err
+	}
+
+	return s.commentRepo.GetPostThreads(ctx, post.ID, perPage, utils.Offset(page, perPage))
+}
+
+// CreateComment adds a comment or a reply to a published post. Comments from
+// staff are published immediately, everyone else's go through moderation.
+func (s *CommentService) CreateComment(ctx context.Context, postSlug string, userID uuid.UUID, role entities.Role, input commentDTOs.CreateCommentRequest) (*commentDTOs.CommentDTO, error) {
+	if allowed, retryAfter := s.limiter.Allow(userID.String()); !allowed {
+		return nil, appErrors.New(appErrors.CodeTooManyRequests,
+			fmt.Sprintf("You are commenting too fast. Try again in %d seconds", int(math.Ceil(retryAfter.Seconds()))))
+	}
+
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "comment body cannot be blank")
+	}
+
+	post, err := s.postRepo.GetPublishedPostBySlug(ctx, postSlug)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPostVisible(post, userID, role); err != nil {
+		return nil, err
+	}
+
+	if input.ParentID != nil {
+		parent, err := s.commentRepo.GetCommentByID(ctx, *input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.PostID != post.ID {
+			return nil, appErrors.New(appErrors.CodeBadRequest, "parent comment belongs to a different post")
+		}
+		// Threads are one level deep: replies cannot be replied to
+		if parent.ParentID != nil {
+			return nil, appErrors.New(appErrors.CodeBadRequest, "replies cannot be nested")
+		}
+		if parent.Deleted || parent.Status != string(entities.CommentStatusApproved) {
+			return nil, appErrors.New(appErrors.CodeBadRequest, "cannot reply to this comment")
+		}
+	}
+
+	// Moderators' own comments need no moderation
+	status := entities.CommentStatusPending
+	if role.IsAdmin() {
+		status = entities.CommentStatusApproved
+	}
+
+	comment := &commentDTOs.CommentDTO{
+		PostID:   post.ID,
+		ParentID: input.ParentID,
+		Author:   commentDTOs.CommentAuthorDTO{ID: userID.String()},
+		Body:     body,
+		Status:   string(status),
+	}
+
+	if err := s.commentRepo.CreateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	if status == entities.CommentStatusApproved {
+		s.trending.RecordComment(ctx, post)
+	}
+
+	return s.commentRepo.GetCommentByID(ctx, comment.ID)
+}
+
+// UpdateComment lets the author change a comment within the edit window. An approved
+// comment edited by anyone but a moderator goes back through moderation.
+func (s *CommentService) UpdateComment(ctx context.Context, id int, userID uuid.UUID, role entities.Role, input commentDTOs.UpdateCommentRequest) (*commentDTOs.CommentDTO, error) {
+	comment, err := s.commentRepo.GetCommentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.Author.ID != userID.String() {
+		return nil, appErrors.New(appErrors.CodeForbidden, "You can only edit your own comments")
+	}
+	if comment.Deleted {
+		return nil, appErrors.New(appErrors.CodeNotFound, "comment not found")
+	}
+	if time.Since(comment.CreatedAt) > entities.CommentEditWindow {
+		return nil, appErrors.New(appErrors.CodeForbidden,
+			fmt.Sprintf("Comments can only be edited within %d minutes of posting", int(entities.CommentEditWindow.Minutes())))
+	}
+
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "comment body cannot be blank")
+	}
+
+	status := entities.CommentStatus(comment.Status)
+	if status == entities.CommentStatusApproved && !role.IsAdmin() {
+		status = entities.CommentStatusPending
+	}
+
+	if err := s.commentRepo.UpdateCommentBody(ctx, id, body, status); err != nil {
+		return nil, err
+	}
+
+	if string(status) != comment.Status {
+		s.removeFromTrending(ctx, comment)
+	}
+
+	return s.commentRepo.GetCommentByID(ctx, id)
+}
+
+// DeleteComment soft deletes a comment. Authors can delete their own comments, admins any comment.
+func (s *CommentService) DeleteComment(ctx context.Context, id int, userID uuid.UUID, role entities.Role) error {
+	comment, err := s.commentRepo.GetCommentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if comment.Author.ID != userID.String() && !role.IsAdmin() {
+		return appErrors.New(appErrors.CodeForbidden, "You can only delete your own comments")
+	}
+
+	if err := s.commentRepo.SoftDeleteComment(ctx, id); err != nil {
+		return err
+	}
+	if !comment.Deleted && comment.Status == string(entities.CommentStatusApproved) {
+		s.removeFromTrending(ctx, comment)
+	}
+	return nil
+}
+
+// GetModerationQueue returns a page of comments awaiting a moderation decision, oldest first
+func (s *CommentService) GetModerationQueue(ctx context.Context, status entities.CommentStatus, page, perPage int) ([]*commentDTOs.CommentDTO, int, error) {
+	switch status {
+	case entities.CommentStatusPending, entities.CommentStatusApproved, entities.CommentStatusRejected, entities.CommentStatusSpam:
+	default:
+		return nil, 0, appErrors.New(appErrors.CodeBadRequest, "invalid comment status")
+	}
+
+	return s.commentRepo.GetCommentsByStatus(ctx, status, perPage, utils.Offset(page, perPage))
+}
+
+// ModerateComment approves, rejects or flags a comment as spam
+func (s *CommentService) ModerateComment(ctx context.Context, id int, moderatorID uuid.UUID, input commentDTOs.ModerateCommentRequest) (*commentDTOs.CommentDTO, error) {
+	comment, err := s.commentRepo.GetCommentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	status := entities.CommentStatus(input.Status)
+	if err := s.commentRepo.ModerateComment(ctx, id, status, moderatorID); err != nil {
+		return nil, err
+	}
+
+	// A comment counts towards trending once it is approved
+	if status == entities.CommentStatusApproved && comment.Status != string(entities.CommentStatusApproved) && !comment.Deleted {
+		if post, err := s.postRepo.GetPostByID(ctx, comment.PostID); err != nil {
+			s.logger.Warn("Failed to load post of approved comment", "commentID", id, "error", err)
+		} else if post.Status == string(entities.PostStatusPublished) {
+			s.trending.RecordComment(ctx, post)
+		}
+	}
+	// and stops counting once it is no longer
+	if status != entities.CommentStatusApproved && comment.Status == string(entities.CommentStatusApproved) && !comment.Deleted {
+		s.removeFromTrending(ctx, comment)
+	}
+
+	return s.commentRepo.GetCommentByID(ctx, id)
+}
+
+// removeFromTrending takes back an approved comment's trending activity
+func (s *CommentService) removeFromTrending(ctx context.Context, comment *commentDTOs.CommentDTO) {
+	post, err := s.postRepo.GetPostByID(ctx, comment.PostID)
+	if err != nil {
+		s.logger.Warn("Failed to load post of removed comment", "commentID", comment.ID, "error", err)
+		return
+	}
+	if post.Status == string(entities.PostStatusPublished) {
+		s.trending.RemoveComment(ctx, post, comment.CreatedAt)
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/markdown"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// ContentRenderer fills in the HTML, excerpt, reading time and table of contents
+// of posts from their Markdown content, reusing cached output when available
+type ContentRenderer struct {
+	renderer *markdown.Renderer
+	cache    *cache.RenderCache
+	logger   contracts.Logger
+}
+
+func NewContentRenderer(renderer *markdown.Renderer, cache *cache.RenderCache, logger contracts.Logger) *ContentRenderer {
+	return &ContentRenderer{
+		renderer: renderer,
+		cache:    cache,
+		logger:   logger,
+	}
+}
+
+// RenderPosts renders each post in place
+func (c *ContentRenderer) RenderPosts(ctx context.Context, posts []*postDTOs.PostDTO) error {
+	for _, post := range posts {
+		if err := c.RenderPost(ctx, post); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderPost renders a post in place. An empty excerpt is replaced by one generated from the content.
+func (c *ContentRenderer) RenderPost(ctx context.Context, post *postDTOs.PostDTO) error {
+	rendered, err := c.Render(ctx, post.Content)
+	if err != nil {
+		return err
+	}
+
+	post.ContentHTML = rendered.HTML
+	post.ReadingTime = rendered.ReadingTimeMinutes
+	post.TOC = rendered.TableOfContents
+	if post.Excerpt == nil || *post.Excerpt == "" {
+		excerpt := rendered.Excerpt
+		post.Excerpt = &excerpt
+	}
+
+	return nil
+}
+
+// Render converts Markdown to sanitized HTML. Cache failures are logged and fall back to rendering.
+func (c *ContentRenderer) Render(ctx context.Context, content string) (*markdown.Rendered, error) {
+	sum := sha256.Sum256([]byte(content))
+	hash := hex.EncodeToString(sum[:])
+
+	cached, err := c.cache.Get(ctx, hash)
+	if err != nil {
+		c.logger.Warn("Failed to read rendered content from cache", "error", err)
+	}
+	if cached != nil {
+		return cached, nil
+	}
+
+	rendered, err := c.renderer.Render(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.cache.Set(ctx, hash, rendered); err != nil {
+		c.logger.Warn("Failed to cache rendered content", "error", err)
+	}
+
+	return rendered, nil
+}
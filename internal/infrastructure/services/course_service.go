@@ -0,0 +1,395 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/courseDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"context"
+	"github.com/google/uuid"
+	"strings"
+)
+
+// CourseService manages courses and their modules and lessons. Instructors manage
+// their own courses, admins any course.
+type CourseService struct {
+	courseRepo repositories.CourseRepository
+	logger     contracts.Logger
+}
+
+func NewCourseService(courseRepo repositories.CourseRepository, logger contracts.Logger) *CourseService {
+	return &CourseService{
+		courseRepo: courseRepo,
+		logger:     logger,
+	}
+}
+
+// GetPublishedCourses returns a page of published courses
+func (s *CourseService) GetPublishedCourses(ctx context.Context, page, perPage int) ([]*entities.Course, int, error) {
+	return s.courseRepo.GetPublishedCourses(ctx, perPage, utils.Offset(page, perPage))
+}
+
+// GetCourseBySlug returns a published course with the outline of its published modules and lessons
+func (s *CourseService) GetCourseBySlug(ctx context.Context, slug string) (*entities.Course, error) {
+	course, err := s.getPublishedCourse(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachOutline(ctx, course, true); err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+// GetPublishedLesson returns a published lesson of a published course with its content
+func (s *CourseService) GetPublishedLesson(ctx context.Context, slug string, lessonID int) (*entities.Lesson, error) {
+	course, err := s.getPublishedCourse(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	lesson, err := s.courseRepo.GetLessonByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.CourseID != course.ID || lesson.Status != entities.CourseStatusPublished {
+		return nil, appErrors.New(appErrors.CodeNotFound, "lesson not found")
+	}
+
+	module, err := s.courseRepo.GetModuleByID(ctx, lesson.ModuleID)
+	if err != nil {
+		return nil, err
+	}
+	if module.Status != entities.CourseStatusPublished {
+		return nil, appErrors.New(appErrors.CodeNotFound, "lesson not found")
+	}
+
+	return lesson, nil
+}
+
+// GetManagedCourses returns a page of the user's own courses, drafts included
+func (s *CourseService) GetManagedCourses(ctx context.Context, userID uuid.UUID, page, perPage int) ([]*entities.Course, int, error) {
+	return s.courseRepo.GetCoursesByUser(ctx, userID, perPage, utils.Offset(page, perPage))
+}
+
+// GetManagedCourse returns a course with all of its modules and lessons, drafts included, for its owner or an admin
+func (s *CourseService) GetManagedCourse(ctx context.Context, id int, userID uuid.UUID, role entities.Role) (*entities.Course, error) {
+	if _, err := s.getOwnedCourse(ctx, id, userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.managedCourse(ctx, id)
+}
+
+// CreateCourse creates a draft course owned by the user
+func (s *CourseService) CreateCourse(ctx context.Context, userID uuid.UUID, input courseDTOs.CreateCourseRequest) (*entities.Course, error) {
+	title := strings.TrimSpace(input.Title)
+	slug, err := uniqueSlug(ctx, title, s.courseRepo.CourseSlugExists)
+	if err != nil {
+		return nil, err
+	}
+
+	course := &entities.Course{
+		UserID:      userID.String(),
+		Title:       title,
+		Slug:        slug,
+		Description: trimmedOrNil(input.Description),
+		Status:      entities.CourseStatusDraft,
+	}
+
+	if err := s.courseRepo.CreateCourse(ctx, course); err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+func (s *CourseService) UpdateCourse(ctx context.Context, id int, userID uuid.UUID, role entities.Role, input courseDTOs.UpdateCourseRequest) (*entities.Course, error) {
+	course, err := s.getOwnedCourse(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title != course.Title {
+		course.Slug, err = uniqueSlug(ctx, title, s.courseRepo.CourseSlugExists)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	course.Title = title
+	course.Description = trimmedOrNil(input.Description)
+	course.Status = entities.CourseStatus(input.Status)
+
+	if err := s.courseRepo.UpdateCourse(ctx, course); err != nil {
+		return nil, err
+	}
+
+	return s.managedCourse(ctx, id)
+}
+
+// UpdateEnrollmentSettings sets whether students may enroll themselves, how many may
+// enroll and the course's dates
+func (s *CourseService) UpdateEnrollmentSettings(ctx context.Context, id int, userID uuid.UUID, role entities.Role, input courseDTOs.UpdateEnrollmentSettingsRequest) (*entities.Course, error) {
+	course, err := s.getOwnedCourse(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.StartsAt != nil && input.EndsAt != nil && !input.EndsAt.After(*input.StartsAt) {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "ends_at must be after starts_at")
+	}
+
+	course.IsFree = input.IsFree
+	course.EnrollmentLimit = input.EnrollmentLimit
+	course.StartsAt = input.StartsAt
+	course.EndsAt = input.EndsAt
+
+	if err := s.courseRepo.UpdateEnrollmentSettings(ctx, course); err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+// DeleteCourse removes a course with its modules and lessons
+func (s *CourseService) DeleteCourse(ctx context.Context, id int, userID uuid.UUID, role entities.Role) error {
+	if _, err := s.getOwnedCourse(ctx, id, userID, role); err != nil {
+		return err
+	}
+
+	return s.courseRepo.DeleteCourse(ctx, id)
+}
+
+// CreateModule adds a draft module to a course
+func (s *CourseService) CreateModule(ctx context.Context, courseID int, userID uuid.UUID, role entities.Role, input courseDTOs.CreateModuleRequest) (*entities.Module, error) {
+	if _, err := s.getOwnedCourse(ctx, courseID, userID, role); err != nil {
+		return nil, err
+	}
+
+	module := &entities.Module{
+		CourseID:    courseID,
+		Title:       strings.TrimSpace(input.Title),
+		Description: trimmedOrNil(input.Description),
+		Status:      entities.CourseStatusDraft,
+		Lessons:     []*entities.Lesson{},
+	}
+
+	if err := s.courseRepo.CreateModule(ctx, module, input.Position); err != nil {
+		return nil, err
+	}
+
+	return module, nil
+}
+
+func (s *CourseService) UpdateModule(ctx context.Context, courseID, moduleID int, userID uuid.UUID, role entities.Role, input courseDTOs.UpdateModuleRequest) (*entities.Module, error) {
+	module, err := s.getOwnedModule(ctx, courseID, moduleID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	module.Title = strings.TrimSpace(input.Title)
+	module.Description = trimmedOrNil(input.Description)
+	module.Status = entities.CourseStatus(input.Status)
+
+	if err := s.courseRepo.UpdateModule(ctx, module); err != nil {
+		return nil, err
+	}
+
+	return module, nil
+}
+
+// DeleteModule removes a module with its lessons
+func (s *CourseService) DeleteModule(ctx context.Context, courseID, moduleID int, userID uuid.UUID, role entities.Role) error {
+	if _, err := s.getOwnedModule(ctx, courseID, moduleID, userID, role); err != nil {
+		return err
+	}
+
+	return s.courseRepo.DeleteModule(ctx, moduleID)
+}
+
+// ReorderModules puts the modules of a course in the given order
+func (s *CourseService) ReorderModules(ctx context.Context, courseID int, userID uuid.UUID, role entities.Role, moduleIDs []int) (*entities.Course, error) {
+	if _, err := s.getOwnedCourse(ctx, courseID, userID, role); err != nil {
+		return nil, err
+	}
+
+	if err := s.courseRepo.ReorderModules(ctx, courseID, moduleIDs); err != nil {
+		return nil, err
+	}
+
+	return s.managedCourse(ctx, courseID)
+}
+
+// CreateLesson adds a draft lesson to a module of a course
+func (s *CourseService) CreateLesson(ctx context.Context, courseID, moduleID int, userID uuid.UUID, role entities.Role, input courseDTOs.CreateLessonRequest) (*entities.Lesson, error) {
+	if _, err := s.getOwnedModule(ctx, courseID, moduleID, userID, role); err != nil {
+		return nil, err
+	}
+
+	lesson := &entities.Lesson{
+		ModuleID:        moduleID,
+		Title:           strings.TrimSpace(input.Title),
+		Content:         input.Content,
+		DurationMinutes: input.DurationMinutes,
+		IsRequired:      input.IsRequired == nil || *input.IsRequired,
+		Status:          entities.CourseStatusDraft,
+	}
+
+	if err := s.courseRepo.CreateLesson(ctx, lesson, input.Position); err != nil {
+		return nil, err
+	}
+
+	return lesson, nil
+}
+
+// GetManagedLesson returns a lesson with its content, drafts included, for the course owner or an admin
+func (s *CourseService) GetManagedLesson(ctx context.Context, courseID, moduleID, lessonID int, userID uuid.UUID, role entities.Role) (*entities.Lesson, error) {
+	return s.getOwnedLesson(ctx, courseID, moduleID, lessonID, userID, role)
+}
+
+func (s *CourseService) UpdateLesson(ctx context.Context, courseID, moduleID, lessonID int, userID uuid.UUID, role entities.Role, input courseDTOs.UpdateLessonRequest) (*entities.Lesson, error) {
+	lesson, err := s.getOwnedLesson(ctx, courseID, moduleID, lessonID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	lesson.Title = strings.TrimSpace(input.Title)
+	lesson.Content = input.Content
+	lesson.DurationMinutes = input.DurationMinutes
+	lesson.IsRequired = input.IsRequired == nil || *input.IsRequired
+	lesson.Status = entities.CourseStatus(input.Status)
+
+	if err := s.courseRepo.UpdateLesson(ctx, lesson); err != nil {
+		return nil, err
+	}
+
+	return lesson, nil
+}
+
+func (s *CourseService) DeleteLesson(ctx context.Context, courseID, moduleID, lessonID int, userID uuid.UUID, role entities.Role) error {
+	if _, err := s.getOwnedLesson(ctx, courseID, moduleID, lessonID, userID, role); err != nil {
+		return err
+	}
+
+	return s.courseRepo.DeleteLesson(ctx, lessonID)
+}
+
+// ReorderLessons puts the lessons of a module in the given order
+func (s *CourseService) ReorderLessons(ctx context.Context, courseID, moduleID int, userID uuid.UUID, role entities.Role, lessonIDs []int) (*entities.Course, error) {
+	if _, err := s.getOwnedModule(ctx, courseID, moduleID, userID, role); err != nil {
+		return nil, err
+	}
+
+	if err := s.courseRepo.ReorderLessons(ctx, moduleID, lessonIDs); err != nil {
+		return nil, err
+	}
+
+	return s.managedCourse(ctx, courseID)
+}
+
+// getPublishedCourse loads a course by slug. Draft courses are reported as missing.
+func (s *CourseService) getPublishedCourse(ctx context.Context, slug string) (*entities.Course, error) {
+	course, err := s.courseRepo.GetCourseBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if course.Status != entities.CourseStatusPublished {
+		return nil, appErrors.New(appErrors.CodeNotFound, "course not found")
+	}
+	return course, nil
+}
+
+// getOwnedCourse loads a course the user may manage: their own, or any course for admins
+func (s *CourseService) getOwnedCourse(ctx context.Context, id int, userID uuid.UUID, role entities.Role) (*entities.Course, error) {
+	course, err := s.courseRepo.GetCourseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if course.UserID != userID.String() && !role.IsAdmin() {
+		return nil, appErrors.New(appErrors.CodeForbidden, "You can only manage your own courses")
+	}
+
+	return course, nil
+}
+
+// getOwnedModule loads a module of a course the user may manage
+func (s *CourseService) getOwnedModule(ctx context.Context, courseID, moduleID int, userID uuid.UUID, role entities.Role) (*entities.Module, error) {
+	if _, err := s.getOwnedCourse(ctx, courseID, userID, role); err != nil {
+		return nil, err
+	}
+
+	module, err := s.courseRepo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	if module.CourseID != courseID {
+		return nil, appErrors.New(appErrors.CodeNotFound, "module not found")
+	}
+
+	return module, nil
+}
+
+// getOwnedLesson loads a lesson of a module of a course the user may manage
+func (s *CourseService) getOwnedLesson(ctx context.Context, courseID, moduleID, lessonID int, userID uuid.UUID, role entities.Role) (*entities.Lesson, error) {
+	if _, err := s.getOwnedModule(ctx, courseID, moduleID, userID, role); err != nil {
+		return nil, err
+	}
+
+	lesson, err := s.courseRepo.GetLessonByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.ModuleID != moduleID {
+		return nil, appErrors.New(appErrors.CodeNotFound, "lesson not found")
+	}
+
+	return lesson, nil
+}
+
+// managedCourse reloads a course after a change, with all of its modules and lessons
+func (s *CourseService) managedCourse(ctx context.Context, id int) (*entities.Course, error) {
+	course, err := s.courseRepo.GetCourseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachOutline(ctx, course, false); err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+// attachOutline sets a course's modules in order, each with its lessons in order and without their content
+func (s *CourseService) attachOutline(ctx context.Context, course *entities.Course, publishedOnly bool) error {
+	modules, err := s.courseRepo.GetModules(ctx, course.ID, publishedOnly)
+	if err != nil {
+		return err
+	}
+
+	lessons, err := s.courseRepo.GetLessons(ctx, course.ID, publishedOnly)
+	if err != nil {
+		return err
+	}
+
+	byModule := make(map[int]*entities.Module, len(modules))
+	for _, module := range modules {
+		module.Lessons = []*entities.Lesson{}
+		byModule[module.ID] = module
+	}
+	for _, lesson := range lessons {
+		if module, ok := byModule[lesson.ModuleID]; ok {
+			module.Lessons = append(module.Lessons, lesson)
+		}
+	}
+
+	course.Modules = modules
+	return nil
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/cache"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"context"
+	"github.com/google/uuid"
+)
+
+// EngagementService handles reactions and bookmarks on published posts
+type EngagementService struct {
+	reactionRepo repositories.ReactionRepository
+	bookmarkRepo repositories.BookmarkRepository
+	postRepo     repositories.PostRepository
+	counter      *cache.ReactionCounter
+	renderer     *ContentRenderer
+	trending     *TrendingTracker
+	logger       contracts.Logger
+}
+
+func NewEngagementService(
+	reactionRepo repositories.ReactionRepository,
+	bookmarkRepo repositories.BookmarkRepository,
+	postRepo repositories.PostRepository,
+	counter *cache.ReactionCounter,
+	renderer *ContentRenderer,
+	trending *TrendingTracker,
+	logger contracts.Logger,
+) *EngagementService {
+	return &EngagementService{
+		reactionRepo: reactionRepo,
+		bookmarkRepo: bookmarkRepo,
+		postRepo:     postRepo,
+		counter:      counter,
+		renderer:     renderer,
+		trending:     trending,
+		logger:       logger,
+	}
+}
+
+// AddReaction adds the user's reaction to a published post
+func (s *EngagementService) AddReaction(ctx context.Context, postSlug string, userID uuid.UUID, role entities.Role, reaction entities.ReactionType) (*postDTOs.ReactionSummaryDTO, error) {
+	return s.changeReaction(ctx, postSlug, userID, role, reaction, 1, s.reactionRepo.AddReaction)
+}
+
+// RemoveReaction takes back the user's reaction to a published post
+func (s *EngagementService) RemoveReaction(ctx context.Context, postSlug string, userID uuid.UUID, role entities.Role, reaction entities.ReactionType) (*postDTOs.ReactionSummaryDTO, error) {
+	return s.changeReaction(ctx, postSlug, userID, role, reaction, -1, s.reactionRepo.RemoveReaction)
+}
+
+func (s *EngagementService) changeReaction(
+	ctx context.Context,
+	postSlug string,
+	userID uuid.UUID,
+	role entities.Role,
+	reaction entities.ReactionType,
+	delta int,
+	change func(context.Context, int, uuid.UUID, entities.ReactionType) (bool, error),
+) (*postDTOs.ReactionSummaryDTO, error) {
+	if !reaction.IsValid() {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "unknown reaction")
+	}
+
+	post, err := s.postRepo.GetPublishedPostBySlug(ctx, postSlug)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPostVisible(post, userID, role); err != nil {
+		return nil, err
+	}
+
+	changed, err := change(ctx, post.ID, userID, reaction)
+	if err != nil {
+		return nil, err
+	}
+	// Repeating a request is a no-op, so counters only move when a row changed
+	if changed {
+		if err := s.counter.Increment(ctx, post.ID, string(reaction), delta); err != nil {
+			s.logger.Warn("Failed to update cached reaction count", "post_id", post.ID, "error", err)
+		}
+		// Removed reactions leave the leaderboards at the next rebuild
+		if delta > 0 {
+			s.trending.RecordReaction(ctx, post)
+		}
+	}
+
+	counts, err := s.reactionCounts(ctx, []int{post.ID})
+	if err != nil {
+		return nil, err
+	}
+	viewerReactions, err := s.reactionRepo.GetUserReactions(ctx, post.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &postDTOs.ReactionSummaryDTO{
+		PostID:          post.ID,
+		Reactions:       withAllReactions(counts[post.ID]),
+		ViewerReactions: viewerReactions,
+	}, nil
+}
+
+// AttachReactionCounts sets the reaction counts of each post
+func (s *EngagementService) AttachReactionCounts(ctx context.Context, posts []*postDTOs.PostDTO) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	postIDs := make([]int, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+
+	counts, err := s.reactionCounts(ctx, postIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, post := range posts {
+		post.Reactions = withAllReactions(counts[post.ID])
+	}
+	return nil
+}
+
+// AttachViewer sets the signed-in user's own reactions and bookmark on a post
+func (s *EngagementService) AttachViewer(ctx context.Context, post *postDTOs.PostDTO, userID uuid.UUID) error {
+	reactions, err := s.reactionRepo.GetUserReactions(ctx, post.ID, userID)
+	if err != nil {
+		return err
+	}
+	bookmarked, err := s.bookmarkRepo.IsBookmarked(ctx, userID, post.ID)
+	if err != nil {
+		return err
+	}
+
+	post.Viewer = &postDTOs.PostViewerDTO{Reactions: reactions, Bookmarked: bookmarked}
+	return nil
+}
+
+// reactionCounts reads counts from the cache, loading posts that are not cached from the
+// database. Cache failures are logged and fall back to the database.
+func (s *EngagementService) reactionCounts(ctx context.Context, postIDs []int) (map[int]map[string]int, error) {
+	counts, missing, err := s.counter.Get(ctx, postIDs)
+	if err != nil {
+		s.logger.Warn("Failed to read cached reaction counts", "error", err)
+		return s.reactionRepo.GetReactionCounts(ctx, postIDs)
+	}
+	if len(missing) == 0 {
+		return counts, nil
+	}
+
+	loaded, err := s.reactionRepo.GetReactionCounts(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.counter.Set(ctx, missing, loaded); err != nil {
+		s.logger.Warn("Failed to cache reaction counts", "error", err)
+	}
+
+	for _, postID := range missing {
+		counts[postID] = loaded[postID]
+	}
+	return counts, nil
+}
+
+// withAllReactions lists every reaction type, so clients always get the same keys
+func withAllReactions(counts map[string]int) map[string]int {
+	result := make(map[string]int, len(entities.ReactionTypes))
+	for _, reaction := range entities.ReactionTypes {
+		result[string(reaction)] = counts[string(reaction)]
+	}
+	return result
+}
+
+// AddBookmark saves a published post to the user's reading list
+func (s *EngagementService) AddBookmark(ctx context.Context, postSlug string, userID uuid.UUID, role entities.Role) error {
+	post, err := s.postRepo.GetPublishedPostBySlug(ctx, postSlug)
+	if err != nil {
+		return err
+	}
+	if err := checkPostVisible(post, userID, role); err != nil {
+		return err
+	}
+
+	return s.bookmarkRepo.AddBookmark(ctx, userID, post.ID)
+}
+
+// RemoveBookmark takes a post off the user's reading list
+func (s *EngagementService) RemoveBookmark(ctx context.Context, postSlug string, userID uuid.UUID) error {
+	post, err := s.postRepo.GetPublishedPostBySlug(ctx, postSlug)
+	if err != nil {
+		return err
+	}
+
+	return s.bookmarkRepo.RemoveBookmark(ctx, userID, post.ID)
+}
+
+// GetBookmarks returns a page of the user's reading list
+func (s *EngagementService) GetBookmarks(ctx context.Context, userID uuid.UUID, role entities.Role, page, perPage int) ([]*postDTOs.BookmarkDTO, int, error) {
+	bookmarks, total, err := s.bookmarkRepo.GetUserBookmarks(ctx, userID, perPage, utils.Offset(page, perPage))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	posts := make([]*postDTOs.PostDTO, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		posts[i] = bookmark.Post
+	}
+	if err := s.renderer.RenderPosts(ctx, posts); err != nil {
+		return nil, 0, err
+	}
+	if err := s.AttachReactionCounts(ctx, posts); err != nil {
+		return nil, 0, err
+	}
+	restrictPosts(posts, userID, role)
+
+	return bookmarks, total, nil
+}
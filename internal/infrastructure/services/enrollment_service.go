@@ -0,0 +1,171 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/courseDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+// EnrollmentService manages who may read the lessons of a course. Students enroll
+// themselves in free courses; instructors and admins enroll anyone in their courses.
+type EnrollmentService struct {
+	enrollmentRepo repositories.EnrollmentRepository
+	courseRepo     repositories.CourseRepository
+	userRepo       repositories.UserRepository
+	logger         contracts.Logger
+}
+
+func NewEnrollmentService(
+	enrollmentRepo repositories.EnrollmentRepository,
+	courseRepo repositories.CourseRepository,
+	userRepo repositories.UserRepository,
+	logger contracts.Logger,
+) *EnrollmentService {
+	return &EnrollmentService{
+		enrollmentRepo: enrollmentRepo,
+		courseRepo:     courseRepo,
+		userRepo:       userRepo,
+		logger:         logger,
+	}
+}
+
+// Enroll enrolls the user in a free, published course while its enrollment is open
+func (s *EnrollmentService) Enroll(ctx context.Context, slug string, userID uuid.UUID) (*entities.Enrollment, error) {
+	course, err := s.courseRepo.GetCourseBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if course.Status != entities.CourseStatusPublished {
+		return nil, appErrors.New(appErrors.CodeNotFound, "course not found")
+	}
+	if !course.IsFree {
+		return nil, appErrors.New(appErrors.CodeForbidden, "Students are enrolled in this course by its instructor")
+	}
+	if course.EnrollmentClosed(time.Now()) {
+		return nil, appErrors.New(appErrors.CodeForbidden, "Enrollment in this course has closed")
+	}
+
+	enrollment := &entities.Enrollment{
+		CourseID: course.ID,
+		UserID:   userID.String(),
+	}
+	if err := s.enrollmentRepo.CreateEnrollment(ctx, enrollment); err != nil {
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+// Unenroll takes the user out of a course
+func (s *EnrollmentService) Unenroll(ctx context.Context, slug string, userID uuid.UUID) error {
+	course, err := s.courseRepo.GetCourseBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	return s.enrollmentRepo.DeleteEnrollment(ctx, course.ID, userID)
+}
+
+// GetMyCourses returns a page of the published courses the user is enrolled in
+func (s *EnrollmentService) GetMyCourses(ctx context.Context, userID uuid.UUID, page, perPage int) ([]*entities.Enrollment, int, error) {
+	return s.enrollmentRepo.GetUserEnrollments(ctx, userID, perPage, utils.Offset(page, perPage))
+}
+
+// GetCourseEnrollments returns a page of the students of a course for its owner or an admin
+func (s *EnrollmentService) GetCourseEnrollments(ctx context.Context, courseID int, userID uuid.UUID, role entities.Role, page, perPage int) ([]*entities.Enrollment, int, error) {
+	if _, err := s.getOwnedCourse(ctx, courseID, userID, role); err != nil {
+		return nil, 0, err
+	}
+
+	return s.enrollmentRepo.GetCourseEnrollments(ctx, courseID, perPage, utils.Offset(page, perPage))
+}
+
+// EnrollUser enrolls an active user in a course on behalf of its owner. Unlike
+// self-enrollment this works for paid, draft and closed courses, but not past the
+// enrollment limit.
+func (s *EnrollmentService) EnrollUser(ctx context.Context, courseID int, userID uuid.UUID, role entities.Role, input courseDTOs.EnrollUserRequest) (*entities.Enrollment, error) {
+	if _, err := s.getOwnedCourse(ctx, courseID, userID, role); err != nil {
+		return nil, err
+	}
+
+	studentID, err := uuid.Parse(input.UserID)
+	if err != nil {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "invalid user_id")
+	}
+	student, err := s.userRepo.GetUserByID(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !student.Active {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "only active users can be enrolled")
+	}
+
+	enrolledBy := userID.String()
+	enrollment := &entities.Enrollment{
+		CourseID:   courseID,
+		UserID:     studentID.String(),
+		FirstName:  student.FirstName,
+		LastName:   student.LastName,
+		Email:      student.Email,
+		EnrolledBy: &enrolledBy,
+	}
+	if err := s.enrollmentRepo.CreateEnrollment(ctx, enrollment); err != nil {
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+// RemoveEnrollment takes a student out of a course on behalf of its owner
+func (s *EnrollmentService) RemoveEnrollment(ctx context.Context, courseID int, studentID, userID uuid.UUID, role entities.Role) error {
+	if _, err := s.getOwnedCourse(ctx, courseID, userID, role); err != nil {
+		return err
+	}
+
+	return s.enrollmentRepo.DeleteEnrollment(ctx, courseID, studentID)
+}
+
+// CheckLessonAccess reports whether the user may read the lessons of a course: its
+// owner and admins always may, students once enrolled and the course has started
+func (s *EnrollmentService) CheckLessonAccess(ctx context.Context, slug string, userID uuid.UUID, role entities.Role) error {
+	course, err := s.courseRepo.GetCourseBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if course.UserID == userID.String() || role.IsAdmin() {
+		return nil
+	}
+
+	enrolled, err := s.enrollmentRepo.IsEnrolled(ctx, course.ID, userID)
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return appErrors.New(appErrors.CodeForbidden, "Enroll in this course to access its lessons")
+	}
+	if !course.Started(time.Now()) {
+		return appErrors.New(appErrors.CodeForbidden, "This course has not started yet")
+	}
+
+	return nil
+}
+
+// getOwnedCourse loads a course the user may manage: their own, or any course for admins
+func (s *EnrollmentService) getOwnedCourse(ctx context.Context, id int, userID uuid.UUID, role entities.Role) (*entities.Course, error) {
+	course, err := s.courseRepo.GetCourseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if course.UserID != userID.String() && !role.IsAdmin() {
+		return nil, appErrors.New(appErrors.CodeForbidden, "You can only manage enrollments in your own courses")
+	}
+
+	return course, nil
+}
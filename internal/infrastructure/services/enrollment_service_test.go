@@ -0,0 +1,311 @@
+package services
+
+import (
+	"app05/internal/core/domain/dtos/courseDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/pkg/appErrors"
+	"context"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+// fakeCourseRepo serves courses, modules and lessons from memory. Methods the tests
+// don't need are left to the embedded interface and panic when called.
+type fakeCourseRepo struct {
+	repositories.CourseRepository
+	courses []*entities.Course
+	modules []*entities.Module
+	lessons []*entities.Lesson
+}
+
+func (r *fakeCourseRepo) GetCourseByID(ctx context.Context, id int) (*entities.Course, error) {
+	for _, course := range r.courses {
+		if course.ID == id {
+			copied := *course
+			return &copied, nil
+		}
+	}
+	return nil, appErrors.New(appErrors.CodeNotFound, "course not found")
+}
+
+func (r *fakeCourseRepo) GetCourseBySlug(ctx context.Context, slug string) (*entities.Course, error) {
+	for _, course := range r.courses {
+		if course.Slug == slug {
+			copied := *course
+			return &copied, nil
+		}
+	}
+	return nil, appErrors.New(appErrors.CodeNotFound, "course not found")
+}
+
+func (r *fakeCourseRepo) GetModuleByID(ctx context.Context, id int) (*entities.Module, error) {
+	for _, module := range r.modules {
+		if module.ID == id {
+			copied := *module
+			return &copied, nil
+		}
+	}
+	return nil, appErrors.New(appErrors.CodeNotFound, "module not found")
+}
+
+func (r *fakeCourseRepo) GetModules(ctx context.Context, courseID int, publishedOnly bool) ([]*entities.Module, error) {
+	var modules []*entities.Module
+	for _, module := range r.modules {
+		if module.CourseID == courseID && (!publishedOnly || module.Status == entities.CourseStatusPublished) {
+			copied := *module
+			modules = append(modules, &copied)
+		}
+	}
+	return modules, nil
+}
+
+func (r *fakeCourseRepo) GetLessonByID(ctx context.Context, id int) (*entities.Lesson, error) {
+	for _, lesson := range r.lessons {
+		if lesson.ID == id {
+			copied := *lesson
+			return &copied, nil
+		}
+	}
+	return nil, appErrors.New(appErrors.CodeNotFound, "lesson not found")
+}
+
+func (r *fakeCourseRepo) GetLessons(ctx context.Context, courseID int, publishedOnly bool) ([]*entities.Lesson, error) {
+	var lessons []*entities.Lesson
+	for _, lesson := range r.lessons {
+		if lesson.CourseID != courseID {
+			continue
+		}
+		if publishedOnly {
+			module, err := r.GetModuleByID(ctx, lesson.ModuleID)
+			if err != nil {
+				return nil, err
+			}
+			if lesson.Status != entities.CourseStatusPublished || module.Status != entities.CourseStatusPublished {
+				continue
+			}
+		}
+		copied := *lesson
+		lessons = append(lessons, &copied)
+	}
+	return lessons, nil
+}
+
+// fakeEnrollmentRepo keeps enrollments in memory, keyed by course and user
+type fakeEnrollmentRepo struct {
+	repositories.EnrollmentRepository
+	enrollments map[int]map[string]*entities.Enrollment
+}
+
+func newFakeEnrollmentRepo() *fakeEnrollmentRepo {
+	return &fakeEnrollmentRepo{enrollments: map[int]map[string]*entities.Enrollment{}}
+}
+
+func (r *fakeEnrollmentRepo) CreateEnrollment(ctx context.Context, enrollment *entities.Enrollment) error {
+	if r.enrollments[enrollment.CourseID] == nil {
+		r.enrollments[enrollment.CourseID] = map[string]*entities.Enrollment{}
+	}
+	if _, ok := r.enrollments[enrollment.CourseID][enrollment.UserID]; !ok {
+		r.enrollments[enrollment.CourseID][enrollment.UserID] = enrollment
+	}
+	return nil
+}
+
+func (r *fakeEnrollmentRepo) DeleteEnrollment(ctx context.Context, courseID int, userID uuid.UUID) error {
+	delete(r.enrollments[courseID], userID.String())
+	return nil
+}
+
+func (r *fakeEnrollmentRepo) GetEnrollment(ctx context.Context, courseID int, userID uuid.UUID) (*entities.Enrollment, error) {
+	enrollment, ok := r.enrollments[courseID][userID.String()]
+	if !ok {
+		return nil, appErrors.New(appErrors.CodeNotFound, "enrollment not found")
+	}
+	return enrollment, nil
+}
+
+func (r *fakeEnrollmentRepo) IsEnrolled(ctx context.Context, courseID int, userID uuid.UUID) (bool, error) {
+	_, ok := r.enrollments[courseID][userID.String()]
+	return ok, nil
+}
+
+type fakeUserRepo struct {
+	repositories.UserRepository
+	users map[uuid.UUID]*entities.User
+}
+
+func (r *fakeUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, appErrors.New(appErrors.CodeNotFound, "user not found")
+	}
+	return user, nil
+}
+
+func newEnrollmentTest(t *testing.T) (*EnrollmentService, *fakeEnrollmentRepo, uuid.UUID) {
+	t.Helper()
+
+	owner := uuid.New()
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	courses := &fakeCourseRepo{courses: []*entities.Course{
+		{ID: 1, UserID: owner.String(), Slug: "free", Status: entities.CourseStatusPublished, IsFree: true},
+		{ID: 2, UserID: owner.String(), Slug: "paid", Status: entities.CourseStatusPublished},
+		{ID: 3, UserID: owner.String(), Slug: "draft", Status: entities.CourseStatusDraft, IsFree: true},
+		{ID: 4, UserID: owner.String(), Slug: "ended", Status: entities.CourseStatusPublished, IsFree: true, EndsAt: &past},
+		{ID: 5, UserID: owner.String(), Slug: "upcoming", Status: entities.CourseStatusPublished, IsFree: true, StartsAt: &future},
+	}}
+	enrollments := newFakeEnrollmentRepo()
+	return NewEnrollmentService(enrollments, courses, nil, nopLogger{}), enrollments, owner
+}
+
+func TestEnrollmentService_Enroll(t *testing.T) {
+	tests := []struct {
+		slug    string
+		wantErr appErrors.ErrorCode
+	}{
+		{slug: "free"},
+		{slug: "upcoming"}, // students enroll before a course starts
+		{slug: "paid", wantErr: appErrors.CodeForbidden},
+		{slug: "draft", wantErr: appErrors.CodeNotFound},
+		{slug: "ended", wantErr: appErrors.CodeForbidden},
+		{slug: "missing", wantErr: appErrors.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			service, enrollments, _ := newEnrollmentTest(t)
+			student := uuid.New()
+
+			enrollment, err := service.Enroll(context.Background(), tt.slug, student)
+			if tt.wantErr.Code != "" {
+				assertAppError(t, err, tt.wantErr)
+				for courseID := range enrollments.enrollments {
+					if len(enrollments.enrollments[courseID]) > 0 {
+						t.Fatalf("refused enrollment was stored")
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if enrollment.UserID != student.String() || enrollment.EnrolledBy != nil {
+				t.Fatalf("unexpected enrollment %+v", enrollment)
+			}
+			if enrolled, _ := enrollments.IsEnrolled(context.Background(), enrollment.CourseID, student); !enrolled {
+				t.Fatal("enrollment was not stored")
+			}
+		})
+	}
+}
+
+func TestEnrollmentService_CheckLessonAccess(t *testing.T) {
+	service, enrollments, owner := newEnrollmentTest(t)
+	ctx := context.Background()
+
+	student := uuid.New()
+	enrollments.CreateEnrollment(ctx, &entities.Enrollment{CourseID: 2, UserID: student.String()})
+	enrollments.CreateEnrollment(ctx, &entities.Enrollment{CourseID: 5, UserID: student.String()})
+
+	tests := []struct {
+		name    string
+		slug    string
+		userID  uuid.UUID
+		role    entities.Role
+		wantErr appErrors.ErrorCode
+	}{
+		{name: "enrolled student", slug: "paid", userID: student, role: entities.RoleStudent},
+		{name: "student before the course starts", slug: "upcoming", userID: student, role: entities.RoleStudent, wantErr: appErrors.CodeForbidden},
+		{name: "student not enrolled", slug: "free", userID: student, role: entities.RoleStudent, wantErr: appErrors.CodeForbidden},
+		{name: "another instructor", slug: "paid", userID: uuid.New(), role: entities.RoleInstructor, wantErr: appErrors.CodeForbidden},
+		{name: "owner", slug: "upcoming", userID: owner, role: entities.RoleInstructor},
+		{name: "admin", slug: "upcoming", userID: uuid.New(), role: entities.RoleAdmin},
+		{name: "superuser", slug: "paid", userID: uuid.New(), role: entities.RoleSuperUser},
+		{name: "unknown course", slug: "missing", userID: owner, role: entities.RoleInstructor, wantErr: appErrors.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.CheckLessonAccess(ctx, tt.slug, tt.userID, tt.role)
+			if tt.wantErr.Code != "" {
+				assertAppError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEnrollmentService_EnrollUser(t *testing.T) {
+	active, inactive := uuid.New(), uuid.New()
+	users := &fakeUserRepo{users: map[uuid.UUID]*entities.User{
+		active:   {FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Active: true},
+		inactive: {Email: "gone@example.com"},
+	}}
+
+	tests := []struct {
+		name     string
+		courseID int
+		student  string
+		owner    bool
+		role     entities.Role
+		wantErr  appErrors.ErrorCode
+	}{
+		{name: "owner enrolls in a paid course", courseID: 2, student: active.String(), owner: true, role: entities.RoleInstructor},
+		{name: "owner enrolls in a draft course", courseID: 3, student: active.String(), owner: true, role: entities.RoleInstructor},
+		{name: "owner enrolls after enrollment closed", courseID: 4, student: active.String(), owner: true, role: entities.RoleInstructor},
+		{name: "admin enrolls in any course", courseID: 2, student: active.String(), role: entities.RoleAdmin},
+		{name: "another instructor", courseID: 2, student: active.String(), role: entities.RoleInstructor, wantErr: appErrors.CodeForbidden},
+		{name: "student", courseID: 1, student: active.String(), role: entities.RoleStudent, wantErr: appErrors.CodeForbidden},
+		{name: "inactive user", courseID: 2, student: inactive.String(), owner: true, role: entities.RoleInstructor, wantErr: appErrors.CodeBadRequest},
+		{name: "unknown user", courseID: 2, student: uuid.NewString(), owner: true, role: entities.RoleInstructor, wantErr: appErrors.CodeNotFound},
+		{name: "invalid user id", courseID: 2, student: "nobody", owner: true, role: entities.RoleInstructor, wantErr: appErrors.CodeBadRequest},
+		{name: "unknown course", courseID: 99, student: active.String(), role: entities.RoleAdmin, wantErr: appErrors.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, enrollments, owner := newEnrollmentTest(t)
+			service.userRepo = users
+			userID := uuid.New()
+			if tt.owner {
+				userID = owner
+			}
+
+			enrollment, err := service.EnrollUser(context.Background(), tt.courseID, userID, tt.role, courseDTOs.EnrollUserRequest{UserID: tt.student})
+			if tt.wantErr.Code != "" {
+				assertAppError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if enrollment.EnrolledBy == nil || *enrollment.EnrolledBy != userID.String() || enrollment.Email != "ada@example.com" {
+				t.Fatalf("unexpected enrollment %+v", enrollment)
+			}
+			if enrolled, _ := enrollments.IsEnrolled(context.Background(), tt.courseID, active); !enrolled {
+				t.Fatal("enrollment was not stored")
+			}
+		})
+	}
+}
+
+func TestEnrollmentService_RemoveEnrollment(t *testing.T) {
+	service, enrollments, owner := newEnrollmentTest(t)
+	ctx := context.Background()
+	student := uuid.New()
+	enrollments.CreateEnrollment(ctx, &entities.Enrollment{CourseID: 2, UserID: student.String()})
+
+	err := service.RemoveEnrollment(ctx, 2, student, uuid.New(), entities.RoleInstructor)
+	assertAppError(t, err, appErrors.CodeForbidden)
+	if enrolled, _ := enrollments.IsEnrolled(ctx, 2, student); !enrolled {
+		t.Fatal("another instructor removed the enrollment")
+	}
+
+	if err := service.RemoveEnrollment(ctx, 2, student, owner, entities.RoleInstructor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if enrolled, _ := enrollments.IsEnrolled(ctx, 2, student); enrolled {
+		t.Fatal("the owner could not remove the enrollment")
+	}
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/feeds"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"net/url"
+	"strings"
+)
+
+// SiteInfo describes the public site that content links point to
+type SiteInfo struct {
+	Name        string
+	Description string
+	Language    string
+	URL         string // public URL of the frontend, where posts are read
+	APIURL      string // public URL of this server
+}
+
+// PostURL returns the public address of a post
+func (s SiteInfo) PostURL(slug string) string {
+	return strings.TrimRight(s.URL, "/") + "/posts/" + url.PathEscape(slug)
+}
+
+type FeedService struct {
+	postRepo     repositories.PostRepository
+	taxonomyRepo repositories.TaxonomyRepository
+	userRepo     repositories.UserRepository
+	renderer     *ContentRenderer
+	cache        *cache.FeedCache
+	site         SiteInfo
+	itemLimit    int
+	logger       contracts.Logger
+}
+
+func NewFeedService(
+	postRepo repositories.PostRepository,
+	taxonomyRepo repositories.TaxonomyRepository,
+	userRepo repositories.UserRepository,
+	renderer *ContentRenderer,
+	cache *cache.FeedCache,
+	site SiteInfo,
+	itemLimit int,
+	logger contracts.Logger,
+) *FeedService {
+	return &FeedService{
+		postRepo:     postRepo,
+		taxonomyRepo: taxonomyRepo,
+		userRepo:     userRepo,
+		renderer:     renderer,
+		cache:        cache,
+		site:         site,
+		itemLimit:    itemLimit,
+		logger:       logger,
+	}
+}
+
+// GetPostsFeed returns the latest published posts matching the filter encoded in the
+// requested format. Encoded feeds are cached, cache failures fall back to building the feed.
+func (s *FeedService) GetPostsFeed(ctx context.Context, format feeds.Format, filter postDTOs.PublishedPostFilter) (*feeds.Document, error) {
+	query := feedQuery(filter)
+	key := string(format) + ":" + query.Encode()
+
+	cached, err := s.cache.Get(ctx, key)
+	if err != nil {
+		s.logger.Warn("Failed to read feed from cache", "error", err)
+	}
+	if cached != nil {
+		return cached, nil
+	}
+
+	feed, err := s.buildPostsFeed(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.FeedURL = strings.TrimRight(s.site.APIURL, "/") + "/feeds/posts." + string(format)
+	if len(query) > 0 {
+		feed.FeedURL += "?" + query.Encode()
+	}
+
+	document, err := feeds.Encode(feed, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Set(ctx, key, document); err != nil {
+		s.logger.Warn("Failed to cache feed", "error", err)
+	}
+
+	return document, nil
+}
+
+func (s *FeedService) buildPostsFeed(ctx context.Context, filter postDTOs.PublishedPostFilter) (*feeds.Feed, error) {
+	feed := &feeds.Feed{
+		Title:       s.site.Name,
+		Description: s.site.Description,
+		Link:        strings.TrimRight(s.site.URL, "/"),
+		Language:    s.site.Language,
+	}
+
+	// Name the feed after its filters; unknown filters are reported as not found
+	var scopes []string
+	if filter.TagSlug != "" {
+		tag, err := s.taxonomyRepo.GetTagBySlug(ctx, filter.TagSlug)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, "tagged "+tag.Name)
+	}
+	if filter.CategorySlug != "" {
+		category, err := s.taxonomyRepo.GetCategoryBySlug(ctx, filter.CategorySlug)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, "in "+category.Name)
+	}
+	if filter.AuthorID != nil {
+		author, err := s.userRepo.GetUserByID(ctx, *filter.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, "by "+strings.TrimSpace(author.FirstName+" "+author.LastName))
+	}
+	if len(scopes) > 0 {
+		feed.Title = fmt.Sprintf("%s: posts %s", s.site.Name, strings.Join(scopes, ", "))
+	}
+
+	filter.Limit = s.itemLimit
+	filter.Offset = 0
+	posts, _, err := s.postRepo.GetPublishedPosts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.renderer.RenderPosts(ctx, posts); err != nil {
+		return nil, err
+	}
+	// Feeds are read anonymously, restricted posts only carry their excerpt
+	restrictPosts(posts, uuid.Nil, "")
+
+	host := s.siteHost()
+	authors := map[string]string{}
+
+	for _, post := range posts {
+		if post.Slug == nil || post.PublishedAt == nil {
+			continue
+		}
+
+		item := &feeds.Item{
+			// Tag URIs (RFC 4151) stay stable when a post's slug changes
+			ID:          fmt.Sprintf("tag:%s,%s:post-%d", host, post.PublishedAt.Format("2006-01-02"), post.ID),
+			Title:       post.Title,
+			Link:        s.site.PostURL(*post.Slug),
+			ContentHTML: post.ContentHTML,
+			Author:      s.authorName(ctx, authors, post.UserID),
+			Published:   *post.PublishedAt,
+			Updated:     post.UpdatedAt,
+		}
+		if post.Excerpt != nil {
+			item.Summary = *post.Excerpt
+		}
+		for _, category := range post.Categories {
+			item.Categories = append(item.Categories, category.Name)
+		}
+		for _, tag := range post.Tags {
+			item.Categories = append(item.Categories, tag.Name)
+		}
+
+		if item.Updated.Before(item.Published) {
+			item.Updated = item.Published
+		}
+		if item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+
+		feed.Items = append(feed.Items, item)
+	}
+
+	return feed, nil
+}
+
+// authorName looks up a post author's display name, remembering names already resolved for the feed
+func (s *FeedService) authorName(ctx context.Context, names map[string]string, userID string) string {
+	if name, ok := names[userID]; ok {
+		return name
+	}
+
+	name := ""
+	if id, err := uuid.Parse(userID); err == nil {
+		if user, err := s.userRepo.GetUserByID(ctx, id); err == nil {
+			name = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		} else {
+			s.logger.Warn("Failed to load feed author", "user_id", userID, "error", err)
+		}
+	}
+
+	names[userID] = name
+	return name
+}
+
+func (s *FeedService) siteHost() string {
+	if u, err := url.Parse(s.site.URL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return "localhost"
+}
+
+// feedQuery returns the filter as query parameters, used for both the cache key and the feed's own URL
+func feedQuery(filter postDTOs.PublishedPostFilter) url.Values {
+	query := url.Values{}
+	if filter.TagSlug != "" {
+		query.Set("tag", filter.TagSlug)
+	}
+	if filter.CategorySlug != "" {
+		query.Set("category", filter.CategorySlug)
+	}
+	if filter.AuthorID != nil {
+		query.Set("author", filter.AuthorID.String())
+	}
+	return query
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/blob"
+	"app05/internal/infrastructure/imaging"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	thumbnailSize             = 150
+	maxVariantSourceDimension = 6000
+	variantAttempts           = 3
+	variantLease              = 10 * time.Minute // a claimed image is retried after this long without a result
+	variantTimeout            = 5 * time.Minute
+	variantQueueSize          = 100
+)
+
+// variantWidths are the widths of the resized copies made of each image, for srcset
+var variantWidths = []int{320, 640, 1024, 1600}
+
+// ImageVariantService generates thumbnails and resized copies of uploaded images in
+// the background. Queued images are recorded in the database, so images the workers
+// did not get to are picked up again by RequeuePending.
+type ImageVariantService struct {
+	variantRepo repositories.ImageVariantRepository
+	blobs       contracts.BlobStore
+	links       *blob.Links
+	queue       chan string
+	workers     int
+	logger      contracts.Logger
+}
+
+func NewImageVariantService(variantRepo repositories.ImageVariantRepository, blobs contracts.BlobStore, links *blob.Links, workers int, logger contracts.Logger) *ImageVariantService {
+	return &ImageVariantService{
+		variantRepo: variantRepo,
+		blobs:       blobs,
+		links:       links,
+		queue:       make(chan string, variantQueueSize),
+		workers:     max(1, workers),
+		logger:      logger,
+	}
+}
+
+// Start runs the workers until ctx is done
+func (s *ImageVariantService) Start(ctx context.Context) {
+	for i := 0; i < s.workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case key := <-s.queue:
+					s.process(ctx, key)
+				}
+			}
+		}()
+	}
+}
+
+// Enqueue queues an uploaded image for variant generation
+func (s *ImageVariantService) Enqueue(ctx context.Context, sourceKey string) error {
+	if err := s.variantRepo.CreateImageVariantSet(ctx, sourceKey); err != nil {
+		return err
+	}
+	s.schedule(sourceKey)
+	return nil
+}
+
+// RequeuePending hands the workers images that were never processed or whose
+// processing stopped without a result, returning how many it found
+func (s *ImageVariantService) RequeuePending(ctx context.Context) (int, error) {
+	keys, err := s.variantRepo.GetPendingImageVariantSets(ctx, variantLease, variantQueueSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		s.schedule(key)
+	}
+	return len(keys), nil
+}
+
+// Variants returns the variants of those images that have them, keyed by source key.
+// Variants of private images get presigned URLs, as their images do.
+func (s *ImageVariantService) Variants(ctx context.Context, sourceKeys []string) (map[string][]*entities.ImageVariant, error) {
+	variantsByKey, err := s.variantRepo.GetImageVariants(ctx, sourceKeys)
+	if err != nil {
+		return nil, err
+	}
+	for _, variants := range variantsByKey {
+		for _, variant := range variants {
+			if variant.URL, err = s.links.URL(ctx, variant.Key); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return variantsByKey, nil
+}
+
+// Delete removes an image's variants along with their files
+func (s *ImageVariantService) Delete(ctx context.Context, sourceKey string) error {
+	files, err := s.blobs.List(ctx, variantDir(sourceKey)+"/")
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := s.blobs.Delete(ctx, file.Key); err != nil {
+			return err
+		}
+	}
+	return s.variantRepo.DeleteImageVariantSet(ctx, sourceKey)
+}
+
+// schedule hands an image to the workers. When they are busy the image stays pending
+// for RequeuePending.
+func (s *ImageVariantService) schedule(sourceKey string) {
+	select {
+	case s.queue <- sourceKey:
+	default:
+	}
+}
+
+func (s *ImageVariantService) process(ctx context.Context, sourceKey string) {
+	ctx, cancel := context.WithTimeout(ctx, variantTimeout)
+	defer cancel()
+
+	attempt, claimed, err := s.variantRepo.ClaimImageVariantSet(ctx, sourceKey, variantLease)
+	if err != nil {
+		s.logger.Error("Failed to claim image for variants", "key", sourceKey, "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	variants, err := s.generate(ctx, sourceKey)
+	if err != nil {
+		// Images that cannot be decoded will not decode on a retry either
+		final := attempt >= variantAttempts ||
+			errors.Is(err, imaging.ErrUnsupportedFormat) ||
+			errors.Is(err, imaging.ErrTooLarge) ||
+			errors.Is(err, contracts.ErrBlobNotFound)
+		s.logger.Warn("Failed to generate image variants", "key", sourceKey, "attempt", attempt, "final", final, "error", err)
+		if err := s.variantRepo.FailImageVariantSet(ctx, sourceKey, err.Error(), final); err != nil {
+			s.logger.Error("Failed to record image variant failure", "key", sourceKey, "error", err)
+		}
+		return
+	}
+
+	if err := s.variantRepo.CompleteImageVariantSet(ctx, sourceKey, variants); err != nil {
+		s.logger.Error("Failed to store image variants", "key", sourceKey, "error", err)
+	}
+}
+
+// generate stores the variants of an image next to it, in a directory named after it
+func (s *ImageVariantService) generate(ctx context.Context, sourceKey string) ([]*entities.ImageVariant, error) {
+	body, _, err := s.blobs.Get(ctx, sourceKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	img, format, err := imaging.Decode(data, maxVariantSourceDimension)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := imaging.Variants(img, format, variantWidths, thumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := make([]*entities.ImageVariant, len(encoded))
+	for i, variant := range encoded {
+		key := variantDir(sourceKey) + "/" + variant.Name + variant.Ext
+		if err := s.blobs.Put(ctx, key, bytes.NewReader(variant.Data), int64(len(variant.Data)), variant.ContentType); err != nil {
+			return nil, err
+		}
+		variants[i] = &entities.ImageVariant{
+			Key:         key,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			Thumbnail:   variant.Thumbnail,
+			ContentType: variant.ContentType,
+			Size:        int64(len(variant.Data)),
+		}
+	}
+	return variants, nil
+}
+
+// variantDir is the directory holding an image's variants: its key without the extension
+func variantDir(sourceKey string) string {
+	return strings.TrimSuffix(sourceKey, path.Ext(sourceKey))
+}
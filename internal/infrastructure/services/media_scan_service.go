@@ -0,0 +1,258 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/mediaDTOs"
+	"app05/internal/core/domain/repositories"
+	"app05/pkg/appErrors"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// quarantinePrefix holds files until they are scanned clean. It is outside the public
+	// prefixes and never presigned, so quarantined files cannot be downloaded.
+	quarantinePrefix = "quarantine/"
+	scanLease        = 15 * time.Minute // a claimed file is scanned again after this long without a result, doubled per attempt
+	scanQueueSize    = 100
+	maxScanAttempts  = 5 // files still not scanned after this many attempts are marked failed
+)
+
+// MediaScanService scans uploaded media for malware in the background. Files wait in
+// quarantine until scanned clean, then move to their own key; infected files stay in
+// quarantine, as do files that cannot be scanned. Scan state is recorded in the database,
+// so files the workers did not get to are picked up again by RequeuePending.
+type MediaScanService struct {
+	mediaRepo repositories.MediaRepository
+	blobs     contracts.BlobStore
+	scanner   contracts.Scanner
+	variants  *ImageVariantService
+	queue     chan int
+	workers   int
+	logger    contracts.Logger
+}
+
+func NewMediaScanService(
+	mediaRepo repositories.MediaRepository,
+	blobs contracts.BlobStore,
+	scanner contracts.Scanner,
+	variants *ImageVariantService,
+	workers int,
+	logger contracts.Logger,
+) *MediaScanService {
+	return &MediaScanService{
+		mediaRepo: mediaRepo,
+		blobs:     blobs,
+		scanner:   scanner,
+		variants:  variants,
+		queue:     make(chan int, scanQueueSize),
+		workers:   max(1, workers),
+		logger:    logger,
+	}
+}
+
+// Start runs the workers until ctx is done
+func (s *MediaScanService) Start(ctx context.Context) {
+	for i := 0; i < s.workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case id := <-s.queue:
+					s.process(ctx, id)
+				}
+			}
+		}()
+	}
+}
+
+// Enqueue hands a pending file to the workers. When they are busy the file stays
+// pending for RequeuePending.
+func (s *MediaScanService) Enqueue(id int) {
+	select {
+	case s.queue <- id:
+	default:
+	}
+}
+
+// CheckSize returns an error for files too large for the scanner, which could never
+// leave quarantine
+func (s *MediaScanService) CheckSize(size int64) error {
+	if maxSize := s.scanner.MaxSize(); maxSize >= 0 && size > maxSize {
+		return appErrors.New(appErrors.CodeRequestEntityTooLarge,
+			fmt.Sprintf("files must not exceed %d bytes to be scanned for malware", maxSize))
+	}
+	return nil
+}
+
+// RequeuePending hands the workers files that were never scanned or whose scan stopped
+// without a result, returning how many it found
+func (s *MediaScanService) RequeuePending(ctx context.Context) (int, error) {
+	ids, err := s.mediaRepo.GetPendingMediaScans(ctx, scanLease, scanQueueSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		s.Enqueue(id)
+	}
+	return len(ids), nil
+}
+
+// QuarantineUnscanned moves pending files stored outside quarantine, such as files
+// uploaded before scanning existed, into quarantine until they are scanned. Their
+// variants are removed and generated again once the file is scanned clean. It returns
+// how many files it moved.
+func (s *MediaScanService) QuarantineUnscanned(ctx context.Context) (int, error) {
+	ids, err := s.mediaRepo.GetPendingMediaOutside(ctx, quarantinePrefix, scanLease, scanQueueSize)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, id := range ids {
+		// The claim keeps the workers off the file while it moves
+		media, claimed, err := s.mediaRepo.ClaimMediaScan(ctx, id, scanLease)
+		if err != nil {
+			return moved, err
+		}
+		if !claimed || strings.HasPrefix(media.StorageKey, quarantinePrefix) {
+			continue
+		}
+
+		// Files that fail to move are tried again once the claim expires
+		key := quarantineKey(media.StorageKey)
+		if err := s.moveBlob(ctx, media.StorageKey, key, media.Size, media.ContentType); err != nil {
+			s.logger.Error("Failed to move media into quarantine", "media", id, "key", media.StorageKey, "error", err)
+			continue
+		}
+		if err := s.mediaRepo.MoveMedia(ctx, id, media.StorageKey, key); err != nil {
+			s.logger.Error("Failed to record quarantined media", "media", id, "error", err)
+			s.removeBlob(ctx, key)
+			continue
+		}
+		s.removeBlob(ctx, media.StorageKey)
+		if err := s.variants.Delete(ctx, media.StorageKey); err != nil {
+			s.logger.Error("Failed to remove variants of quarantined media", "key", media.StorageKey, "error", err)
+		}
+		s.Enqueue(id)
+		moved++
+	}
+	return moved, nil
+}
+
+// process scans a file and moves it out of quarantine when clean, or into it when an
+// infected file was stored elsewhere. Failed scans leave the file pending to be retried,
+// until it runs out of attempts or turns out too large to scan.
+func (s *MediaScanService) process(ctx context.Context, id int) {
+	ctx, cancel := context.WithTimeout(ctx, scanLease)
+	defer cancel()
+
+	media, claimed, err := s.mediaRepo.ClaimMediaScan(ctx, id, scanLease)
+	if err != nil {
+		s.logger.Error("Failed to claim media for scanning", "media", id, "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+	if media.ScanAttempts > maxScanAttempts {
+		s.failScan(ctx, media, "too many attempts")
+		return
+	}
+	if err := s.CheckSize(media.Size); err != nil {
+		s.failScan(ctx, media, "file too large")
+		return
+	}
+
+	body, _, err := s.blobs.Get(ctx, media.StorageKey)
+	if err != nil {
+		s.logger.Error("Failed to read media for scanning", "media", id, "key", media.StorageKey, "attempt", media.ScanAttempts, "error", err)
+		return
+	}
+	result, err := s.scanner.Scan(ctx, body)
+	body.Close()
+	if errors.Is(err, contracts.ErrScanTooLarge) {
+		s.failScan(ctx, media, "file too large")
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to scan media", "media", id, "attempt", media.ScanAttempts, "error", err)
+		if media.ScanAttempts == maxScanAttempts {
+			s.failScan(ctx, media, "too many attempts")
+		}
+		return
+	}
+
+	status, signature, key := mediaDTOs.ScanClean, (*string)(nil), strings.TrimPrefix(media.StorageKey, quarantinePrefix)
+	if result.Infected {
+		s.logger.Warn("Malware found in uploaded media", "media", id, "user", media.UserID, "signature", result.Signature)
+		status, signature, key = mediaDTOs.ScanInfected, &result.Signature, quarantineKey(key)
+	}
+
+	if key != media.StorageKey {
+		if err := s.moveBlob(ctx, media.StorageKey, key, media.Size, media.ContentType); err != nil {
+			s.logger.Error("Failed to move scanned media", "media", id, "from", media.StorageKey, "to", key, "error", err)
+			return
+		}
+	}
+	if err := s.mediaRepo.CompleteMediaScan(ctx, id, status, signature, key); err != nil {
+		s.logger.Error("Failed to record media scan", "media", id, "error", err)
+		// The record still points at the old key, or is gone when the file was deleted meanwhile
+		if key != media.StorageKey {
+			s.removeBlob(ctx, key)
+		}
+		return
+	}
+	if key != media.StorageKey {
+		s.removeBlob(ctx, media.StorageKey)
+	}
+	// Files stored before scanning existed may already have public variants
+	if status == mediaDTOs.ScanInfected && key != media.StorageKey {
+		if err := s.variants.Delete(ctx, media.StorageKey); err != nil {
+			s.logger.Error("Failed to remove variants of infected media", "key", media.StorageKey, "error", err)
+		}
+	}
+
+	if status == mediaDTOs.ScanClean && variantTypes[media.ContentType] {
+		if err := s.variants.Enqueue(ctx, key); err != nil {
+			s.logger.Error("Failed to queue media variants", "key", key, "error", err)
+		}
+	}
+}
+
+// failScan gives up on scanning a file, which stays in quarantine
+func (s *MediaScanService) failScan(ctx context.Context, media *mediaDTOs.MediaDTO, reason string) {
+	s.logger.Warn("Giving up scanning media", "media", media.ID, "user", media.UserID, "reason", reason)
+	if err := s.mediaRepo.CompleteMediaScan(ctx, media.ID, mediaDTOs.ScanFailed, nil, media.StorageKey); err != nil {
+		s.logger.Error("Failed to record media scan", "media", media.ID, "error", err)
+	}
+}
+
+// moveBlob copies a file to a new key. The original is removed by the caller once
+// nothing refers to it anymore.
+func (s *MediaScanService) moveBlob(ctx context.Context, from, to string, size int64, contentType string) error {
+	body, _, err := s.blobs.Get(ctx, from)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return s.blobs.Put(ctx, to, body, size, contentType)
+}
+
+func (s *MediaScanService) removeBlob(ctx context.Context, key string) {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		s.logger.Warn("Failed to remove media file", "key", key, "error", err)
+	}
+}
+
+// quarantineKey is the key a file is held under until it is scanned clean
+func quarantineKey(key string) string {
+	if strings.HasPrefix(key, quarantinePrefix) {
+		return key
+	}
+	return quarantinePrefix + key
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/mediaDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/blob"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	mediaPrefix        = "media/"
+	privateMediaPrefix = "private/media/" // outside the public prefixes, served through presigned URLs only
+)
+
+// variantTypes are the media types that get resized variants
+var variantTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// mediaTypes maps the content types accepted for media, detected from the file's
+// content, to the extension they are stored with
+var mediaTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+	"video/mp4":       ".mp4",
+	"video/webm":      ".webm",
+	"audio/mpeg":      ".mp3",
+}
+
+// MediaService manages the files authors upload for use in posts
+type MediaService struct {
+	mediaRepo   repositories.MediaRepository
+	blobs       contracts.BlobStore
+	variants    *ImageVariantService
+	scans       *MediaScanService
+	links       *blob.Links
+	quotas      map[entities.Role]int64 // storage per user in bytes, negative for unlimited
+	maxFileSize int64
+	mediaURLs   *regexp.Regexp
+	logger      contracts.Logger
+}
+
+func NewMediaService(
+	mediaRepo repositories.MediaRepository,
+	blobs contracts.BlobStore,
+	variants *ImageVariantService,
+	scans *MediaScanService,
+	links *blob.Links,
+	quotas map[entities.Role]int64,
+	maxFileSize int64,
+	logger contracts.Logger,
+) *MediaService {
+	// Matches both relative and absolute links to public media files
+	base := strings.TrimPrefix(strings.TrimSuffix(links.PublicURL(), "/"), "/")
+	mediaURLs := regexp.MustCompile(regexp.QuoteMeta(base+"/"+mediaPrefix) + `[^\s"'()<>?#\[\]]+`)
+
+	return &MediaService{
+		mediaRepo:   mediaRepo,
+		blobs:       blobs,
+		variants:    variants,
+		scans:       scans,
+		links:       links,
+		quotas:      quotas,
+		maxFileSize: maxFileSize,
+		mediaURLs:   mediaURLs,
+		logger:      logger,
+	}
+}
+
+// MaxFileSize is the largest file that can be uploaded, in bytes
+func (s *MediaService) MaxFileSize() int64 {
+	return s.maxFileSize
+}
+
+// UploadMedia stores an uploaded file in the user's library if it is of an accepted type,
+// fits their quota and does not exceed the upload limit
+func (s *MediaService) UploadMedia(ctx context.Context, userID uuid.UUID, role entities.Role, file io.Reader, size int64, fileName string, input mediaDTOs.CreateMediaRequest) (*mediaDTOs.MediaDTO, error) {
+	if size > s.maxFileSize {
+		return nil, appErrors.New(appErrors.CodeBadRequest, fmt.Sprintf("file must not exceed %d bytes", s.maxFileSize))
+	}
+	return s.AddMedia(ctx, userID, role, file, size, fileName, input)
+}
+
+// AddMedia stores a file of any size in the user's library if it is of an accepted type
+// and fits their quota. The file is quarantined until it is scanned for malware. Private
+// files are only linked through presigned URLs, which expire.
+func (s *MediaService) AddMedia(ctx context.Context, userID uuid.UUID, role entities.Role, file io.Reader, size int64, fileName string, input mediaDTOs.CreateMediaRequest) (*mediaDTOs.MediaDTO, error) {
+	// The content type is taken from the content, never from the client
+	content := bufio.NewReaderSize(file, 512)
+	head, err := content.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head)
+	ext, ok := mediaTypes[contentType]
+	if !ok {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "unsupported file type "+contentType)
+	}
+
+	if err := s.scans.CheckSize(size); err != nil {
+		return nil, err
+	}
+	// Checked again when the record is stored, this avoids uploading files that cannot fit
+	if err := s.CheckQuota(ctx, userID, role, size); err != nil {
+		return nil, err
+	}
+	quota := s.quota(role)
+
+	prefix := mediaPrefix
+	if input.Private {
+		prefix = privateMediaPrefix
+	}
+	media := &mediaDTOs.MediaDTO{
+		UserID:      userID.String(),
+		StorageKey:  quarantineKey(prefix + userID.String() + "/" + uuid.NewString() + ext),
+		FileName:    mediaFileName(fileName, ext),
+		ContentType: contentType,
+		Size:        size,
+		AltText:     trimmedOrNil(input.AltText),
+		Tags:        normalizeMediaTags(input.Tags),
+	}
+
+	if err := s.blobs.Put(ctx, media.StorageKey, content, size, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.mediaRepo.CreateMedia(ctx, media, quota); err != nil {
+		s.removeBlob(ctx, media.StorageKey)
+		return nil, err
+	}
+	s.scans.Enqueue(media.ID)
+
+	if err := s.setURL(ctx, media); err != nil {
+		return nil, err
+	}
+	media.Variants = []*entities.ImageVariant{}
+	return media, nil
+}
+
+// CheckScannable returns an error for files too large to be scanned for malware
+func (s *MediaService) CheckScannable(size int64) error {
+	return s.scans.CheckSize(size)
+}
+
+// CheckQuota returns an error when a file of size bytes does not fit the user's quota
+func (s *MediaService) CheckQuota(ctx context.Context, userID uuid.UUID, role entities.Role, size int64) error {
+	quota := s.quota(role)
+	if quota < 0 {
+		return nil
+	}
+
+	used, err := s.mediaRepo.GetMediaUsage(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if used+size > quota {
+		return appErrors.New(appErrors.CodeForbidden, "storage quota exceeded")
+	}
+	return nil
+}
+
+// GetUserMedia returns a page of the user's files, optionally only those with a tag
+func (s *MediaService) GetUserMedia(ctx context.Context, userID uuid.UUID, tag string, page, perPage int) ([]*mediaDTOs.MediaDTO, int, error) {
+	mediaList, total, err := s.mediaRepo.GetMediaByUser(ctx, userID, normalizeMediaTag(tag), perPage, utils.Offset(page, perPage))
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, media := range mediaList {
+		if err := s.setURL(ctx, media); err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := s.attachVariants(ctx, mediaList...); err != nil {
+		return nil, 0, err
+	}
+	return mediaList, total, nil
+}
+
+// GetMedia returns a file with the posts that use it, for its owner or an admin
+func (s *MediaService) GetMedia(ctx context.Context, id int, userID uuid.UUID, role entities.Role) (*mediaDTOs.MediaDTO, error) {
+	media, err := s.getOwnedMedia(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	media.Posts, err = s.mediaRepo.GetMediaPosts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachVariants(ctx, media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// UpdateMedia sets a file's alt text and tags
+func (s *MediaService) UpdateMedia(ctx context.Context, id int, userID uuid.UUID, role entities.Role, input mediaDTOs.UpdateMediaRequest) (*mediaDTOs.MediaDTO, error) {
+	media, err := s.getOwnedMedia(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	media.AltText = trimmedOrNil(input.AltText)
+	media.Tags = normalizeMediaTags(input.Tags)
+	if err := s.mediaRepo.UpdateMedia(ctx, media); err != nil {
+		return nil, err
+	}
+
+	return s.GetMedia(ctx, id, userID, role)
+}
+
+// DeleteMedia removes a file that no post uses
+func (s *MediaService) DeleteMedia(ctx context.Context, id int, userID uuid.UUID, role entities.Role) error {
+	media, err := s.getOwnedMedia(ctx, id, userID, role)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mediaRepo.DeleteMedia(ctx, id); err != nil {
+		return err
+	}
+	if err := s.variants.Delete(ctx, media.StorageKey); err != nil {
+		s.logger.Warn("Failed to remove media variants", "key", media.StorageKey, "error", err)
+	}
+	s.removeBlob(ctx, media.StorageKey)
+	return nil
+}
+
+// GetUsage returns how much storage the user's files take and their quota
+func (s *MediaService) GetUsage(ctx context.Context, userID uuid.UUID, role entities.Role) (*mediaDTOs.MediaUsageDTO, error) {
+	used, err := s.mediaRepo.GetMediaUsage(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &mediaDTOs.MediaUsageDTO{UsedBytes: used}
+	if quota := s.quota(role); quota >= 0 {
+		usage.QuotaBytes = &quota
+	}
+	return usage, nil
+}
+
+// SyncPostMedia records which public media files a post's content links to, directly
+// or through one of their variants. Private files have no lasting links to find.
+func (s *MediaService) SyncPostMedia(ctx context.Context, postID int, content string) error {
+	keys := []string{}
+	for _, link := range s.mediaURLs.FindAllString(content, -1) {
+		key := link[strings.Index(link, mediaPrefix):]
+		keys = append(keys, key)
+		// media/{user}/{file}/{variant} is a variant, named after its file's key without extension
+		if strings.Count(key, "/") == 3 {
+			keys = append(keys, path.Dir(key))
+		}
+	}
+	return s.mediaRepo.SetPostMedia(ctx, postID, keys)
+}
+
+// attachVariants sets the generated variants of image media
+func (s *MediaService) attachVariants(ctx context.Context, mediaList ...*mediaDTOs.MediaDTO) error {
+	keys := make([]string, 0, len(mediaList))
+	for _, media := range mediaList {
+		if variantTypes[media.ContentType] {
+			keys = append(keys, media.StorageKey)
+		}
+	}
+
+	variantsByKey, err := s.variants.Variants(ctx, keys)
+	if err != nil {
+		return err
+	}
+	for _, media := range mediaList {
+		media.Variants = variantsByKey[media.StorageKey]
+		if media.Variants == nil {
+			media.Variants = []*entities.ImageVariant{}
+		}
+	}
+	return nil
+}
+
+func (s *MediaService) getOwnedMedia(ctx context.Context, id int, userID uuid.UUID, role entities.Role) (*mediaDTOs.MediaDTO, error) {
+	media, err := s.mediaRepo.GetMediaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if media.UserID != userID.String() && !role.IsAdmin() {
+		return nil, appErrors.New(appErrors.CodeForbidden, "You can only manage your own media")
+	}
+
+	if err := s.setURL(ctx, media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// setURL links a file on the public URL, or through a presigned URL when it is private.
+// Files not scanned clean are not linked at all.
+func (s *MediaService) setURL(ctx context.Context, media *mediaDTOs.MediaDTO) error {
+	media.Private = !s.links.IsPublic(strings.TrimPrefix(media.StorageKey, quarantinePrefix))
+	if media.ScanStatus != mediaDTOs.ScanClean {
+		media.URL = ""
+		return nil
+	}
+
+	url, err := s.links.URL(ctx, media.StorageKey)
+	if err != nil {
+		return err
+	}
+	media.URL = url
+	return nil
+}
+
+// quota returns the storage allowed for a role, none for roles without a quota
+func (s *MediaService) quota(role entities.Role) int64 {
+	quota, ok := s.quotas[role]
+	if !ok {
+		return 0
+	}
+	return quota
+}
+
+// removeBlob deletes a stored file. A leftover file is only logged.
+func (s *MediaService) removeBlob(ctx context.Context, key string) {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		s.logger.Warn("Failed to remove media file", "key", key, "error", err)
+	}
+}
+
+// mediaFileName keeps the base name of an uploaded file, with the extension of its detected type
+func mediaFileName(name, ext string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	if name == "" || name == "." || name == "/" {
+		name = "file"
+	}
+	if len(name) > 200 {
+		name = name[:200]
+	}
+	return strings.ToValidUTF8(name, "") + ext
+}
+
+func normalizeMediaTags(tags []string) []string {
+	normalized := []string{}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = normalizeMediaTag(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+func normalizeMediaTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/pkg/appErrors"
+	"github.com/google/uuid"
+	"slices"
+)
+
+// Post visibility is checked against the caller's user id and role. Anonymous
+// callers have uuid.Nil and an empty role.
+
+// canSeePost reports whether the caller may know the post exists. Drafts and archived
+// posts, like private posts, are only visible to their authors and admins.
+func canSeePost(post *postDTOs.PostDTO, userID uuid.UUID, role entities.Role) bool {
+	if entities.PostStatus(post.Status) != entities.PostStatusPublished && !role.IsAdmin() && !isPostAuthor(post, userID) {
+		return false
+	}
+	if entities.PostVisibility(post.Visibility) != entities.PostVisibilityPrivate {
+		return true
+	}
+	return role.IsAdmin() || isPostAuthor(post, userID)
+}
+
+// canReadPost reports whether the caller may read the post's full content
+func canReadPost(post *postDTOs.PostDTO, userID uuid.UUID, role entities.Role) bool {
+	if role.IsAdmin() || isPostAuthor(post, userID) {
+		return true
+	}
+
+	switch entities.PostVisibility(post.Visibility) {
+	case entities.PostVisibilityPublic:
+		return true
+	case entities.PostVisibilityRegistered:
+		return userID != uuid.Nil
+	case entities.PostVisibilityRoles:
+		return userID != uuid.Nil && slices.Contains(post.VisibleRoles, string(role))
+	default:
+		return false
+	}
+}
+
+func isPostAuthor(post *postDTOs.PostDTO, userID uuid.UUID) bool {
+	if userID == uuid.Nil {
+		return false
+	}
+	for _, author := range post.Authors {
+		if author.ID == userID.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPostVisible returns a not found error for posts the caller may not see,
+// so private posts cannot be told apart from missing ones
+func checkPostVisible(post *postDTOs.PostDTO, userID uuid.UUID, role entities.Role) error {
+	if !canSeePost(post, userID, role) {
+		return appErrors.New(appErrors.CodeNotFound, "post not found")
+	}
+	return nil
+}
+
+// restrictPosts withholds the content of rendered posts the caller may not read,
+// leaving the excerpt as a teaser
+func restrictPosts(posts []*postDTOs.PostDTO, userID uuid.UUID, role entities.Role) {
+	for _, post := range posts {
+		if canReadPost(post, userID, role) {
+			continue
+		}
+		post.Locked = true
+		post.Content = ""
+		post.ContentHTML = ""
+		post.TOC = nil
+	}
+}
+
+// visiblePosts drops the posts the caller may not see
+func visiblePosts(posts []*postDTOs.PostDTO, userID uuid.UUID, role entities.Role) []*postDTOs.PostDTO {
+	visible := make([]*postDTOs.PostDTO, 0, len(posts))
+	for _, post := range posts {
+		if canSeePost(post, userID, role) {
+			visible = append(visible, post)
+		}
+	}
+	return visible
+}
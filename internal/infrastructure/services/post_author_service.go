@@ -0,0 +1,156 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/pkg/appErrors"
+	"context"
+	"github.com/google/uuid"
+)
+
+// PostAuthorService manages the authors of posts and checks what each of them may do
+type PostAuthorService struct {
+	authorRepo repositories.PostAuthorRepository
+	postRepo   repositories.PostRepository
+	userRepo   repositories.UserRepository
+	logger     contracts.Logger
+}
+
+func NewPostAuthorService(
+	authorRepo repositories.PostAuthorRepository,
+	postRepo repositories.PostRepository,
+	userRepo repositories.UserRepository,
+	logger contracts.Logger,
+) *PostAuthorService {
+	return &PostAuthorService{
+		authorRepo: authorRepo,
+		postRepo:   postRepo,
+		userRepo:   userRepo,
+		logger:     logger,
+	}
+}
+
+// Authorize checks that the user may perform action on a post. Admins may act on any
+// post, other users need an author role for which allowed reports true.
+func (s *PostAuthorService) Authorize(
+	ctx context.Context,
+	postID int,
+	userID uuid.UUID,
+	role entities.Role,
+	allowed func(entities.PostAuthorRole) bool,
+	action string,
+) error {
+	if role.IsAdmin() {
+		return nil
+	}
+
+	authorRole, err := s.authorRepo.GetPostAuthorRole(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+	if !allowed(authorRole) {
+		return appErrors.New(appErrors.CodeForbidden, "You don't have permission to "+action+" this post")
+	}
+
+	return nil
+}
+
+// SetAuthor adds a co-author to a post or changes their role and returns the post's authors
+func (s *PostAuthorService) SetAuthor(
+	ctx context.Context,
+	postID int,
+	userID uuid.UUID,
+	role entities.Role,
+	authorID uuid.UUID,
+	input postDTOs.SetPostAuthorRequest,
+) ([]*postDTOs.PostAuthorDTO, error) {
+	if _, err := s.postRepo.GetPostByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	if err := s.Authorize(ctx, postID, userID, role, entities.PostAuthorRole.CanManageAuthors, "manage the authors of"); err != nil {
+		return nil, err
+	}
+
+	author, err := s.userRepo.GetUserByID(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if !author.Active || !author.Role.IsStaff() {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "co-authors must be active instructors or admins")
+	}
+
+	if err := s.authorRepo.SetPostAuthor(ctx, postID, authorID, entities.PostAuthorRole(input.Role)); err != nil {
+		return nil, err
+	}
+
+	return s.postAuthors(ctx, postID)
+}
+
+// RemoveAuthor removes a co-author from a post and returns the remaining authors
+func (s *PostAuthorService) RemoveAuthor(ctx context.Context, postID int, userID uuid.UUID, role entities.Role, authorID uuid.UUID) ([]*postDTOs.PostAuthorDTO, error) {
+	if _, err := s.postRepo.GetPostByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	if err := s.Authorize(ctx, postID, userID, role, entities.PostAuthorRole.CanManageAuthors, "manage the authors of"); err != nil {
+		return nil, err
+	}
+
+	authorRole, err := s.authorRepo.GetPostAuthorRole(ctx, postID, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if authorRole == entities.PostAuthorOwner {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "the owner cannot be removed from a post")
+	}
+
+	if err := s.authorRepo.RemovePostAuthor(ctx, postID, authorID); err != nil {
+		return nil, err
+	}
+
+	return s.postAuthors(ctx, postID)
+}
+
+// TransferPosts hands the ownership of all posts of a deactivated user to another active author
+func (s *PostAuthorService) TransferPosts(ctx context.Context, fromUserID uuid.UUID, input postDTOs.TransferPostsRequest) (*postDTOs.TransferPostsResultDTO, error) {
+	toUserID, err := uuid.Parse(input.ToUserID)
+	if err != nil {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "invalid to_user_id")
+	}
+	if toUserID == fromUserID {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "posts cannot be transferred to the same user")
+	}
+
+	from, err := s.userRepo.GetUserByID(ctx, fromUserID)
+	if err != nil {
+		return nil, err
+	}
+	if from.Active {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "posts can only be transferred from a deactivated user")
+	}
+
+	to, err := s.userRepo.GetUserByID(ctx, toUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !to.Active || !to.Role.IsStaff() {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "posts can only be transferred to an active instructor or admin")
+	}
+
+	transferred, err := s.authorRepo.TransferOwnedPosts(ctx, fromUserID, toUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Transferred post ownership", "from_user_id", fromUserID, "to_user_id", toUserID, "posts", transferred)
+	return &postDTOs.TransferPostsResultDTO{Transferred: transferred}, nil
+}
+
+func (s *PostAuthorService) postAuthors(ctx context.Context, postID int) ([]*postDTOs.PostAuthorDTO, error) {
+	post, err := s.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	return post.Authors, nil
+}
@@ -0,0 +1,22 @@
+package services
+
+import "app05/internal/core/domain/dtos/postDTOs"
+
+// localizedPosts keeps the posts written in locale, and the posts in the fallback
+// locale whose article has no translation in locale among posts
+func localizedPosts(posts []*postDTOs.PostDTO, locale, fallback string) []*postDTOs.PostDTO {
+	translated := make(map[string]bool)
+	for _, post := range posts {
+		if post.Locale == locale {
+			translated[post.TranslationGroupID] = true
+		}
+	}
+
+	localized := make([]*postDTOs.PostDTO, 0, len(posts))
+	for _, post := range posts {
+		if post.Locale == locale || (post.Locale == fallback && !translated[post.TranslationGroupID]) {
+			localized = append(localized, post)
+		}
+	}
+	return localized
+}
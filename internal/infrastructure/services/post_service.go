@@ -1,26 +1,389 @@
 package services
 
 import (
+	"app05/internal/core/application/contracts"
 	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/entities"
 	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/cache"
+	"app05/internal/infrastructure/i18n"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
 	"context"
+	"github.com/google/uuid"
+	"strings"
 )
 
 type PostService struct {
-	postRepo repositories.PostRepository
+	postRepo    repositories.PostRepository
+	renderer    *ContentRenderer
+	engagement  *EngagementService
+	series      *SeriesService
+	authors     *PostAuthorService
+	related     *RelatedPostService
+	locales     *i18n.Locales
+	trending    *TrendingTracker
+	media       *MediaService
+	viewCounter *cache.ViewCounter
+	logger      contracts.Logger
 }
 
-func NewPostService(postRepo repositories.PostRepository) *PostService {
+func NewPostService(
+	postRepo repositories.PostRepository,
+	renderer *ContentRenderer,
+	engagement *EngagementService,
+	series *SeriesService,
+	authors *PostAuthorService,
+	related *RelatedPostService,
+	locales *i18n.Locales,
+	trending *TrendingTracker,
+	media *MediaService,
+	viewCounter *cache.ViewCounter,
+	logger contracts.Logger,
+) *PostService {
 	return &PostService{
-		postRepo: postRepo,
+		postRepo:    postRepo,
+		renderer:    renderer,
+		engagement:  engagement,
+		series:      series,
+		authors:     authors,
+		related:     related,
+		locales:     locales,
+		trending:    trending,
+		media:       media,
+		viewCounter: viewCounter,
+		logger:      logger,
 	}
 }
 
-// GetAllPosts retrieves all posts
-func (s *PostService) GetAllPosts(ctx context.Context) ([]*postDTOs.PostDTO, error) {
-	posts, err := s.postRepo.GetAllPosts(ctx)
+// GetAllPosts retrieves all posts the caller may see, withholding content they may not read.
+// A non-empty locale keeps one post per article, in that locale or else the default locale.
+func (s *PostService) GetAllPosts(ctx context.Context, locale string, userID uuid.UUID, role entities.Role) ([]*postDTOs.PostDTO, error) {
+	// Drafts and archived posts are only listed for admins and their authors
+	posts, err := s.postRepo.GetAllPosts(ctx, role.IsAdmin(), userID)
 	if err != nil {
 		return nil, err
 	}
+	posts = visiblePosts(posts, userID, role)
+	if locale != "" {
+		posts = localizedPosts(posts, locale, s.locales.Default())
+	}
+	if err := s.renderer.RenderPosts(ctx, posts); err != nil {
+		return nil, err
+	}
+	if err := s.engagement.AttachReactionCounts(ctx, posts); err != nil {
+		return nil, err
+	}
+	restrictPosts(posts, userID, role)
 	return posts, nil
 }
+
+// GetPostBySlug retrieves a published post and records a view for the visitor.
+// The returned view count includes views that have not been flushed to the database yet.
+// Posts in a series carry their previous/next navigation.
+// For a signed-in viewer (userID other than uuid.Nil) their own reactions and bookmark are included.
+// Callers who may not read the post get its excerpt only.
+// When the post has a published translation in a non-empty locale, that translation is returned instead.
+func (s *PostService) GetPostBySlug(ctx context.Context, slug, locale, visitorID string, userID uuid.UUID, role entities.Role) (*postDTOs.PostDTO, error) {
+	post, err := s.postRepo.GetPublishedPostBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPostVisible(post, userID, role); err != nil {
+		return nil, err
+	}
+	if locale != "" && post.Locale != locale {
+		if post, err = s.translation(ctx, post, locale, userID, role); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.attachTranslations(ctx, post); err != nil {
+		return nil, err
+	}
+	if err := s.renderer.RenderPost(ctx, post); err != nil {
+		return nil, err
+	}
+	restrictPosts([]*postDTOs.PostDTO{post}, userID, role)
+	if err := s.engagement.AttachReactionCounts(ctx, []*postDTOs.PostDTO{post}); err != nil {
+		return nil, err
+	}
+	if err := s.series.AttachNavigation(ctx, post); err != nil {
+		return nil, err
+	}
+	if userID != uuid.Nil {
+		if err := s.engagement.AttachViewer(ctx, post, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// View tracking must never fail the read. Only counted views feed related post
+	// co-views and trending scores.
+	counted, err := s.viewCounter.RecordView(ctx, post.ID, visitorID)
+	if err != nil {
+		s.logger.Error("Failed to record post view", "postID", post.ID, "error", err)
+	} else if counted {
+		s.related.RecordView(ctx, visitorID, post.ID)
+		s.trending.RecordView(ctx, post)
+	}
+
+	pending, err := s.viewCounter.PendingViews(ctx, post.ID)
+	if err != nil {
+		s.logger.Error("Failed to read pending post views", "postID", post.ID, "error", err)
+	}
+	post.ViewCount += int(pending)
+
+	return post, nil
+}
+
+// SearchPosts runs a full-text search over published posts and returns the requested page with the total match count.
+// A non-empty locale keeps one post per article, in that locale or else the default locale.
+func (s *PostService) SearchPosts(ctx context.Context, query, locale string, userID uuid.UUID, role entities.Role, page, perPage int) ([]*postDTOs.PostSearchResultDTO, int, error) {
+	results, total, err := s.postRepo.SearchPosts(ctx, query, locale, s.locales.Default(), perPage, utils.Offset(page, perPage))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	posts := make([]*postDTOs.PostDTO, len(results))
+	for i, result := range results {
+		posts[i] = &result.PostDTO
+	}
+	if err := s.renderer.RenderPosts(ctx, posts); err != nil {
+		return nil, 0, err
+	}
+	if err := s.engagement.AttachReactionCounts(ctx, posts); err != nil {
+		return nil, 0, err
+	}
+
+	restrictPosts(posts, userID, role)
+	for _, result := range results {
+		// The snippet is cut from the content, which locked posts don't reveal
+		if result.Locked {
+			result.Snippet = ""
+		}
+	}
+
+	return results, total, nil
+}
+
+// CreatePost stores a new draft owned by the user. Translating a post requires edit rights on it.
+func (s *PostService) CreatePost(ctx context.Context, userID uuid.UUID, role entities.Role, input postDTOs.CreatePostRequest) (*postDTOs.PostDTO, error) {
+	locale := s.locales.Default()
+	if input.Locale != "" {
+		var err error
+		if locale, err = s.supportedLocale(input.Locale); err != nil {
+			return nil, err
+		}
+	}
+
+	groupID := ""
+	if input.TranslationOf != nil {
+		source, err := s.translatedPost(ctx, *input.TranslationOf, userID, role)
+		if err != nil {
+			return nil, err
+		}
+		groupID = source.TranslationGroupID
+	}
+
+	title := strings.TrimSpace(input.Title)
+	slug, err := uniqueSlug(ctx, title, s.postRepo.PostSlugExists)
+	if err != nil {
+		return nil, err
+	}
+
+	post := &postDTOs.PostDTO{
+		UserID:  userID.String(),
+		Title:   title,
+		Content: input.Content,
+		Excerpt: trimmedOrNil(input.Excerpt),
+		Status:  string(entities.PostStatusDraft),
+		Slug:    &slug,
+		Locale:  locale,
+		// Empty starts a new translation group
+		TranslationGroupID: groupID,
+	}
+	if err := s.postRepo.CreatePost(ctx, post); err != nil {
+		return nil, err
+	}
+	s.syncMedia(ctx, post)
+
+	return s.getEditedPost(ctx, post.ID)
+}
+
+// UpdatePost changes a post's title, content and excerpt. The slug follows the title
+// until the post is first published, so published links keep working.
+func (s *PostService) UpdatePost(ctx context.Context, id int, userID uuid.UUID, role entities.Role, input postDTOs.UpdatePostRequest) (*postDTOs.PostDTO, error) {
+	post, err := s.postRepo.GetPostByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authors.Authorize(ctx, id, userID, role, entities.PostAuthorRole.CanEdit, "edit"); err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title != post.Title && post.PublishedAt == nil {
+		slug, err := uniqueSlug(ctx, title, s.postRepo.PostSlugExists)
+		if err != nil {
+			return nil, err
+		}
+		post.Slug = &slug
+	}
+
+	post.Title = title
+	post.Content = input.Content
+	post.Excerpt = trimmedOrNil(input.Excerpt)
+
+	if err := s.postRepo.UpdatePost(ctx, post); err != nil {
+		return nil, err
+	}
+	s.related.Invalidate(ctx, id)
+	s.syncMedia(ctx, post)
+
+	return s.getEditedPost(ctx, id)
+}
+
+// UpdatePostStatus publishes, unpublishes or archives a post
+func (s *PostService) UpdatePostStatus(ctx context.Context, id int, userID uuid.UUID, role entities.Role, input postDTOs.UpdatePostStatusRequest) (*postDTOs.PostDTO, error) {
+	if _, err := s.postRepo.GetPostByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.authors.Authorize(ctx, id, userID, role, entities.PostAuthorRole.CanPublish, "publish"); err != nil {
+		return nil, err
+	}
+
+	if err := s.postRepo.UpdatePostStatus(ctx, id, input.Status); err != nil {
+		return nil, err
+	}
+	s.related.Invalidate(ctx, id)
+
+	return s.getEditedPost(ctx, id)
+}
+
+// UpdatePostVisibility sets who may read a post's full content
+func (s *PostService) UpdatePostVisibility(ctx context.Context, id int, userID uuid.UUID, role entities.Role, input postDTOs.UpdatePostVisibilityRequest) (*postDTOs.PostDTO, error) {
+	if _, err := s.postRepo.GetPostByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.authors.Authorize(ctx, id, userID, role, entities.PostAuthorRole.CanPublish, "change the visibility of"); err != nil {
+		return nil, err
+	}
+
+	roles := []string{}
+	if entities.PostVisibility(input.Visibility) == entities.PostVisibilityRoles {
+		if len(input.Roles) == 0 {
+			return nil, appErrors.New(appErrors.CodeBadRequest, "roles are required for role-restricted posts")
+		}
+		roles = input.Roles
+	}
+
+	if err := s.postRepo.UpdatePostVisibility(ctx, id, input.Visibility, roles); err != nil {
+		return nil, err
+	}
+
+	return s.getEditedPost(ctx, id)
+}
+
+// UpdatePostTranslation sets a post's locale and links it as a translation of another
+// post, or detaches it from its translations when no post is given. Both posts must be editable.
+func (s *PostService) UpdatePostTranslation(ctx context.Context, id int, userID uuid.UUID, role entities.Role, input postDTOs.UpdatePostTranslationRequest) (*postDTOs.PostDTO, error) {
+	if _, err := s.postRepo.GetPostByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.authors.Authorize(ctx, id, userID, role, entities.PostAuthorRole.CanEdit, "edit"); err != nil {
+		return nil, err
+	}
+
+	locale, err := s.supportedLocale(input.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	groupID := ""
+	if input.TranslationOf != nil {
+		if *input.TranslationOf == id {
+			return nil, appErrors.New(appErrors.CodeBadRequest, "a post cannot be a translation of itself")
+		}
+		source, err := s.translatedPost(ctx, *input.TranslationOf, userID, role)
+		if err != nil {
+			return nil, err
+		}
+		groupID = source.TranslationGroupID
+	}
+
+	if err := s.postRepo.UpdatePostTranslation(ctx, id, locale, groupID); err != nil {
+		return nil, err
+	}
+
+	return s.getEditedPost(ctx, id)
+}
+
+// translatedPost loads the post a new translation is linked to, which the user must be able to edit
+func (s *PostService) translatedPost(ctx context.Context, id int, userID uuid.UUID, role entities.Role) (*postDTOs.PostDTO, error) {
+	source, err := s.postRepo.GetPostByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authors.Authorize(ctx, id, userID, role, entities.PostAuthorRole.CanEdit, "translate"); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func (s *PostService) supportedLocale(locale string) (string, error) {
+	canonical, ok := s.locales.Canonical(locale)
+	if !ok {
+		return "", appErrors.New(appErrors.CodeBadRequest, "unsupported locale: "+locale)
+	}
+	return canonical, nil
+}
+
+// translation returns the post's translation in locale, or the post itself when there is none the caller may see
+func (s *PostService) translation(ctx context.Context, post *postDTOs.PostDTO, locale string, userID uuid.UUID, role entities.Role) (*postDTOs.PostDTO, error) {
+	translation, err := s.postRepo.GetPublishedTranslation(ctx, post.TranslationGroupID, locale)
+	if err != nil {
+		if appErr, ok := err.(*appErrors.AppError); ok && appErr.Code == appErrors.CodeNotFound {
+			return post, nil
+		}
+		return nil, err
+	}
+	if !canSeePost(translation, userID, role) {
+		return post, nil
+	}
+	return translation, nil
+}
+
+// attachTranslations links the post to its other listed translations
+func (s *PostService) attachTranslations(ctx context.Context, post *postDTOs.PostDTO) error {
+	translations, err := s.postRepo.GetPostTranslations(ctx, post.TranslationGroupID)
+	if err != nil {
+		return err
+	}
+	for _, translation := range translations {
+		if translation.ID != post.ID {
+			post.Translations = append(post.Translations, translation)
+		}
+	}
+	return nil
+}
+
+// syncMedia records the media files linked from a saved post's content. The post is
+// saved either way, so a failure is only logged.
+func (s *PostService) syncMedia(ctx context.Context, post *postDTOs.PostDTO) {
+	if err := s.media.SyncPostMedia(ctx, post.ID, post.Content); err != nil {
+		s.logger.Error("Failed to record post media", "postID", post.ID, "error", err)
+	}
+}
+
+func (s *PostService) getEditedPost(ctx context.Context, id int) (*postDTOs.PostDTO, error) {
+	post, err := s.postRepo.GetPostByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.renderer.RenderPost(ctx, post); err != nil {
+		return nil, err
+	}
+	if err := s.engagement.AttachReactionCounts(ctx, []*postDTOs.PostDTO{post}); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/i18n"
+	"app05/internal/infrastructure/postio"
+	"app05/pkg/appErrors"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gosimple/slug"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Column limits of the posts table
+const (
+	maxPostTitleLength   = 255
+	maxPostExcerptLength = 500
+)
+
+// preparedPost is an imported post that passed validation, ready to be stored
+type preparedPost struct {
+	post        *postDTOs.PostDTO
+	tags        []string
+	categoryIDs []int
+}
+
+// PostTransferService imports posts from Markdown and WordPress exports and exports them as Markdown
+type PostTransferService struct {
+	postRepo     repositories.PostRepository
+	taxonomyRepo repositories.TaxonomyRepository
+	locales      *i18n.Locales
+	logger       contracts.Logger
+}
+
+func NewPostTransferService(postRepo repositories.PostRepository, taxonomyRepo repositories.TaxonomyRepository, locales *i18n.Locales, logger contracts.Logger) *PostTransferService {
+	return &PostTransferService{
+		postRepo:     postRepo,
+		taxonomyRepo: taxonomyRepo,
+		locales:      locales,
+		logger:       logger,
+	}
+}
+
+// Import stores the loaded posts under the given author. Each post is checked and
+// reported on individually; a dry run reports what would happen without writing anything.
+func (s *PostTransferService) Import(ctx context.Context, loaded *postio.Loaded, authorID uuid.UUID, options postDTOs.ImportOptions) (*postDTOs.ImportReportDTO, error) {
+	switch options.OnConflict {
+	case "":
+		options.OnConflict = postDTOs.ImportConflictSkip
+	case postDTOs.ImportConflictSkip, postDTOs.ImportConflictRename:
+	default:
+		return nil, appErrors.New(appErrors.CodeBadRequest, "conflict strategy must be skip or rename")
+	}
+
+	report := &postDTOs.ImportReportDTO{
+		DryRun: options.DryRun,
+		Items:  append([]*postDTOs.ImportItemReportDTO{}, loaded.Failures...),
+	}
+
+	// Slugs claimed by earlier posts of this import, which don't exist in the database during a dry run
+	claimed := map[string]bool{}
+	slugTaken := func(ctx context.Context, candidate string) (bool, error) {
+		if claimed[candidate] {
+			return true, nil
+		}
+		return s.postRepo.PostSlugExists(ctx, candidate)
+	}
+
+	for _, imported := range loaded.Posts {
+		item, prepared, err := s.preparePost(ctx, imported, authorID, options, slugTaken)
+		if err != nil {
+			return nil, err
+		}
+		report.Items = append(report.Items, item)
+
+		if item.Action != postDTOs.ImportActionCreate {
+			continue
+		}
+		claimed[item.Slug] = true
+
+		if options.DryRun {
+			continue
+		}
+
+		if err := s.createPost(ctx, prepared); err != nil {
+			// Fail the item rather than the whole import; earlier posts are already stored
+			s.logger.Error("Failed to import post", "source", item.Source, "error", err)
+			item.Action = postDTOs.ImportActionSkip
+			item.Errors = append(item.Errors, importErrorMessage(err))
+			continue
+		}
+		item.PostID = &prepared.post.ID
+	}
+
+	for _, item := range report.Items {
+		report.Total++
+		if item.Action == postDTOs.ImportActionCreate {
+			report.Created++
+		} else {
+			report.Skipped++
+		}
+	}
+
+	return report, nil
+}
+
+// preparePost validates an imported post and resolves its slug. Problems that make the
+// post unusable are reported on the item, only lookup failures are returned as errors.
+func (s *PostTransferService) preparePost(
+	ctx context.Context,
+	imported *postDTOs.ImportedPostDTO,
+	authorID uuid.UUID,
+	options postDTOs.ImportOptions,
+	slugTaken func(context.Context, string) (bool, error),
+) (*postDTOs.ImportItemReportDTO, *preparedPost, error) {
+	item := &postDTOs.ImportItemReportDTO{
+		Source: imported.Source,
+		Title:  imported.Title,
+		Action: postDTOs.ImportActionCreate,
+	}
+
+	if utf8.RuneCountInString(imported.Title) > maxPostTitleLength {
+		item.Errors = append(item.Errors, fmt.Sprintf("title is longer than %d characters", maxPostTitleLength))
+	}
+	if strings.TrimSpace(imported.Content) == "" {
+		item.Errors = append(item.Errors, "post has no content")
+	}
+	if imported.Excerpt != nil && utf8.RuneCountInString(*imported.Excerpt) > maxPostExcerptLength {
+		item.Errors = append(item.Errors, fmt.Sprintf("excerpt is longer than %d characters", maxPostExcerptLength))
+	}
+
+	status := entities.PostStatus(imported.Status)
+	if status == "" {
+		status = entities.PostStatusDraft
+	}
+	if !status.IsValid() {
+		item.Errors = append(item.Errors, fmt.Sprintf("unknown status %q", imported.Status))
+	}
+
+	var tags []string
+	for _, name := range imported.Tags {
+		if strings.TrimSpace(name) == "" {
+			item.Warnings = append(item.Warnings, "blank tag ignored")
+			continue
+		}
+		tags = append(tags, name)
+	}
+
+	var categoryIDs []int
+	for _, categorySlug := range imported.Categories {
+		category, err := s.taxonomyRepo.GetCategoryBySlug(ctx, categorySlug)
+		if err != nil {
+			if appErr, ok := err.(*appErrors.AppError); ok && appErr.Code == appErrors.CodeNotFound {
+				item.Warnings = append(item.Warnings, fmt.Sprintf("category %q does not exist and will not be assigned", categorySlug))
+				continue
+			}
+			return nil, nil, err
+		}
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+
+	postSlug, err := s.resolveSlug(ctx, imported, options, slugTaken, item)
+	if err != nil {
+		return nil, nil, err
+	}
+	item.Slug = postSlug
+
+	if len(item.Errors) > 0 {
+		item.Action = postDTOs.ImportActionSkip
+		return item, nil, nil
+	}
+
+	post := &postDTOs.PostDTO{
+		UserID:  authorID.String(),
+		Title:   imported.Title,
+		Content: imported.Content,
+		Excerpt: imported.Excerpt,
+		Status:  string(status),
+		Slug:    &postSlug,
+		Locale:  s.locales.Default(), // imported posts are written in the default locale
+	}
+	if status == entities.PostStatusPublished {
+		publishedAt := time.Now()
+		if imported.PublishedAt != nil {
+			publishedAt = *imported.PublishedAt
+		}
+		post.PublishedAt = &publishedAt
+	}
+
+	return item, &preparedPost{post: post, tags: tags, categoryIDs: categoryIDs}, nil
+}
+
+func (s *PostTransferService) resolveSlug(
+	ctx context.Context,
+	imported *postDTOs.ImportedPostDTO,
+	options postDTOs.ImportOptions,
+	slugTaken func(context.Context, string) (bool, error),
+	item *postDTOs.ImportItemReportDTO,
+) (string, error) {
+	if imported.Slug == "" {
+		postSlug, err := uniqueSlug(ctx, imported.Title, slugTaken)
+		if err != nil {
+			if _, ok := err.(*appErrors.AppError); ok {
+				item.Errors = append(item.Errors, "a slug cannot be generated from the title")
+				return "", nil
+			}
+			return "", err
+		}
+		return postSlug, nil
+	}
+
+	postSlug := slug.Make(imported.Slug)
+	if postSlug == "" {
+		item.Errors = append(item.Errors, fmt.Sprintf("invalid slug %q", imported.Slug))
+		return "", nil
+	}
+	if postSlug != imported.Slug {
+		item.Warnings = append(item.Warnings, fmt.Sprintf("slug %q normalized to %q", imported.Slug, postSlug))
+	}
+
+	taken, err := slugTaken(ctx, postSlug)
+	if err != nil {
+		return "", err
+	}
+	if !taken {
+		return postSlug, nil
+	}
+
+	if options.OnConflict != postDTOs.ImportConflictRename {
+		item.Errors = append(item.Errors, fmt.Sprintf("slug %q is already in use", postSlug))
+		return postSlug, nil
+	}
+
+	renamed, err := uniqueSlug(ctx, postSlug, slugTaken)
+	if err != nil {
+		return "", err
+	}
+	item.Warnings = append(item.Warnings, fmt.Sprintf("slug %q is already in use, renamed to %q", postSlug, renamed))
+	return renamed, nil
+}
+
+// createPost stores a prepared post together with its tags and categories. Missing tags
+// are created first; they are kept even when the post fails to store.
+func (s *PostTransferService) createPost(ctx context.Context, prepared *preparedPost) error {
+	tagIDs := make([]int, 0, len(prepared.tags))
+	for _, name := range prepared.tags {
+		tag, err := findOrCreateTag(ctx, s.taxonomyRepo, name)
+		if err != nil {
+			return err
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	return s.postRepo.ImportPost(ctx, prepared.post, tagIDs, prepared.categoryIDs)
+}
+
+// importErrorMessage describes why a post failed to store without revealing database details
+func importErrorMessage(err error) string {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	return "post could not be stored"
+}
+
+// Export writes every post, whatever its status, as Markdown files in a zip archive
+func (s *PostTransferService) Export(ctx context.Context, w io.Writer) error {
+	posts, err := s.postRepo.GetAllPosts(ctx, true, uuid.Nil)
+	if err != nil {
+		return err
+	}
+
+	return postio.WriteMarkdownZip(w, posts)
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/courseDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/pkg/appErrors"
+	"context"
+	"github.com/google/uuid"
+)
+
+// ProgressService tracks how far students got through the lessons of a course. A course
+// is completed once all of its required, published lessons are.
+type ProgressService struct {
+	progressRepo   repositories.ProgressRepository
+	enrollmentRepo repositories.EnrollmentRepository
+	courseRepo     repositories.CourseRepository
+	courses        *CourseService
+	logger         contracts.Logger
+}
+
+func NewProgressService(
+	progressRepo repositories.ProgressRepository,
+	enrollmentRepo repositories.EnrollmentRepository,
+	courseRepo repositories.CourseRepository,
+	courses *CourseService,
+	logger contracts.Logger,
+) *ProgressService {
+	return &ProgressService{
+		progressRepo:   progressRepo,
+		enrollmentRepo: enrollmentRepo,
+		courseRepo:     courseRepo,
+		courses:        courses,
+		logger:         logger,
+	}
+}
+
+// GetProgress returns the user's progress through a published course with the lesson to resume
+func (s *ProgressService) GetProgress(ctx context.Context, slug string, userID uuid.UUID) (*entities.CourseProgress, error) {
+	course, err := s.courses.GetCourseBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	lessons, err := s.progressRepo.GetLessonProgress(ctx, userID, course.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := courseProgress(course, lessons)
+
+	enrollment, err := s.enrollmentRepo.GetEnrollment(ctx, course.ID, userID)
+	if err != nil {
+		// Owners and admins read courses without being enrolled
+		if appErr, ok := err.(*appErrors.AppError); ok && appErr.Code == appErrors.CodeNotFound {
+			return progress, nil
+		}
+		return nil, err
+	}
+	progress.CompletedAt = enrollment.CompletedAt
+
+	return progress, nil
+}
+
+// SaveCheckpoint records the video position the user reached in a lesson
+func (s *ProgressService) SaveCheckpoint(ctx context.Context, slug string, lessonID int, userID uuid.UUID, input courseDTOs.LessonCheckpointRequest) (*entities.LessonProgress, error) {
+	lesson, err := s.courses.GetPublishedLesson(ctx, slug, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.progressRepo.SaveCheckpoint(ctx, userID, lesson.ID, input.PositionSeconds)
+}
+
+// CompleteLesson marks a lesson completed, completing the course when it was the last
+// required lesson, and returns the user's progress through the course
+func (s *ProgressService) CompleteLesson(ctx context.Context, slug string, lessonID int, userID uuid.UUID) (*entities.CourseProgress, error) {
+	lesson, err := s.courses.GetPublishedLesson(ctx, slug, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.progressRepo.CompleteLesson(ctx, userID, lesson.ID); err != nil {
+		return nil, err
+	}
+
+	completed, err := s.progressRepo.CompleteCourse(ctx, lesson.CourseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if completed {
+		s.logger.Info("Course completed", "course", lesson.CourseID, "user", userID)
+	}
+
+	return s.GetProgress(ctx, slug, userID)
+}
+
+// GetClassProgress summarizes the progress of a course's students for its owner or an admin
+func (s *ProgressService) GetClassProgress(ctx context.Context, courseID int, userID uuid.UUID, role entities.Role) (*entities.ClassProgress, error) {
+	course, err := s.courseRepo.GetCourseByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if course.UserID != userID.String() && !role.IsAdmin() {
+		return nil, appErrors.New(appErrors.CodeForbidden, "You can only view the progress of your own courses")
+	}
+
+	return s.progressRepo.GetClassProgress(ctx, courseID)
+}
+
+// courseProgress lists the progress through every published lesson of a course outline in
+// order. The lesson to resume is the most recently viewed one not yet completed, or else
+// the first one not yet completed.
+func courseProgress(course *entities.Course, viewed []*entities.LessonProgress) *entities.CourseProgress {
+	progress := &entities.CourseProgress{
+		CourseID: course.ID,
+		Lessons:  []*entities.LessonProgress{},
+	}
+
+	byLesson := make(map[int]*entities.LessonProgress, len(viewed))
+	for _, lesson := range viewed {
+		byLesson[lesson.LessonID] = lesson
+	}
+
+	published := map[int]bool{}
+	var firstIncomplete *entities.LessonProgress
+	for _, module := range course.Modules {
+		for _, lesson := range module.Lessons {
+			published[lesson.ID] = true
+
+			lessonProgress, ok := byLesson[lesson.ID]
+			if !ok {
+				lessonProgress = &entities.LessonProgress{LessonID: lesson.ID}
+			}
+			progress.Lessons = append(progress.Lessons, lessonProgress)
+
+			if lesson.IsRequired {
+				progress.RequiredLessons++
+				if lessonProgress.CompletedAt != nil {
+					progress.CompletedLessons++
+				}
+			}
+			if lessonProgress.CompletedAt == nil && firstIncomplete == nil {
+				firstIncomplete = lessonProgress
+			}
+		}
+	}
+
+	if progress.RequiredLessons > 0 {
+		progress.Percent = 100 * progress.CompletedLessons / progress.RequiredLessons
+	}
+
+	// viewed is ordered by when each lesson was last viewed, most recent first
+	for _, lesson := range viewed {
+		if published[lesson.LessonID] && lesson.CompletedAt == nil {
+			progress.Resume = lesson
+			break
+		}
+	}
+	if progress.Resume == nil {
+		progress.Resume = firstIncomplete
+	}
+
+	return progress
+}
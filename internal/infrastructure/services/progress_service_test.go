@@ -0,0 +1,273 @@
+package services
+
+import (
+	"app05/internal/core/domain/dtos/courseDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/pkg/appErrors"
+	"context"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+// fakeProgressRepo records completed lessons and checkpoints. Whether a course is
+// completed is decided by the database, so CompleteCourse only records that it was asked.
+type fakeProgressRepo struct {
+	repositories.ProgressRepository
+	lessons          []*entities.LessonProgress // most recently viewed first
+	checkpoints      map[int]int
+	completedCourses []int
+	class            *entities.ClassProgress
+}
+
+func (r *fakeProgressRepo) SaveCheckpoint(ctx context.Context, userID uuid.UUID, lessonID, positionSeconds int) (*entities.LessonProgress, error) {
+	if r.checkpoints == nil {
+		r.checkpoints = map[int]int{}
+	}
+	r.checkpoints[lessonID] = positionSeconds
+	return &entities.LessonProgress{LessonID: lessonID, PositionSeconds: positionSeconds}, nil
+}
+
+func (r *fakeProgressRepo) CompleteLesson(ctx context.Context, userID uuid.UUID, lessonID int) (*entities.LessonProgress, error) {
+	for _, lesson := range r.lessons {
+		if lesson.LessonID == lessonID {
+			if lesson.CompletedAt == nil {
+				now := time.Now()
+				lesson.CompletedAt = &now
+			}
+			return lesson, nil
+		}
+	}
+	now := time.Now()
+	lesson := &entities.LessonProgress{LessonID: lessonID, CompletedAt: &now, LastViewedAt: &now}
+	r.lessons = append([]*entities.LessonProgress{lesson}, r.lessons...)
+	return lesson, nil
+}
+
+func (r *fakeProgressRepo) GetLessonProgress(ctx context.Context, userID uuid.UUID, courseID int) ([]*entities.LessonProgress, error) {
+	return r.lessons, nil
+}
+
+func (r *fakeProgressRepo) CompleteCourse(ctx context.Context, courseID int, userID uuid.UUID) (bool, error) {
+	r.completedCourses = append(r.completedCourses, courseID)
+	return false, nil
+}
+
+func (r *fakeProgressRepo) GetClassProgress(ctx context.Context, courseID int) (*entities.ClassProgress, error) {
+	return r.class, nil
+}
+
+func TestCourseProgress(t *testing.T) {
+	course := &entities.Course{ID: 1, Modules: []*entities.Module{
+		{ID: 10, Lessons: []*entities.Lesson{{ID: 1, IsRequired: true}, {ID: 2}}},
+		{ID: 11, Lessons: []*entities.Lesson{{ID: 3, IsRequired: true}, {ID: 4, IsRequired: true}}},
+	}}
+	done := time.Now()
+	completed := func(id int) *entities.LessonProgress {
+		return &entities.LessonProgress{LessonID: id, CompletedAt: &done, LastViewedAt: &done}
+	}
+	viewed := func(id int) *entities.LessonProgress {
+		return &entities.LessonProgress{LessonID: id, PositionSeconds: 30, LastViewedAt: &done}
+	}
+
+	tests := []struct {
+		name      string
+		viewed    []*entities.LessonProgress
+		percent   int
+		completed int
+		resume    int // 0 for none
+	}{
+		{name: "not started", resume: 1},
+		{name: "first lesson completed", viewed: []*entities.LessonProgress{completed(1)}, percent: 33, completed: 1, resume: 2},
+		{name: "optional lessons don't count", viewed: []*entities.LessonProgress{completed(2)}, percent: 0, resume: 1},
+		{name: "resume the lesson viewed last", viewed: []*entities.LessonProgress{viewed(4), completed(1), viewed(3)}, percent: 33, completed: 1, resume: 4},
+		{name: "skip completed lessons when resuming", viewed: []*entities.LessonProgress{completed(4), viewed(3)}, percent: 33, completed: 1, resume: 3},
+		{name: "ignore lessons no longer published", viewed: []*entities.LessonProgress{viewed(99), completed(1)}, percent: 33, completed: 1, resume: 2},
+		{name: "required lessons completed", viewed: []*entities.LessonProgress{completed(4), completed(3), completed(1)}, percent: 100, completed: 3, resume: 2},
+		{name: "all lessons completed", viewed: []*entities.LessonProgress{completed(4), completed(3), completed(2), completed(1)}, percent: 100, completed: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := courseProgress(course, tt.viewed)
+
+			if progress.RequiredLessons != 3 || progress.CompletedLessons != tt.completed || progress.Percent != tt.percent {
+				t.Errorf("got %d of %d required lessons (%d%%), want %d of 3 (%d%%)",
+					progress.CompletedLessons, progress.RequiredLessons, progress.Percent, tt.completed, tt.percent)
+			}
+
+			resume := 0
+			if progress.Resume != nil {
+				resume = progress.Resume.LessonID
+			}
+			if resume != tt.resume {
+				t.Errorf("Resume = lesson %d, want lesson %d", resume, tt.resume)
+			}
+
+			// Every published lesson is listed once, in outline order
+			if len(progress.Lessons) != 4 {
+				t.Fatalf("got %d lessons, want 4", len(progress.Lessons))
+			}
+			for i, lesson := range progress.Lessons {
+				if lesson.LessonID != i+1 {
+					t.Fatalf("lesson %d is %d, want %d", i, lesson.LessonID, i+1)
+				}
+			}
+		})
+	}
+
+	// A course without required lessons has no progress to report
+	empty := courseProgress(&entities.Course{ID: 2, Modules: []*entities.Module{{Lessons: []*entities.Lesson{{ID: 5}}}}}, nil)
+	if empty.Percent != 0 || empty.RequiredLessons != 0 || empty.Resume == nil || empty.Resume.LessonID != 5 {
+		t.Fatalf("unexpected progress %+v", empty)
+	}
+}
+
+func newProgressTest(t *testing.T) (*ProgressService, *fakeProgressRepo, *fakeEnrollmentRepo, uuid.UUID) {
+	t.Helper()
+
+	owner := uuid.New()
+	courses := &fakeCourseRepo{
+		courses: []*entities.Course{
+			{ID: 1, UserID: owner.String(), Slug: "course", Status: entities.CourseStatusPublished},
+			{ID: 2, UserID: owner.String(), Slug: "other", Status: entities.CourseStatusPublished},
+			{ID: 3, UserID: owner.String(), Slug: "draft", Status: entities.CourseStatusDraft},
+		},
+		modules: []*entities.Module{
+			{ID: 10, CourseID: 1, Status: entities.CourseStatusPublished},
+			{ID: 11, CourseID: 1, Status: entities.CourseStatusDraft},
+			{ID: 20, CourseID: 2, Status: entities.CourseStatusPublished},
+			{ID: 30, CourseID: 3, Status: entities.CourseStatusPublished},
+		},
+		lessons: []*entities.Lesson{
+			{ID: 100, ModuleID: 10, CourseID: 1, Status: entities.CourseStatusPublished, IsRequired: true},
+			{ID: 101, ModuleID: 10, CourseID: 1, Status: entities.CourseStatusPublished, IsRequired: true},
+			{ID: 102, ModuleID: 10, CourseID: 1, Status: entities.CourseStatusDraft, IsRequired: true},
+			{ID: 110, ModuleID: 11, CourseID: 1, Status: entities.CourseStatusPublished, IsRequired: true},
+			{ID: 200, ModuleID: 20, CourseID: 2, Status: entities.CourseStatusPublished, IsRequired: true},
+			{ID: 300, ModuleID: 30, CourseID: 3, Status: entities.CourseStatusPublished, IsRequired: true},
+		},
+	}
+	progress := &fakeProgressRepo{}
+	enrollments := newFakeEnrollmentRepo()
+	service := NewProgressService(progress, enrollments, courses, NewCourseService(courses, nopLogger{}), nopLogger{})
+	return service, progress, enrollments, owner
+}
+
+func TestProgressService_CompleteLesson(t *testing.T) {
+	tests := []struct {
+		name     string
+		slug     string
+		lessonID int
+		wantErr  appErrors.ErrorCode
+		percent  int
+	}{
+		{name: "published lesson", slug: "course", lessonID: 100, percent: 50},
+		{name: "draft lesson", slug: "course", lessonID: 102, wantErr: appErrors.CodeNotFound},
+		{name: "lesson of a draft module", slug: "course", lessonID: 110, wantErr: appErrors.CodeNotFound},
+		{name: "lesson of another course", slug: "course", lessonID: 200, wantErr: appErrors.CodeNotFound},
+		{name: "lesson of a draft course", slug: "draft", lessonID: 300, wantErr: appErrors.CodeNotFound},
+		{name: "unknown lesson", slug: "course", lessonID: 999, wantErr: appErrors.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, progressRepo, _, _ := newProgressTest(t)
+			ctx := context.Background()
+			student := uuid.New()
+
+			progress, err := service.CompleteLesson(ctx, tt.slug, tt.lessonID, student)
+			if tt.wantErr.Code != "" {
+				assertAppError(t, err, tt.wantErr)
+				if len(progressRepo.lessons) != 0 || len(progressRepo.completedCourses) != 0 {
+					t.Fatal("progress was recorded for a lesson that can't be read")
+				}
+				if _, err := service.SaveCheckpoint(ctx, tt.slug, tt.lessonID, student, courseDTOs.LessonCheckpointRequest{PositionSeconds: 10}); err == nil {
+					t.Fatal("a checkpoint was saved for a lesson that can't be read")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Every completed lesson may complete the course
+			if len(progressRepo.completedCourses) != 1 || progressRepo.completedCourses[0] != 1 {
+				t.Fatalf("CompleteCourse calls = %v, want [1]", progressRepo.completedCourses)
+			}
+			if progress.Percent != tt.percent || progress.RequiredLessons != 2 {
+				t.Fatalf("got %d%% of %d required lessons, want %d%% of 2", progress.Percent, progress.RequiredLessons, tt.percent)
+			}
+			if progress.Resume == nil || progress.Resume.LessonID != 101 {
+				t.Fatalf("Resume = %+v, want lesson 101", progress.Resume)
+			}
+		})
+	}
+}
+
+func TestProgressService_GetProgress(t *testing.T) {
+	service, progressRepo, enrollments, owner := newProgressTest(t)
+	ctx := context.Background()
+
+	completedAt := time.Now()
+	student := uuid.New()
+	enrollments.CreateEnrollment(ctx, &entities.Enrollment{CourseID: 1, UserID: student.String(), CompletedAt: &completedAt})
+	progressRepo.CompleteLesson(ctx, student, 100)
+	progressRepo.CompleteLesson(ctx, student, 101)
+
+	progress, err := service.GetProgress(ctx, "course", student)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if progress.Percent != 100 || progress.CompletedAt == nil || !progress.CompletedAt.Equal(completedAt) || progress.Resume != nil {
+		t.Fatalf("unexpected progress for a completed course: %+v", progress)
+	}
+
+	// Owners read their courses without being enrolled
+	progressRepo.lessons = nil
+	progress, err = service.GetProgress(ctx, "course", owner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if progress.Percent != 0 || progress.CompletedAt != nil {
+		t.Fatalf("unexpected progress for the owner: %+v", progress)
+	}
+
+	_, err = service.GetProgress(ctx, "draft", student)
+	assertAppError(t, err, appErrors.CodeNotFound)
+}
+
+func TestProgressService_GetClassProgress(t *testing.T) {
+	tests := []struct {
+		name    string
+		owner   bool
+		role    entities.Role
+		wantErr appErrors.ErrorCode
+	}{
+		{name: "owner", owner: true, role: entities.RoleInstructor},
+		{name: "admin", role: entities.RoleAdmin},
+		{name: "another instructor", role: entities.RoleInstructor, wantErr: appErrors.CodeForbidden},
+		{name: "student", role: entities.RoleStudent, wantErr: appErrors.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, progressRepo, _, owner := newProgressTest(t)
+			progressRepo.class = &entities.ClassProgress{CourseID: 1, Enrolled: 3}
+			userID := uuid.New()
+			if tt.owner {
+				userID = owner
+			}
+
+			class, err := service.GetClassProgress(context.Background(), 1, userID, tt.role)
+			if tt.wantErr.Code != "" {
+				assertAppError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if class.Enrolled != 3 {
+				t.Fatalf("unexpected class progress %+v", class)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/cache"
+	"context"
+	"github.com/google/uuid"
+)
+
+// coViewCandidates is how many of a post's strongest co-views are considered when ranking
+const coViewCandidates = 50
+
+// RelatedPostService recommends posts related to a post. Rankings are cached per post
+// as ids only, so the posts themselves are always current when served.
+type RelatedPostService struct {
+	postRepo   repositories.PostRepository
+	renderer   *ContentRenderer
+	engagement *EngagementService
+	cache      *cache.RelatedCache
+	coViews    *cache.CoViewTracker
+	limit      int
+	logger     contracts.Logger
+}
+
+func NewRelatedPostService(
+	postRepo repositories.PostRepository,
+	renderer *ContentRenderer,
+	engagement *EngagementService,
+	cache *cache.RelatedCache,
+	coViews *cache.CoViewTracker,
+	limit int,
+	logger contracts.Logger,
+) *RelatedPostService {
+	return &RelatedPostService{
+		postRepo:   postRepo,
+		renderer:   renderer,
+		engagement: engagement,
+		cache:      cache,
+		coViews:    coViews,
+		limit:      limit,
+		logger:     logger,
+	}
+}
+
+// GetRelatedPosts returns the posts most related to a published post, withholding content the caller may not read
+func (s *RelatedPostService) GetRelatedPosts(ctx context.Context, slug string, userID uuid.UUID, role entities.Role) ([]*postDTOs.PostDTO, error) {
+	post, err := s.postRepo.GetPublishedPostBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPostVisible(post, userID, role); err != nil {
+		return nil, err
+	}
+
+	ids, err := s.relatedPostIDs(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []*postDTOs.PostDTO{}, nil
+	}
+
+	posts, err := s.postRepo.GetListedPostsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.renderer.RenderPosts(ctx, posts); err != nil {
+		return nil, err
+	}
+	if err := s.engagement.AttachReactionCounts(ctx, posts); err != nil {
+		return nil, err
+	}
+	restrictPosts(posts, userID, role)
+
+	return posts, nil
+}
+
+// relatedPostIDs returns the cached ranking of a post, ranking it again when missing.
+// Cache and co-view failures are logged and fall back to ranking without them.
+func (s *RelatedPostService) relatedPostIDs(ctx context.Context, postID int) ([]int, error) {
+	ids, found, err := s.cache.Get(ctx, postID)
+	if err != nil {
+		s.logger.Warn("Failed to read related posts from cache", "postID", postID, "error", err)
+	}
+	if found {
+		return ids, nil
+	}
+
+	coViews, err := s.coViews.TopCoViews(ctx, postID, coViewCandidates)
+	if err != nil {
+		s.logger.Warn("Failed to read post co-views", "postID", postID, "error", err)
+	}
+
+	ids, err = s.postRepo.GetRelatedPostIDs(ctx, postID, coViews, s.limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Set(ctx, postID, ids); err != nil {
+		s.logger.Warn("Failed to cache related posts", "postID", postID, "error", err)
+	}
+
+	return ids, nil
+}
+
+// RecordView counts the post as read together with the visitor's other recent reads
+func (s *RelatedPostService) RecordView(ctx context.Context, visitorID string, postID int) {
+	if err := s.coViews.RecordView(ctx, visitorID, postID); err != nil {
+		s.logger.Error("Failed to record post co-views", "postID", postID, "error", err)
+	}
+}
+
+// Invalidate drops a post's cached ranking after it is edited
+func (s *RelatedPostService) Invalidate(ctx context.Context, postID int) {
+	if err := s.cache.Delete(ctx, postID); err != nil {
+		s.logger.Error("Failed to invalidate related posts", "postID", postID, "error", err)
+	}
+}
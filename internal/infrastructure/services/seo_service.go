@@ -0,0 +1,225 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/sitemap"
+	"app05/pkg/appErrors"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+)
+
+type SEOService struct {
+	postRepo repositories.PostRepository
+	renderer *ContentRenderer
+	authors  *PostAuthorService
+	site     SiteInfo
+	logger   contracts.Logger
+}
+
+func NewSEOService(postRepo repositories.PostRepository, renderer *ContentRenderer, authors *PostAuthorService, site SiteInfo, logger contracts.Logger) *SEOService {
+	return &SEOService{
+		postRepo: postRepo,
+		renderer: renderer,
+		authors:  authors,
+		site:     site,
+		logger:   logger,
+	}
+}
+
+// GetSitemap returns the sitemap of published posts. Once there are more posts than
+// a single sitemap may hold, it returns an index of numbered sitemaps instead.
+func (s *SEOService) GetSitemap(ctx context.Context) (*sitemap.Document, error) {
+	entries, total, err := s.postRepo.GetSitemapEntries(ctx, sitemap.MaxURLs, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if total <= sitemap.MaxURLs {
+		return sitemap.EncodeURLSet(s.sitemapURLs(entries))
+	}
+
+	pages := (total + sitemap.MaxURLs - 1) / sitemap.MaxURLs
+	sitemaps := make([]sitemap.URL, 0, pages)
+	for page := 1; page <= pages; page++ {
+		sitemaps = append(sitemaps, sitemap.URL{
+			Loc: fmt.Sprintf("%s/sitemaps/posts-%d.xml", strings.TrimRight(s.site.APIURL, "/"), page),
+		})
+	}
+
+	return sitemap.EncodeIndex(sitemaps)
+}
+
+// GetSitemapPage returns one of the numbered sitemaps listed in the sitemap index
+func (s *SEOService) GetSitemapPage(ctx context.Context, page int) (*sitemap.Document, error) {
+	if page < 1 {
+		return nil, appErrors.New(appErrors.CodeNotFound, "sitemap not found")
+	}
+
+	entries, _, err := s.postRepo.GetSitemapEntries(ctx, sitemap.MaxURLs, (page-1)*sitemap.MaxURLs)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, appErrors.New(appErrors.CodeNotFound, "sitemap not found")
+	}
+
+	return sitemap.EncodeURLSet(s.sitemapURLs(entries))
+}
+
+func (s *SEOService) sitemapURLs(entries []*postDTOs.SitemapEntryDTO) []sitemap.URL {
+	urls := make([]sitemap.URL, 0, len(entries))
+	for _, entry := range entries {
+		urls = append(urls, sitemap.URL{Loc: s.site.PostURL(entry.Slug), LastMod: entry.UpdatedAt})
+	}
+	return urls
+}
+
+// GetPostMeta resolves the head metadata of a published post, falling back to its
+// title, excerpt and public URL where no SEO overrides are set
+func (s *SEOService) GetPostMeta(ctx context.Context, slug string) (*postDTOs.PostMetaDTO, error) {
+	post, err := s.postRepo.GetPublishedPostBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPostVisible(post, uuid.Nil, ""); err != nil {
+		return nil, err
+	}
+	if err := s.renderer.RenderPost(ctx, post); err != nil {
+		return nil, err
+	}
+
+	title := post.Title
+	if post.SEO.MetaTitle != nil {
+		title = *post.SEO.MetaTitle
+	}
+
+	description := ""
+	if post.SEO.MetaDescription != nil {
+		description = *post.SEO.MetaDescription
+	} else if post.Excerpt != nil {
+		description = *post.Excerpt
+	}
+
+	canonicalURL := s.site.PostURL(slug)
+	if post.SEO.CanonicalURL != nil {
+		canonicalURL = *post.SEO.CanonicalURL
+	}
+
+	meta := &postDTOs.PostMetaDTO{
+		Title:        title,
+		Description:  description,
+		CanonicalURL: canonicalURL,
+		OpenGraph: postDTOs.OpenGraphDTO{
+			Type:          "article",
+			Title:         title,
+			Description:   description,
+			URL:           canonicalURL,
+			SiteName:      s.site.Name,
+			Locale:        openGraphLocale(post.Locale),
+			Image:         post.SEO.OGImageURL,
+			PublishedTime: post.PublishedAt,
+			ModifiedTime:  post.UpdatedAt,
+		},
+		Twitter: postDTOs.TwitterCardDTO{
+			Card:        "summary",
+			Title:       title,
+			Description: description,
+			Image:       post.SEO.OGImageURL,
+		},
+	}
+
+	if len(post.Categories) > 0 {
+		meta.OpenGraph.Section = post.Categories[0].Name
+	}
+	for _, tag := range post.Tags {
+		meta.OpenGraph.Tags = append(meta.OpenGraph.Tags, tag.Name)
+	}
+	if post.SEO.OGImageURL != nil {
+		meta.Twitter.Card = "summary_large_image"
+	}
+
+	translations, err := s.postRepo.GetPostTranslations(ctx, post.TranslationGroupID)
+	if err != nil {
+		return nil, err
+	}
+	s.attachAlternates(meta, post, translations)
+
+	return meta, nil
+}
+
+// attachAlternates lists every listed translation of a post, itself included, as hreflang
+// alternates. The translation in the site's default locale is also the x-default.
+func (s *SEOService) attachAlternates(meta *postDTOs.PostMetaDTO, post *postDTOs.PostDTO, translations []*postDTOs.PostTranslationDTO) {
+	if len(translations) < 2 {
+		return
+	}
+
+	var fallback *postDTOs.HreflangDTO
+	for _, translation := range translations {
+		alternate := &postDTOs.HreflangDTO{Hreflang: translation.Locale, URL: s.site.PostURL(translation.Slug)}
+		meta.Alternates = append(meta.Alternates, alternate)
+
+		if translation.Locale == s.site.Language {
+			fallback = &postDTOs.HreflangDTO{Hreflang: "x-default", URL: alternate.URL}
+		}
+		if translation.ID != post.ID {
+			meta.OpenGraph.Alternates = append(meta.OpenGraph.Alternates, openGraphLocale(translation.Locale))
+		}
+	}
+	if fallback != nil {
+		meta.Alternates = append(meta.Alternates, fallback)
+	}
+}
+
+// openGraphLocale writes a locale the way Open Graph expects, such as "pt_BR"
+func openGraphLocale(locale string) string {
+	return strings.ReplaceAll(locale, "-", "_")
+}
+
+// UpdatePostSEO replaces a post's SEO fields and returns the updated post
+func (s *SEOService) UpdatePostSEO(ctx context.Context, postID int, userID uuid.UUID, role entities.Role, input postDTOs.UpdatePostSEORequest) (*postDTOs.PostDTO, error) {
+	if _, err := s.postRepo.GetPostByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	if err := s.authors.Authorize(ctx, postID, userID, role, entities.PostAuthorRole.CanEdit, "edit"); err != nil {
+		return nil, err
+	}
+
+	seo := postDTOs.PostSEODTO{
+		MetaTitle:       trimmedOrNil(input.MetaTitle),
+		MetaDescription: trimmedOrNil(input.MetaDescription),
+		CanonicalURL:    trimmedOrNil(input.CanonicalURL),
+		OGImageURL:      trimmedOrNil(input.OGImageURL),
+	}
+
+	if err := s.postRepo.UpdatePostSEO(ctx, postID, seo); err != nil {
+		return nil, err
+	}
+
+	post, err := s.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.renderer.RenderPost(ctx, post); err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// trimmedOrNil trims an optional string, treating blank values as unset
+func trimmedOrNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
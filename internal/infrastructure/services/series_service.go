@@ -0,0 +1,237 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/dtos/seriesDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"context"
+	"github.com/google/uuid"
+	"strings"
+)
+
+// SeriesService manages ordered collections of posts such as multi-part tutorials
+type SeriesService struct {
+	seriesRepo repositories.SeriesRepository
+	postRepo   repositories.PostRepository
+	logger     contracts.Logger
+}
+
+func NewSeriesService(seriesRepo repositories.SeriesRepository, postRepo repositories.PostRepository, logger contracts.Logger) *SeriesService {
+	return &SeriesService{
+		seriesRepo: seriesRepo,
+		postRepo:   postRepo,
+		logger:     logger,
+	}
+}
+
+// GetPublishedSeries returns a page of series that have published posts
+func (s *SeriesService) GetPublishedSeries(ctx context.Context, page, perPage int) ([]*seriesDTOs.SeriesDTO, int, error) {
+	return s.seriesRepo.GetPublishedSeries(ctx, perPage, utils.Offset(page, perPage))
+}
+
+// GetSeriesBySlug returns a series with its published posts in order. Series without
+// published posts are not public.
+func (s *SeriesService) GetSeriesBySlug(ctx context.Context, slug string) (*seriesDTOs.SeriesDTO, error) {
+	series, err := s.seriesRepo.GetSeriesBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Posts, err = s.seriesRepo.GetSeriesEntries(ctx, series.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(series.Posts) == 0 {
+		return nil, appErrors.New(appErrors.CodeNotFound, "series not found")
+	}
+
+	return series, nil
+}
+
+// GetManagedSeries returns a series with all of its posts, drafts included, for its owner or an admin
+func (s *SeriesService) GetManagedSeries(ctx context.Context, id int, userID uuid.UUID, role entities.Role) (*seriesDTOs.SeriesDTO, error) {
+	if _, err := s.getOwnedSeries(ctx, id, userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.managedSeries(ctx, id)
+}
+
+func (s *SeriesService) CreateSeries(ctx context.Context, userID uuid.UUID, input seriesDTOs.CreateSeriesRequest) (*seriesDTOs.SeriesDTO, error) {
+	title := strings.TrimSpace(input.Title)
+	slug, err := uniqueSlug(ctx, title, s.seriesRepo.SeriesSlugExists)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &seriesDTOs.SeriesDTO{
+		UserID:      userID.String(),
+		Title:       title,
+		Slug:        slug,
+		Description: trimmedOrNil(input.Description),
+	}
+
+	if err := s.seriesRepo.CreateSeries(ctx, series); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (s *SeriesService) UpdateSeries(ctx context.Context, id int, userID uuid.UUID, role entities.Role, input seriesDTOs.UpdateSeriesRequest) (*seriesDTOs.SeriesDTO, error) {
+	series, err := s.getOwnedSeries(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title != series.Title {
+		series.Slug, err = uniqueSlug(ctx, title, s.seriesRepo.SeriesSlugExists)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	series.Title = title
+	series.Description = trimmedOrNil(input.Description)
+
+	if err := s.seriesRepo.UpdateSeries(ctx, series); err != nil {
+		return nil, err
+	}
+
+	return s.managedSeries(ctx, id)
+}
+
+// DeleteSeries removes a series, leaving its posts in place
+func (s *SeriesService) DeleteSeries(ctx context.Context, id int, userID uuid.UUID, role entities.Role) error {
+	if _, err := s.getOwnedSeries(ctx, id, userID, role); err != nil {
+		return err
+	}
+
+	return s.seriesRepo.DeleteSeries(ctx, id)
+}
+
+// AddPost adds one of the series owner's posts to the series
+func (s *SeriesService) AddPost(ctx context.Context, id int, userID uuid.UUID, role entities.Role, input seriesDTOs.AddSeriesPostRequest) (*seriesDTOs.SeriesDTO, error) {
+	series, err := s.getOwnedSeries(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := s.postRepo.GetPostByID(ctx, input.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != series.UserID {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "only posts by the series author can be added to the series")
+	}
+
+	if err := s.seriesRepo.AddSeriesPost(ctx, id, input.PostID, input.Position); err != nil {
+		return nil, err
+	}
+
+	return s.managedSeries(ctx, id)
+}
+
+func (s *SeriesService) RemovePost(ctx context.Context, id, postID int, userID uuid.UUID, role entities.Role) (*seriesDTOs.SeriesDTO, error) {
+	if _, err := s.getOwnedSeries(ctx, id, userID, role); err != nil {
+		return nil, err
+	}
+
+	if err := s.seriesRepo.RemoveSeriesPost(ctx, id, postID); err != nil {
+		return nil, err
+	}
+
+	return s.managedSeries(ctx, id)
+}
+
+// ReorderPosts puts the posts of a series in the given order
+func (s *SeriesService) ReorderPosts(ctx context.Context, id int, userID uuid.UUID, role entities.Role, postIDs []int) (*seriesDTOs.SeriesDTO, error) {
+	if _, err := s.getOwnedSeries(ctx, id, userID, role); err != nil {
+		return nil, err
+	}
+
+	if err := s.seriesRepo.ReorderSeriesPosts(ctx, id, postIDs); err != nil {
+		return nil, err
+	}
+
+	return s.managedSeries(ctx, id)
+}
+
+// AttachNavigation sets the series position and previous/next posts of a published post.
+// Posts outside a series are left unchanged.
+func (s *SeriesService) AttachNavigation(ctx context.Context, post *postDTOs.PostDTO) error {
+	series, err := s.seriesRepo.GetSeriesByPostID(ctx, post.ID)
+	if err != nil {
+		if appErr, ok := err.(*appErrors.AppError); ok && appErr.Code == appErrors.CodeNotFound {
+			return nil
+		}
+		return err
+	}
+
+	entries, err := s.seriesRepo.GetSeriesEntries(ctx, series.ID, true)
+	if err != nil {
+		return err
+	}
+
+	for i, entry := range entries {
+		if entry.PostID != post.ID {
+			continue
+		}
+
+		navigation := &postDTOs.PostSeriesDTO{
+			ID:       series.ID,
+			Title:    series.Title,
+			Slug:     series.Slug,
+			Position: entry.Position,
+			Total:    len(entries),
+		}
+		if i > 0 {
+			navigation.Previous = seriesPostLink(entries[i-1])
+		}
+		if i < len(entries)-1 {
+			navigation.Next = seriesPostLink(entries[i+1])
+		}
+		post.Series = navigation
+		break
+	}
+
+	return nil
+}
+
+func seriesPostLink(entry *seriesDTOs.SeriesEntryDTO) *postDTOs.SeriesPostLinkDTO {
+	return &postDTOs.SeriesPostLinkDTO{ID: entry.PostID, Title: entry.Title, Slug: entry.Slug}
+}
+
+// getOwnedSeries loads a series the user may manage: their own, or any series for admins
+func (s *SeriesService) getOwnedSeries(ctx context.Context, id int, userID uuid.UUID, role entities.Role) (*seriesDTOs.SeriesDTO, error) {
+	series, err := s.seriesRepo.GetSeriesByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if series.UserID != userID.String() && !role.IsAdmin() {
+		return nil, appErrors.New(appErrors.CodeForbidden, "You can only manage your own series")
+	}
+
+	return series, nil
+}
+
+// managedSeries reloads a series after a change, with all of its posts
+func (s *SeriesService) managedSeries(ctx context.Context, id int) (*seriesDTOs.SeriesDTO, error) {
+	series, err := s.seriesRepo.GetSeriesByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Posts, err = s.seriesRepo.GetSeriesEntries(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/dtos/taxonomyDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/i18n"
+	"app05/pkg/appErrors"
+	"app05/pkg/utils"
+	"context"
+	"github.com/google/uuid"
+	"strings"
+)
+
+const maxTagSuggestions = 10
+
+type TaxonomyService struct {
+	taxonomyRepo repositories.TaxonomyRepository
+	postRepo     repositories.PostRepository
+	renderer     *ContentRenderer
+	engagement   *EngagementService
+	authors      *PostAuthorService
+	related      *RelatedPostService
+	locales      *i18n.Locales
+	logger       contracts.Logger
+}
+
+func NewTaxonomyService(
+	taxonomyRepo repositories.TaxonomyRepository,
+	postRepo repositories.PostRepository,
+	renderer *ContentRenderer,
+	engagement *EngagementService,
+	authors *PostAuthorService,
+	related *RelatedPostService,
+	locales *i18n.Locales,
+	logger contracts.Logger,
+) *TaxonomyService {
+	return &TaxonomyService{
+		taxonomyRepo: taxonomyRepo,
+		postRepo:     postRepo,
+		renderer:     renderer,
+		engagement:   engagement,
+		authors:      authors,
+		related:      related,
+		locales:      locales,
+		logger:       logger,
+	}
+}
+
+// GetCategoryTree returns all categories nested under their parents
+func (s *TaxonomyService) GetCategoryTree(ctx context.Context) ([]*taxonomyDTOs.CategoryDTO, error) {
+	categories, err := s.taxonomyRepo.GetAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*taxonomyDTOs.CategoryDTO, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	roots := []*taxonomyDTOs.CategoryDTO{}
+	for _, category := range categories {
+		if category.ParentID != nil {
+			if parent, ok := byID[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, category)
+				continue
+			}
+		}
+		roots = append(roots, category)
+	}
+
+	return roots, nil
+}
+
+func (s *TaxonomyService) CreateCategory(ctx context.Context, input taxonomyDTOs.CreateCategoryRequest) (*taxonomyDTOs.CategoryDTO, error) {
+	if input.ParentID != nil {
+		if _, err := s.taxonomyRepo.GetCategoryByID(ctx, *input.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	slug, err := uniqueSlug(ctx, strings.TrimSpace(input.Name), s.taxonomyRepo.CategorySlugExists)
+	if err != nil {
+		return nil, err
+	}
+
+	category := &taxonomyDTOs.CategoryDTO{
+		ParentID:    input.ParentID,
+		Name:        strings.TrimSpace(input.Name),
+		Slug:        slug,
+		Description: input.Description,
+	}
+
+	if err := s.taxonomyRepo.CreateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *TaxonomyService) UpdateCategory(ctx context.Context, id int, input taxonomyDTOs.UpdateCategoryRequest) (*taxonomyDTOs.CategoryDTO, error) {
+	category, err := s.taxonomyRepo.GetCategoryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ParentID != nil {
+		// A category cannot be moved under itself or any of its descendants
+		cyclic, err := s.taxonomyRepo.IsCategoryDescendant(ctx, id, *input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if cyclic {
+			return nil, appErrors.New(appErrors.CodeBadRequest, "a category cannot be nested under itself or its descendants")
+		}
+		if _, err := s.taxonomyRepo.GetCategoryByID(ctx, *input.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name != category.Name {
+		category.Slug, err = uniqueSlug(ctx, name, s.taxonomyRepo.CategorySlugExists)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	category.Name = name
+	category.ParentID = input.ParentID
+	category.Description = input.Description
+
+	if err := s.taxonomyRepo.UpdateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *TaxonomyService) DeleteCategory(ctx context.Context, id int) error {
+	return s.taxonomyRepo.DeleteCategory(ctx, id)
+}
+
+// GetTags returns a page of tags ordered by popularity
+func (s *TaxonomyService) GetTags(ctx context.Context, page, perPage int) ([]*taxonomyDTOs.TagDTO, int, error) {
+	return s.taxonomyRepo.GetAllTags(ctx, perPage, utils.Offset(page, perPage))
+}
+
+// AutocompleteTags suggests existing tags starting with the given prefix
+func (s *TaxonomyService) AutocompleteTags(ctx context.Context, prefix string) ([]*taxonomyDTOs.TagDTO, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return []*taxonomyDTOs.TagDTO{}, nil
+	}
+	return s.taxonomyRepo.SearchTagsByPrefix(ctx, prefix, maxTagSuggestions)
+}
+
+// GetPostsByTag returns a page of listed posts carrying the tag. A non-empty locale
+// keeps one post per article, in that locale or else the default locale.
+func (s *TaxonomyService) GetPostsByTag(ctx context.Context, slug, locale string, userID uuid.UUID, role entities.Role, page, perPage int) ([]*postDTOs.PostDTO, int, error) {
+	if _, err := s.taxonomyRepo.GetTagBySlug(ctx, slug); err != nil {
+		return nil, 0, err
+	}
+
+	posts, total, err := s.postRepo.GetPublishedPosts(ctx, postDTOs.PublishedPostFilter{
+		TagSlug:        slug,
+		Locale:         locale,
+		FallbackLocale: s.locales.Default(),
+		Limit:          perPage,
+		Offset:         utils.Offset(page, perPage),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.renderer.RenderPosts(ctx, posts); err != nil {
+		return nil, 0, err
+	}
+	if err := s.engagement.AttachReactionCounts(ctx, posts); err != nil {
+		return nil, 0, err
+	}
+	restrictPosts(posts, userID, role)
+
+	return posts, total, nil
+}
+
+// GetPostsByCategory returns a page of listed posts in the category or any of its subcategories.
+// A non-empty locale keeps one post per article, in that locale or else the default locale.
+func (s *TaxonomyService) GetPostsByCategory(ctx context.Context, slug, locale string, userID uuid.UUID, role entities.Role, page, perPage int) ([]*postDTOs.PostDTO, int, error) {
+	if _, err := s.taxonomyRepo.GetCategoryBySlug(ctx, slug); err != nil {
+		return nil, 0, err
+	}
+
+	posts, total, err := s.postRepo.GetPublishedPosts(ctx, postDTOs.PublishedPostFilter{
+		CategorySlug:   slug,
+		Locale:         locale,
+		FallbackLocale: s.locales.Default(),
+		Limit:          perPage,
+		Offset:         utils.Offset(page, perPage),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.renderer.RenderPosts(ctx, posts); err != nil {
+		return nil, 0, err
+	}
+	if err := s.engagement.AttachReactionCounts(ctx, posts); err != nil {
+		return nil, 0, err
+	}
+	restrictPosts(posts, userID, role)
+
+	return posts, total, nil
+}
+
+// SetPostCategories replaces the categories assigned to a post
+func (s *TaxonomyService) SetPostCategories(ctx context.Context, postID int, userID uuid.UUID, role entities.Role, categoryIDs []int) (*postDTOs.PostDTO, error) {
+	if _, err := s.postRepo.GetPostByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	if err := s.authors.Authorize(ctx, postID, userID, role, entities.PostAuthorRole.CanEdit, "edit"); err != nil {
+		return nil, err
+	}
+
+	if err := s.taxonomyRepo.SetPostCategories(ctx, postID, categoryIDs); err != nil {
+		return nil, err
+	}
+
+	return s.getRenderedPost(ctx, postID)
+}
+
+// SetPostTags replaces the tags assigned to a post, creating tags that don't exist yet
+func (s *TaxonomyService) SetPostTags(ctx context.Context, postID int, userID uuid.UUID, role entities.Role, names []string) (*postDTOs.PostDTO, error) {
+	if _, err := s.postRepo.GetPostByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	if err := s.authors.Authorize(ctx, postID, userID, role, entities.PostAuthorRole.CanEdit, "edit"); err != nil {
+		return nil, err
+	}
+
+	tagIDs := make([]int, 0, len(names))
+	for _, name := range names {
+		tag, err := findOrCreateTag(ctx, s.taxonomyRepo, name)
+		if err != nil {
+			return nil, err
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	if err := s.taxonomyRepo.SetPostTags(ctx, postID, tagIDs); err != nil {
+		return nil, err
+	}
+	// Shared tags are a related post signal
+	s.related.Invalidate(ctx, postID)
+
+	return s.getRenderedPost(ctx, postID)
+}
+
+func (s *TaxonomyService) getRenderedPost(ctx context.Context, postID int) (*postDTOs.PostDTO, error) {
+	post, err := s.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.renderer.RenderPost(ctx, post); err != nil {
+		return nil, err
+	}
+	if err := s.engagement.AttachReactionCounts(ctx, []*postDTOs.PostDTO{post}); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// findOrCreateTag returns the tag with the given name, creating it when it doesn't exist yet
+func findOrCreateTag(ctx context.Context, taxonomyRepo repositories.TaxonomyRepository, name string) (*taxonomyDTOs.TagDTO, error) {
+	// Collapse whitespace so "Go  Lang" and "Go Lang" resolve to the same tag
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return nil, appErrors.New(appErrors.CodeBadRequest, "tag names cannot be blank")
+	}
+
+	tag, err := taxonomyRepo.GetTagByName(ctx, name)
+	if err == nil {
+		return tag, nil
+	}
+	if appErr, ok := err.(*appErrors.AppError); !ok || appErr.Code != appErrors.CodeNotFound {
+		return nil, err
+	}
+
+	slug, err := uniqueSlug(ctx, name, taxonomyRepo.TagSlugExists)
+	if err != nil {
+		return nil, err
+	}
+
+	tag = &taxonomyDTOs.TagDTO{Name: name, Slug: slug}
+	if err := taxonomyRepo.CreateTag(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// uniqueSlug generates a slug for name that is not yet taken according to exists
+func uniqueSlug(ctx context.Context, name string, exists func(context.Context, string) (bool, error)) (string, error) {
+	var lookupErr error
+	slug := utils.GenerateUniqueSlug(name, func(candidate string) bool {
+		taken, err := exists(ctx, candidate)
+		if err != nil {
+			// Stop probing, the error is reported below
+			lookupErr = err
+			return false
+		}
+		return taken
+	})
+	if lookupErr != nil {
+		return "", lookupErr
+	}
+	if slug == "" {
+		return "", appErrors.New(appErrors.CodeBadRequest, "name must contain at least one letter or digit")
+	}
+	return slug, nil
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/cache"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+// TrendingTracker adds post activity to the trending leaderboards as it happens.
+// Failures are logged only; the nightly rebuild recovers anything missed.
+type TrendingTracker struct {
+	store   *cache.TrendingStore
+	weights postDTOs.TrendingWeights
+	logger  contracts.Logger
+}
+
+func NewTrendingTracker(store *cache.TrendingStore, weights postDTOs.TrendingWeights, logger contracts.Logger) *TrendingTracker {
+	return &TrendingTracker{
+		store:   store,
+		weights: weights,
+		logger:  logger,
+	}
+}
+
+func (t *TrendingTracker) RecordView(ctx context.Context, post *postDTOs.PostDTO) {
+	t.record(ctx, post, t.weights.View, time.Now())
+}
+
+func (t *TrendingTracker) RecordReaction(ctx context.Context, post *postDTOs.PostDTO) {
+	t.record(ctx, post, t.weights.Reaction, time.Now())
+}
+
+func (t *TrendingTracker) RecordComment(ctx context.Context, post *postDTOs.PostDTO) {
+	t.record(ctx, post, t.weights.Comment, time.Now())
+}
+
+// RemoveComment takes back a comment that is no longer approved, dated when it was posted
+// as the rebuild dates it
+func (t *TrendingTracker) RemoveComment(ctx context.Context, post *postDTOs.PostDTO, postedAt time.Time) {
+	t.record(ctx, post, -t.weights.Comment, postedAt)
+}
+
+func (t *TrendingTracker) record(ctx context.Context, post *postDTOs.PostDTO, weight float64, at time.Time) {
+	// Private posts are never listed
+	if entities.PostVisibility(post.Visibility) == entities.PostVisibilityPrivate {
+		return
+	}
+
+	categoryIDs := make([]int, len(post.Categories))
+	for i, category := range post.Categories {
+		categoryIDs[i] = category.ID
+	}
+
+	if err := t.store.Add(ctx, post.ID, categoryIDs, weight, at); err != nil {
+		t.logger.Warn("Failed to record trending activity", "postID", post.ID, "error", err)
+	}
+}
+
+// TrendingService ranks posts by their recent views, reactions and comments, each
+// counting for less the older it is
+type TrendingService struct {
+	postRepo     repositories.PostRepository
+	taxonomyRepo repositories.TaxonomyRepository
+	trendingRepo repositories.TrendingRepository
+	renderer     *ContentRenderer
+	engagement   *EngagementService
+	store        *cache.TrendingStore
+	views        *cache.ViewCounter
+	weights      postDTOs.TrendingWeights
+	window       time.Duration
+	halfLife     time.Duration
+	logger       contracts.Logger
+}
+
+func NewTrendingService(
+	postRepo repositories.PostRepository,
+	taxonomyRepo repositories.TaxonomyRepository,
+	trendingRepo repositories.TrendingRepository,
+	renderer *ContentRenderer,
+	engagement *EngagementService,
+	store *cache.TrendingStore,
+	views *cache.ViewCounter,
+	weights postDTOs.TrendingWeights,
+	window, halfLife time.Duration,
+	logger contracts.Logger,
+) *TrendingService {
+	return &TrendingService{
+		postRepo:     postRepo,
+		taxonomyRepo: taxonomyRepo,
+		trendingRepo: trendingRepo,
+		renderer:     renderer,
+		engagement:   engagement,
+		store:        store,
+		views:        views,
+		weights:      weights,
+		window:       window,
+		halfLife:     halfLife,
+		logger:       logger,
+	}
+}
+
+// GetTrendingPosts returns the top trending listed posts, optionally only those in a
+// category or its subcategories, withholding content the caller may not read
+func (s *TrendingService) GetTrendingPosts(ctx context.Context, categorySlug string, userID uuid.UUID, role entities.Role, limit int) ([]*postDTOs.PostDTO, error) {
+	var categoryIDs []int
+	if categorySlug != "" {
+		var err error
+		if categoryIDs, err = s.categoryTree(ctx, categorySlug); err != nil {
+			return nil, err
+		}
+	}
+
+	// Until the first rebuild the leaderboards are empty
+	built, err := s.store.Built(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !built {
+		if err := s.Rebuild(ctx); err != nil && !errors.Is(err, cache.ErrTrendingRebuildRunning) {
+			return nil, err
+		}
+	}
+
+	// Posts unpublished or made private since they were scored are dropped when loaded
+	ids, err := s.store.Top(ctx, categoryIDs, limit*2)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []*postDTOs.PostDTO{}, nil
+	}
+
+	posts, err := s.postRepo.GetListedPostsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+	if err := s.renderer.RenderPosts(ctx, posts); err != nil {
+		return nil, err
+	}
+	if err := s.engagement.AttachReactionCounts(ctx, posts); err != nil {
+		return nil, err
+	}
+	restrictPosts(posts, userID, role)
+
+	return posts, nil
+}
+
+// categoryTree returns the ids of a category and all its descendants
+func (s *TrendingService) categoryTree(ctx context.Context, slug string) ([]int, error) {
+	root, err := s.taxonomyRepo.GetCategoryBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.taxonomyRepo.GetAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[int][]int)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+
+	ids := []int{root.ID}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids, nil
+}
+
+// Rebuild recomputes the leaderboards from the activity within the trending window
+// and drops daily view counts that have left it. Activity recorded while the scores are
+// read is replayed onto the new leaderboards. Views recorded or flushed in the moment the
+// unflushed views are read may be counted twice, and comments unapproved while the scores
+// are read stay counted; both are corrected by the next rebuild.
+func (s *TrendingService) Rebuild(ctx context.Context) error {
+	token, err := s.store.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := s.store.Unlock(context.WithoutCancel(ctx), token); err != nil {
+			s.logger.Warn("Failed to release trending rebuild lock", "error", err)
+		}
+	}()
+
+	// Activity is replayed from the epoch on, which is stored in whole seconds
+	now := time.Now().Truncate(time.Second)
+	since := now.Add(-s.window)
+
+	pendingViews, err := s.views.AllPendingViews(ctx)
+	if err != nil {
+		return err
+	}
+	scores, err := s.trendingRepo.GetTrendingScores(ctx, since, now, pendingViews, s.halfLife, s.weights)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Replace(ctx, token, now, scores); err != nil {
+		return err
+	}
+	if err := s.trendingRepo.PruneDailyViews(ctx, since); err != nil {
+		return err
+	}
+
+	s.logger.Info("Rebuilt trending posts", "posts", len(scores))
+	return nil
+}
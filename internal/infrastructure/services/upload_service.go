@@ -0,0 +1,321 @@
+package services
+
+import (
+	"app05/internal/core/application/contracts"
+	"app05/internal/core/domain/dtos/mediaDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/cache"
+	"app05/pkg/appErrors"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	uploadChunkPrefix = "resumable/"
+	// Chunks without a tracked upload are only removed after this long, so a chunk that
+	// is being recorded is never mistaken for one of an expired upload
+	orphanedChunkAge = time.Hour
+	// How long adding a completed upload to the media library may take before another
+	// request may try again
+	uploadCompletionTimeout = 30 * time.Minute
+	// Most uploads a user may have open at once
+	maxOpenUploads = 5
+)
+
+// UploadService receives large files in chunks that can be resumed after a failed
+// request, as the tus protocol does. Once all chunks are in, the file is added to the
+// media library.
+type UploadService struct {
+	uploads  *cache.UploadStore
+	blobs    contracts.BlobStore
+	media    *MediaService
+	maxSizes map[entities.Role]int64 // largest file per role in bytes, negative for unlimited
+	logger   contracts.Logger
+}
+
+func NewUploadService(
+	uploads *cache.UploadStore,
+	blobs contracts.BlobStore,
+	media *MediaService,
+	maxSizes map[entities.Role]int64,
+	logger contracts.Logger,
+) *UploadService {
+	return &UploadService{
+		uploads:  uploads,
+		blobs:    blobs,
+		media:    media,
+		maxSizes: maxSizes,
+		logger:   logger,
+	}
+}
+
+// MaxSize is the largest file the role may upload, negative for unlimited
+func (s *UploadService) MaxSize(role entities.Role) int64 {
+	maxSize, ok := s.maxSizes[role]
+	if !ok {
+		return 0
+	}
+	return maxSize
+}
+
+// CreateUpload starts an upload of a file of length bytes. The lengths of the user's open
+// uploads count towards their quota until the uploads complete or expire.
+func (s *UploadService) CreateUpload(ctx context.Context, userID uuid.UUID, role entities.Role, length int64, fileName string, input mediaDTOs.CreateMediaRequest) (*mediaDTOs.ResumableUpload, error) {
+	if maxSize := s.MaxSize(role); maxSize >= 0 && length > maxSize {
+		return nil, appErrors.New(appErrors.CodeRequestEntityTooLarge, fmt.Sprintf("file must not exceed %d bytes", maxSize))
+	}
+	if err := s.media.CheckScannable(length); err != nil {
+		return nil, err
+	}
+
+	upload := &mediaDTOs.ResumableUpload{
+		ID:       strings.ReplaceAll(uuid.NewString(), "-", ""),
+		UserID:   userID.String(),
+		Length:   length,
+		FileName: fileName,
+		Media:    input,
+	}
+	if err := s.uploads.Create(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	// The upload is reserved before it is checked, so concurrent requests see each other
+	if err := s.checkReserved(ctx, userID, role); err != nil {
+		if deleteErr := s.uploads.Delete(ctx, upload); deleteErr != nil {
+			s.logger.Warn("Failed to drop refused upload", "upload", upload.ID, "error", deleteErr)
+		}
+		return nil, err
+	}
+	return upload, nil
+}
+
+// GetUpload returns one of the user's uploads
+func (s *UploadService) GetUpload(ctx context.Context, id string, userID uuid.UUID) (*mediaDTOs.ResumableUpload, error) {
+	upload, err := s.uploads.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Other users' uploads are reported as missing, not as forbidden
+	if upload == nil || upload.UserID != userID.String() {
+		return nil, appErrors.New(appErrors.CodeNotFound, "upload not found")
+	}
+	return upload, nil
+}
+
+// WriteChunk appends a chunk received at offset, which must be the upload's current
+// offset. After the last chunk the file is added to the media library.
+func (s *UploadService) WriteChunk(ctx context.Context, id string, userID uuid.UUID, role entities.Role, offset int64, chunk io.Reader) (*mediaDTOs.ResumableUpload, error) {
+	upload, err := s.GetUpload(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Completed() {
+		return upload, nil
+	}
+	if offset != upload.Offset {
+		return nil, appErrors.New(appErrors.CodeConflict, fmt.Sprintf("upload is at offset %d", upload.Offset))
+	}
+
+	if offset < upload.Length {
+		// A chunk beyond the declared length is an error, not a truncation
+		body := &countingReader{reader: io.LimitReader(chunk, upload.Length-offset+1)}
+		key := fmt.Sprintf("%s%s/%020d-%s", uploadChunkPrefix, id, offset, uuid.NewString())
+		if err := s.blobs.Put(ctx, key, body, -1, "application/octet-stream"); err != nil {
+			s.removeChunk(ctx, key)
+			return nil, err
+		}
+		if offset+body.count > upload.Length {
+			s.removeChunk(ctx, key)
+			return nil, appErrors.New(appErrors.CodeRequestEntityTooLarge, "chunk exceeds the upload length")
+		}
+		if body.count == 0 {
+			s.removeChunk(ctx, key)
+			return upload, nil
+		}
+
+		advanced, err := s.uploads.Advance(ctx, upload, key, body.count)
+		if err != nil || !advanced {
+			s.removeChunk(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+			return nil, appErrors.New(appErrors.CodeConflict, "upload was changed by another request")
+		}
+	}
+
+	if upload.Offset < upload.Length {
+		return upload, nil
+	}
+	return s.complete(ctx, upload, userID, role)
+}
+
+// DeleteUpload cancels an upload, removing the chunks received so far
+func (s *UploadService) DeleteUpload(ctx context.Context, id string, userID uuid.UUID) error {
+	upload, err := s.GetUpload(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	return s.discard(ctx, upload)
+}
+
+// RemoveOrphanedChunks deletes the chunks of uploads that expired before they were
+// completed and returns how many it deleted
+func (s *UploadService) RemoveOrphanedChunks(ctx context.Context) (int, error) {
+	chunks, err := s.blobs.List(ctx, uploadChunkPrefix)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	tracked := map[string]bool{}
+	for _, chunk := range chunks {
+		if time.Since(chunk.ModTime) < orphanedChunkAge {
+			continue
+		}
+		id := path.Base(path.Dir(chunk.Key))
+		exists, checked := tracked[id]
+		if !checked {
+			if exists, err = s.uploads.Exists(ctx, id); err != nil {
+				return removed, err
+			}
+			tracked[id] = exists
+		}
+		if exists {
+			continue
+		}
+		if err := s.blobs.Delete(ctx, chunk.Key); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// complete adds the uploaded file to the media library. Files the library rejects end
+// the upload; after other failures the client may complete it by resuming.
+func (s *UploadService) complete(ctx context.Context, upload *mediaDTOs.ResumableUpload, userID uuid.UUID, role entities.Role) (*mediaDTOs.ResumableUpload, error) {
+	claimed, err := s.uploads.ClaimCompletion(ctx, upload.ID, uploadCompletionTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, appErrors.New(appErrors.CodeConflict, "upload is being completed by another request")
+	}
+
+	file := &chunkReader{ctx: ctx, blobs: s.blobs, keys: upload.Chunks}
+	media, err := s.media.AddMedia(ctx, userID, role, file, upload.Length, upload.FileName, upload.Media)
+	file.Close()
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			if discardErr := s.discard(ctx, upload); discardErr != nil {
+				s.logger.Warn("Failed to discard rejected upload", "upload", upload.ID, "error", discardErr)
+			}
+			return nil, err
+		}
+		if releaseErr := s.uploads.ReleaseCompletion(ctx, upload.ID); releaseErr != nil {
+			s.logger.Error("Failed to release upload completion", "upload", upload.ID, "error", releaseErr)
+		}
+		return nil, err
+	}
+
+	if err := s.uploads.Complete(ctx, upload, media.ID); err != nil {
+		s.logger.Error("Failed to record completed upload", "upload", upload.ID, "media", media.ID, "error", err)
+	}
+	for _, key := range upload.Chunks {
+		s.removeChunk(ctx, key)
+	}
+	upload.MediaID = media.ID
+	upload.Chunks = nil
+	return upload, nil
+}
+
+// checkReserved checks the user's open uploads against the upload limit and, together with
+// their stored media, against their quota
+func (s *UploadService) checkReserved(ctx context.Context, userID uuid.UUID, role entities.Role) error {
+	count, reserved, err := s.uploads.Reserved(ctx, userID.String())
+	if err != nil {
+		return err
+	}
+	if count > maxOpenUploads {
+		return appErrors.New(appErrors.CodeTooManyRequests,
+			fmt.Sprintf("You can have at most %d unfinished uploads", maxOpenUploads))
+	}
+	return s.media.CheckQuota(ctx, userID, role, reserved)
+}
+
+func (s *UploadService) discard(ctx context.Context, upload *mediaDTOs.ResumableUpload) error {
+	if err := s.uploads.Delete(ctx, upload); err != nil {
+		return err
+	}
+	for _, key := range upload.Chunks {
+		s.removeChunk(ctx, key)
+	}
+	return nil
+}
+
+// removeChunk deletes a stored chunk. Leftover chunks are removed by RemoveOrphanedChunks.
+func (s *UploadService) removeChunk(ctx context.Context, key string) {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		s.logger.Warn("Failed to remove upload chunk", "key", key, "error", err)
+	}
+}
+
+// chunkReader reads stored chunks one after another as a single file
+type chunkReader struct {
+	ctx     context.Context
+	blobs   contracts.BlobStore
+	keys    []string
+	current io.ReadCloser
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.keys) == 0 {
+				return 0, io.EOF
+			}
+			body, _, err := r.blobs.Get(r.ctx, r.keys[0])
+			if err != nil {
+				return 0, err
+			}
+			r.current, r.keys = body, r.keys[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	return r.current.Close()
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
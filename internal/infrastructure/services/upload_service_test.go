@@ -0,0 +1,254 @@
+package services
+
+import (
+	"app05/internal/core/domain/dtos/mediaDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/blob"
+	"app05/internal/infrastructure/cache"
+	"app05/pkg/appErrors"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+func (nopLogger) Fatal(string, ...interface{}) {}
+func (nopLogger) Panic(string, ...interface{}) {}
+func (nopLogger) Sync() error                  { return nil }
+
+// fakeRedis serves the read commands of a Redis server over the RESP2 protocol from
+// hashes and lists set up by the test. Other commands fail.
+type fakeRedis struct {
+	mu     sync.Mutex
+	hashes map[string]map[string]string
+	lists  map[string][]string
+}
+
+func newFakeRedis(t *testing.T) (*fakeRedis, *redis.Client) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeRedis{hashes: map[string]map[string]string{}, lists: map[string][]string{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String(), DisableIdentity: true})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		command, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		f.mu.Lock()
+		var reply string
+		switch strings.ToUpper(command[0]) {
+		case "HGETALL":
+			var values []string
+			for field, value := range f.hashes[command[1]] {
+				values = append(values, field, value)
+			}
+			reply = respArray(values)
+		case "LRANGE":
+			reply = respArray(f.lists[command[1]])
+		default:
+			reply = "-ERR unknown command '" + command[0] + "'\r\n"
+		}
+		f.mu.Unlock()
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+
+	command := make([]string, count)
+	for i := range command {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, err
+		}
+		value := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, value); err != nil {
+			return nil, err
+		}
+		command[i] = string(value[:size])
+	}
+	return command, nil
+}
+
+func respArray(values []string) string {
+	var reply strings.Builder
+	fmt.Fprintf(&reply, "*%d\r\n", len(values))
+	for _, value := range values {
+		fmt.Fprintf(&reply, "$%d\r\n%s\r\n", len(value), value)
+	}
+	return reply.String()
+}
+
+// addUpload stores an upload the way cache.UploadStore does
+func (f *fakeRedis) addUpload(t *testing.T, upload *mediaDTOs.ResumableUpload) {
+	t.Helper()
+	file, err := json.Marshal(map[string]interface{}{
+		"user_id":   upload.UserID,
+		"length":    upload.Length,
+		"file_name": upload.FileName,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.hashes["upload:"+upload.ID] = map[string]string{
+		"file":       string(file),
+		"offset":     strconv.FormatInt(upload.Offset, 10),
+		"expires_at": strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10),
+		"media_id":   strconv.Itoa(upload.MediaID),
+	}
+	f.lists["upload:"+upload.ID+":chunks"] = upload.Chunks
+}
+
+func assertAppError(t *testing.T, err error, code appErrors.ErrorCode) {
+	t.Helper()
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code.Code != code.Code {
+		t.Fatalf("expected a %s error, got %v", code.Code, err)
+	}
+}
+
+func TestUploadService_CreateUploadLength(t *testing.T) {
+	service := NewUploadService(nil, nil, nil, map[entities.Role]int64{
+		entities.RoleAdmin:      -1,
+		entities.RoleInstructor: 100,
+		entities.RoleStudent:    10,
+	}, nopLogger{})
+
+	tests := []struct {
+		name    string
+		role    entities.Role
+		maxSize int64
+		length  int64
+	}{
+		{name: "over the role's limit", role: entities.RoleStudent, maxSize: 10, length: 11},
+		{name: "over another role's limit", role: entities.RoleInstructor, maxSize: 100, length: 101},
+		{name: "role without a limit configured", role: entities.Role("guest"), maxSize: 0, length: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := service.MaxSize(tt.role); got != tt.maxSize {
+				t.Fatalf("MaxSize(%q) = %d, want %d", tt.role, got, tt.maxSize)
+			}
+			// Refused before anything is stored
+			_, err := service.CreateUpload(context.Background(), uuid.New(), tt.role, tt.length, "file.bin", mediaDTOs.CreateMediaRequest{})
+			assertAppError(t, err, appErrors.CodeRequestEntityTooLarge)
+		})
+	}
+
+	if got := service.MaxSize(entities.RoleAdmin); got != -1 {
+		t.Fatalf("MaxSize(admin) = %d, want unlimited", got)
+	}
+}
+
+func TestUploadService_WriteChunkChecks(t *testing.T) {
+	fake, client := newFakeRedis(t)
+	blobs := blob.NewLocalStore(t.TempDir(), "http://localhost/blobs", "secret")
+	service := NewUploadService(cache.NewUploadStore(client, time.Hour), blobs, nil, nil, nopLogger{})
+	ctx := context.Background()
+
+	owner, other := uuid.New(), uuid.New()
+	fake.addUpload(t, &mediaDTOs.ResumableUpload{
+		ID: "partial", UserID: owner.String(), Length: 10, Offset: 4, Chunks: []string{"resumable/partial/1"},
+	})
+	fake.addUpload(t, &mediaDTOs.ResumableUpload{
+		ID: "done", UserID: owner.String(), Length: 10, Offset: 10, MediaID: 7,
+	})
+
+	tests := []struct {
+		name       string
+		id         string
+		userID     uuid.UUID
+		offset     int64
+		chunk      string
+		wantErr    appErrors.ErrorCode
+		wantOffset int64
+	}{
+		{name: "unknown upload", id: "missing", userID: owner, offset: 0, chunk: "x", wantErr: appErrors.CodeNotFound},
+		{name: "another user's upload", id: "partial", userID: other, offset: 4, chunk: "x", wantErr: appErrors.CodeNotFound},
+		{name: "offset behind the upload", id: "partial", userID: owner, offset: 0, chunk: "x", wantErr: appErrors.CodeConflict},
+		{name: "offset ahead of the upload", id: "partial", userID: owner, offset: 6, chunk: "x", wantErr: appErrors.CodeConflict},
+		{name: "chunk past the upload length", id: "partial", userID: owner, offset: 4, chunk: "1234567", wantErr: appErrors.CodeRequestEntityTooLarge},
+		{name: "empty chunk", id: "partial", userID: owner, offset: 4, chunk: "", wantOffset: 4},
+		{name: "completed upload", id: "done", userID: owner, offset: 0, chunk: "x", wantOffset: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upload, err := service.WriteChunk(ctx, tt.id, tt.userID, entities.RoleStudent, tt.offset, strings.NewReader(tt.chunk))
+			if tt.wantErr.Code != "" {
+				assertAppError(t, err, tt.wantErr)
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if upload.Offset != tt.wantOffset {
+				t.Fatalf("Offset = %d, want %d", upload.Offset, tt.wantOffset)
+			}
+
+			// Refused and empty chunks are not kept
+			chunks, err := blobs.List(ctx, uploadChunkPrefix)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(chunks) != 0 {
+				t.Fatalf("%d chunks left in the blob store", len(chunks))
+			}
+		})
+	}
+}
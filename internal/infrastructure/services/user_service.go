@@ -4,19 +4,37 @@ import (
 	"app05/internal/core/application/contracts"
 	"app05/internal/core/domain/entities"
 	"app05/internal/core/domain/repositories"
+	"app05/internal/infrastructure/blob"
+	"app05/internal/infrastructure/imaging"
+	"app05/pkg/appErrors"
+	"bytes"
 	"context"
+	"errors"
 	"github.com/google/uuid"
+	"io"
+	"strings"
+)
+
+const (
+	avatarPrefix       = "profile-pictures/"
+	maxAvatarDimension = 4096
 )
 
 type UserService struct {
-	userRepo repositories.UserRepository
-	logger   contracts.Logger
+	userRepo  repositories.UserRepository
+	blobs     contracts.BlobStore
+	variants  *ImageVariantService
+	publicURL string // URL public blobs are served under
+	logger    contracts.Logger
 }
 
-func NewUserService(userRepo repositories.UserRepository, logger contracts.Logger) *UserService {
+func NewUserService(userRepo repositories.UserRepository, blobs contracts.BlobStore, variants *ImageVariantService, publicURL string, logger contracts.Logger) *UserService {
 	return &UserService{
-		userRepo: userRepo,
-		logger:   logger,
+		userRepo:  userRepo,
+		blobs:     blobs,
+		variants:  variants,
+		publicURL: publicURL,
+		logger:    logger,
 	}
 }
 
@@ -28,10 +46,103 @@ func (s *UserService) GetUserByID(ctx context.Context, id uuid.UUID) (*entities.
 	return s.userRepo.GetUserByID(ctx, id)
 }
 
+// GetUserProfile returns a user's profile with the resized copies of their profile picture
 func (s *UserService) GetUserProfile(ctx context.Context, id uuid.UUID) (*entities.User, error) {
-	return s.userRepo.GetUserProfile(ctx, id)
+	profile, err := s.userRepo.GetUserProfile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if profile.ProfilePictureURL == nil {
+		return profile, nil
+	}
+
+	key := s.avatarKey(*profile.ProfilePictureURL)
+	if key == "" {
+		return profile, nil
+	}
+	variantsByKey, err := s.variants.Variants(ctx, []string{key})
+	if err != nil {
+		return nil, err
+	}
+	profile.ProfilePictureVariants = variantsByKey[key]
+	return profile, nil
 }
 
 func (s *UserService) VerifyEmail(ctx context.Context, userID uuid.UUID, code string) error {
 	return s.userRepo.VerifyEmail(ctx, userID, code)
 }
+
+// UpdateAvatar stores an uploaded profile picture, re-encoded without metadata, and
+// deletes the user's previous one
+func (s *UserService) UpdateAvatar(ctx context.Context, userID uuid.UUID, file io.Reader) (*entities.User, error) {
+	img, err := imaging.Sanitize(file, maxAvatarDimension)
+	if err != nil {
+		if errors.Is(err, imaging.ErrUnsupportedFormat) || errors.Is(err, imaging.ErrTooLarge) {
+			return nil, appErrors.New(appErrors.CodeBadRequest, err.Error())
+		}
+		return nil, err
+	}
+
+	profile, err := s.userRepo.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := avatarPrefix + uuid.NewString() + img.Ext
+	if err := s.blobs.Put(ctx, key, bytes.NewReader(img.Data), int64(len(img.Data)), img.ContentType); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateProfilePicture(ctx, userID, false, blob.PublicURL(s.publicURL, key)); err != nil {
+		s.removeAvatar(ctx, key)
+		return nil, err
+	}
+	if err := s.variants.Enqueue(ctx, key); err != nil {
+		s.logger.Error("Failed to queue profile picture variants", "key", key, "error", err)
+	}
+	if profile.ProfilePictureURL != nil {
+		s.removeAvatar(ctx, s.avatarKey(*profile.ProfilePictureURL))
+	}
+
+	return s.GetUserProfile(ctx, userID)
+}
+
+// DeleteAvatar removes the user's profile picture
+func (s *UserService) DeleteAvatar(ctx context.Context, userID uuid.UUID) (*entities.User, error) {
+	profile, err := s.userRepo.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile.ProfilePictureURL == nil {
+		return profile, nil
+	}
+
+	if err := s.userRepo.UpdateProfilePicture(ctx, userID, true, ""); err != nil {
+		return nil, err
+	}
+	s.removeAvatar(ctx, s.avatarKey(*profile.ProfilePictureURL))
+
+	return s.GetUserProfile(ctx, userID)
+}
+
+// avatarKey returns the blob key behind a stored profile picture URL, or "" when the
+// URL points elsewhere (e.g. an external picture)
+func (s *UserService) avatarKey(url string) string {
+	key := blob.KeyFromURL(s.publicURL, url)
+	if !strings.HasPrefix(key, avatarPrefix) {
+		return ""
+	}
+	return key
+}
+
+// removeAvatar deletes a profile picture and its variants. Leftover files are only logged.
+func (s *UserService) removeAvatar(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := s.variants.Delete(ctx, key); err != nil {
+		s.logger.Warn("Failed to remove profile picture variants", "key", key, "error", err)
+	}
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		s.logger.Warn("Failed to remove profile picture", "key", key, "error", err)
+	}
+}
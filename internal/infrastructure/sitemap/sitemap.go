@@ -0,0 +1,92 @@
+// Package sitemap encodes sitemaps and sitemap indexes following the sitemaps.org protocol.
+package sitemap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// MaxURLs is the most URLs a single sitemap may list. Larger sites must be split behind an index.
+const MaxURLs = 50000
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL is a location with an optional last modification time
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// Document is an encoded sitemap along with the validators used for conditional requests
+type Document struct {
+	Body         []byte
+	ETag         string
+	LastModified time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name   `xml:"urlset"`
+	XMLNS   string     `xml:"xmlns,attr"`
+	URLs    []urlEntry `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name   `xml:"sitemapindex"`
+	XMLNS    string     `xml:"xmlns,attr"`
+	Sitemaps []urlEntry `xml:"sitemap"`
+}
+
+type urlEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// EncodeURLSet encodes a sitemap listing the given URLs
+func EncodeURLSet(urls []URL) (*Document, error) {
+	if len(urls) > MaxURLs {
+		return nil, fmt.Errorf("sitemap has %d urls, the limit is %d", len(urls), MaxURLs)
+	}
+	return encode(urlSet{XMLNS: namespace, URLs: entries(urls)}, urls)
+}
+
+// EncodeIndex encodes a sitemap index pointing at the given sitemaps
+func EncodeIndex(sitemaps []URL) (*Document, error) {
+	return encode(sitemapIndex{XMLNS: namespace, Sitemaps: entries(sitemaps)}, sitemaps)
+}
+
+func entries(urls []URL) []urlEntry {
+	result := make([]urlEntry, 0, len(urls))
+	for _, u := range urls {
+		entry := urlEntry{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			entry.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func encode(document interface{}, urls []URL) (*Document, error) {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sitemap: %w", err)
+	}
+	body = append([]byte(xml.Header), body...)
+
+	var lastModified time.Time
+	for _, u := range urls {
+		if u.LastMod.After(lastModified) {
+			lastModified = u.LastMod
+		}
+	}
+
+	sum := sha256.Sum256(body)
+	return &Document{
+		Body:         body,
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: lastModified.UTC().Truncate(time.Second),
+	}, nil
+}
@@ -0,0 +1,10 @@
+-- Weighted full-text search document: title (A) > excerpt (B) > content (C)
+ALTER TABLE posts
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(excerpt, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(content, '')), 'C')
+    ) STORED;
+
+-- GIN index for fast @@ lookups
+CREATE INDEX idx_posts_search_vector ON posts USING GIN (search_vector);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS post_tags;
+DROP TABLE IF EXISTS post_categories;
+DROP TABLE IF EXISTS tags;
+DROP TRIGGER IF EXISTS update_categories_updated_at ON categories;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,48 @@
+-- Hierarchical categories
+CREATE TABLE categories (
+                            id SERIAL PRIMARY KEY,
+                            parent_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+                            name VARCHAR(100) NOT NULL,
+                            slug VARCHAR(120) NOT NULL UNIQUE,
+                            description VARCHAR(500),
+                            created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                            updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                            CONSTRAINT categories_not_own_parent CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+
+CREATE TRIGGER update_categories_updated_at
+    BEFORE UPDATE ON categories
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Free-form tags
+CREATE TABLE tags (
+                      id SERIAL PRIMARY KEY,
+                      name VARCHAR(50) NOT NULL,
+                      slug VARCHAR(60) NOT NULL UNIQUE,
+                      created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Case-insensitive name lookups and prefix matching for autocomplete
+CREATE UNIQUE INDEX idx_tags_name_lower ON tags (lower(name));
+CREATE INDEX idx_tags_name_prefix ON tags (lower(name) varchar_pattern_ops);
+
+-- Many-to-many join tables
+CREATE TABLE post_categories (
+                                 post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+                                 category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+                                 PRIMARY KEY (post_id, category_id)
+);
+
+CREATE INDEX idx_post_categories_category_id ON post_categories(category_id);
+
+CREATE TABLE post_tags (
+                           post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+                           tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+                           PRIMARY KEY (post_id, tag_id)
+);
+
+CREATE INDEX idx_post_tags_tag_id ON post_tags(tag_id);
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS update_posts_updated_at ON posts;
+
+CREATE TRIGGER update_posts_updated_at
+    BEFORE UPDATE ON posts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE posts ALTER COLUMN view_count DROP NOT NULL;
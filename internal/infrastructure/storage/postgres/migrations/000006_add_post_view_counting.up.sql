@@ -0,0 +1,12 @@
+-- View counts are incremented in place, so they must never be NULL
+UPDATE posts SET view_count = 0 WHERE view_count IS NULL;
+ALTER TABLE posts ALTER COLUMN view_count SET NOT NULL;
+
+-- Flushing buffered view counts is not a content edit and must not bump updated_at
+DROP TRIGGER IF EXISTS update_posts_updated_at ON posts;
+
+CREATE TRIGGER update_posts_updated_at
+    BEFORE UPDATE ON posts
+    FOR EACH ROW
+    WHEN (OLD.view_count IS NOT DISTINCT FROM NEW.view_count)
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,32 @@
+CREATE TABLE comments (
+                          id SERIAL PRIMARY KEY,
+                          post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+                          user_id UUID NOT NULL REFERENCES users(id),
+                          parent_id INTEGER REFERENCES comments(id) ON DELETE CASCADE, -- NULL for top-level comments
+                          body TEXT NOT NULL,
+                          status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, approved, rejected, spam
+                          moderated_by UUID REFERENCES users(id),
+                          moderated_at TIMESTAMP WITH TIME ZONE,
+                          edited_at TIMESTAMP WITH TIME ZONE,
+                          deleted_at TIMESTAMP WITH TIME ZONE,
+                          created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                          updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                          CONSTRAINT comments_valid_status CHECK (status IN ('pending', 'approved', 'rejected', 'spam')),
+                          CONSTRAINT comments_not_own_parent CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+-- Indexes for better query performance
+CREATE INDEX idx_comments_post_id_created_at ON comments(post_id, created_at);
+CREATE INDEX idx_comments_parent_id ON comments(parent_id);
+CREATE INDEX idx_comments_user_id ON comments(user_id);
+
+-- Index for the moderation queue
+CREATE INDEX idx_comments_pending ON comments(created_at)
+    WHERE status = 'pending';
+
+-- Trigger to automatically update updated_at timestamp
+CREATE TRIGGER update_comments_updated_at
+    BEFORE UPDATE ON comments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_posts_published_sitemap;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS og_image_url,
+    DROP COLUMN IF EXISTS canonical_url,
+    DROP COLUMN IF EXISTS meta_description,
+    DROP COLUMN IF EXISTS meta_title;
@@ -0,0 +1,10 @@
+-- Optional per-post overrides for search engines and social sharing. When
+-- unset, the title, excerpt and public post URL are used instead.
+ALTER TABLE posts
+    ADD COLUMN meta_title VARCHAR(100),
+    ADD COLUMN meta_description VARCHAR(300),
+    ADD COLUMN canonical_url VARCHAR(2048),
+    ADD COLUMN og_image_url VARCHAR(2048);
+
+-- Sitemaps page through published posts in id order
+CREATE INDEX idx_posts_published_sitemap ON posts(id) WHERE status = 'published' AND slug IS NOT NULL;
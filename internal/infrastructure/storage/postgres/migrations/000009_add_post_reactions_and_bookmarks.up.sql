@@ -0,0 +1,24 @@
+CREATE TABLE post_reactions (
+                                post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+                                user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                reaction VARCHAR(20) NOT NULL, -- like, insightful, helpful, celebrate, curious
+                                created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                                -- A user can give each reaction to a post once
+                                PRIMARY KEY (post_id, user_id, reaction),
+                                CONSTRAINT post_reactions_valid_reaction CHECK (reaction IN ('like', 'insightful', 'helpful', 'celebrate', 'curious'))
+);
+
+CREATE INDEX idx_post_reactions_user_id ON post_reactions(user_id);
+
+CREATE TABLE bookmarks (
+                           user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                           post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+                           created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                           PRIMARY KEY (user_id, post_id)
+);
+
+-- Reading lists are listed newest first
+CREATE INDEX idx_bookmarks_user_id_created_at ON bookmarks(user_id, created_at DESC);
+CREATE INDEX idx_bookmarks_post_id ON bookmarks(post_id);
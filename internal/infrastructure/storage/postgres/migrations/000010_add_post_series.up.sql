@@ -0,0 +1,30 @@
+-- Ordered collections of posts, such as multi-part tutorials
+CREATE TABLE series (
+                        id SERIAL PRIMARY KEY,
+                        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                        title VARCHAR(200) NOT NULL,
+                        slug VARCHAR(220) NOT NULL UNIQUE,
+                        description VARCHAR(1000),
+                        created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                        updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_series_user_id ON series(user_id);
+
+CREATE TRIGGER update_series_updated_at
+    BEFORE UPDATE ON series
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE series_posts (
+                              series_id INTEGER NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+                              post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+                              position INTEGER NOT NULL, -- 1-based order within the series
+
+                              PRIMARY KEY (series_id, post_id),
+                              -- A post belongs to at most one series
+                              CONSTRAINT series_posts_post_id_key UNIQUE (post_id),
+                              -- Checked at commit so positions can be swapped within a transaction
+                              CONSTRAINT series_posts_position_key UNIQUE (series_id, position) DEFERRABLE INITIALLY DEFERRED,
+                              CONSTRAINT series_posts_position_positive CHECK (position > 0)
+);
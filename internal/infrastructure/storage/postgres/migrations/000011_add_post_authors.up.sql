@@ -0,0 +1,17 @@
+-- Authors of a post and what each of them may do. posts.user_id stays the owner.
+CREATE TABLE post_authors (
+                              post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+                              user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                              role VARCHAR(20) NOT NULL, -- owner, editor, contributor
+                              created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                              PRIMARY KEY (post_id, user_id),
+                              CONSTRAINT post_authors_valid_role CHECK (role IN ('owner', 'editor', 'contributor'))
+);
+
+-- Every post has exactly one owner
+CREATE UNIQUE INDEX idx_post_authors_owner ON post_authors(post_id) WHERE role = 'owner';
+CREATE INDEX idx_post_authors_user_id ON post_authors(user_id);
+
+INSERT INTO post_authors (post_id, user_id, role, created_at)
+SELECT id, user_id, 'owner', created_at FROM posts;
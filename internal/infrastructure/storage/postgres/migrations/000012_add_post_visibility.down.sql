@@ -0,0 +1,4 @@
+ALTER TABLE posts
+    DROP CONSTRAINT IF EXISTS posts_valid_visibility,
+    DROP COLUMN IF EXISTS visible_roles,
+    DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,6 @@
+-- Who may read a post's full content. Everyone else gets the excerpt only,
+-- except for private posts which are hidden from everyone but their authors.
+ALTER TABLE posts
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public', -- public, registered, roles, private
+    ADD COLUMN visible_roles TEXT[] NOT NULL DEFAULT '{}',        -- roles allowed to read 'roles' posts
+    ADD CONSTRAINT posts_valid_visibility CHECK (visibility IN ('public', 'registered', 'roles', 'private'));
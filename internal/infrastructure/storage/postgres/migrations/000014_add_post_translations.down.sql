@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_posts_locale_published;
+
+ALTER TABLE posts
+    DROP CONSTRAINT IF EXISTS posts_translation_locale_key,
+    DROP COLUMN IF EXISTS translation_group_id,
+    DROP COLUMN IF EXISTS locale;
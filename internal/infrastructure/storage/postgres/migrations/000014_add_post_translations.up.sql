@@ -0,0 +1,21 @@
+-- Posts are written in one locale (a BCP 47 tag). Translations of the same
+-- article share a translation group, which holds at most one post per locale.
+-- Existing posts each start in their own group.
+--
+-- Existing posts are assigned the locale in the app.default_locale setting, which
+-- should match SITE_LANGUAGE. Set it before migrating when the site is not in English:
+--     ALTER DATABASE <name> SET app.default_locale = 'de';
+-- Filling the column through its default leaves updated_at untouched. New posts always
+-- name their locale, so the default is dropped afterwards.
+ALTER TABLE posts
+    ADD COLUMN locale VARCHAR(35) NOT NULL
+        DEFAULT COALESCE(NULLIF(current_setting('app.default_locale', true), ''), 'en'),
+    ADD COLUMN translation_group_id UUID NOT NULL DEFAULT uuid_generate_v4();
+
+ALTER TABLE posts ALTER COLUMN locale DROP DEFAULT;
+
+ALTER TABLE posts
+    ADD CONSTRAINT posts_translation_locale_key UNIQUE (translation_group_id, locale);
+
+-- Listings are filtered by locale
+CREATE INDEX idx_posts_locale_published ON posts(locale, published_at DESC) WHERE status = 'published';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_post_reactions_created_at;
+DROP TABLE IF EXISTS post_daily_views;
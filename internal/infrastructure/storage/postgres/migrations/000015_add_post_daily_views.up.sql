@@ -0,0 +1,11 @@
+-- Views per post and day, kept for the trending window. Trending leaderboards
+-- are rebuilt from these together with reactions and approved comments.
+CREATE TABLE post_daily_views (
+                                    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+                                    day DATE NOT NULL,
+                                    views BIGINT NOT NULL DEFAULT 0,
+                                    PRIMARY KEY (post_id, day)
+);
+
+CREATE INDEX idx_post_daily_views_day ON post_daily_views(day);
+CREATE INDEX idx_post_reactions_created_at ON post_reactions(created_at);
@@ -0,0 +1,40 @@
+-- Files uploaded by authors for use in posts
+CREATE TABLE media (
+                       id SERIAL PRIMARY KEY,
+                       user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                       storage_key VARCHAR(512) NOT NULL UNIQUE, -- key in the blob store
+                       file_name VARCHAR(255) NOT NULL,          -- name of the uploaded file
+                       content_type VARCHAR(100) NOT NULL,
+                       size_bytes BIGINT NOT NULL,
+                       alt_text VARCHAR(500),
+                       created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                       updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                       CONSTRAINT media_size_bytes_non_negative CHECK (size_bytes >= 0)
+);
+
+CREATE INDEX idx_media_user_created ON media(user_id, created_at DESC);
+
+CREATE TRIGGER update_media_updated_at
+    BEFORE UPDATE ON media
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE media_tags (
+                            media_id INTEGER NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+                            tag VARCHAR(50) NOT NULL,
+
+                            PRIMARY KEY (media_id, tag)
+);
+
+CREATE INDEX idx_media_tags_tag ON media_tags(tag);
+
+-- Media referenced from a post's content. Media in use cannot be deleted.
+CREATE TABLE post_media (
+                            post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+                            media_id INTEGER NOT NULL REFERENCES media(id) ON DELETE RESTRICT,
+
+                            PRIMARY KEY (post_id, media_id)
+);
+
+CREATE INDEX idx_post_media_media_id ON post_media(media_id);
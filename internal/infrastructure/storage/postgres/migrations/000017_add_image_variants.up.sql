@@ -0,0 +1,19 @@
+-- Resized copies of uploaded images, generated in the background after upload
+CREATE TABLE image_variant_sets (
+                                    source_key VARCHAR(512) PRIMARY KEY, -- blob key of the original image
+                                    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+                                    attempts INTEGER NOT NULL DEFAULT 0,
+                                    last_error TEXT,
+                                    variants JSONB NOT NULL DEFAULT '[]',
+                                    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                                    CONSTRAINT image_variant_sets_status_check CHECK (status IN ('pending', 'ready', 'failed'))
+);
+
+CREATE INDEX idx_image_variant_sets_pending ON image_variant_sets(updated_at) WHERE status = 'pending';
+
+CREATE TRIGGER update_image_variant_sets_updated_at
+    BEFORE UPDATE ON image_variant_sets
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
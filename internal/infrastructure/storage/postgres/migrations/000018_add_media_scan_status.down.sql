@@ -0,0 +1,15 @@
+DROP TRIGGER IF EXISTS update_media_updated_at ON media;
+
+CREATE TRIGGER update_media_updated_at
+    BEFORE UPDATE ON media
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP INDEX IF EXISTS idx_media_scan_pending;
+
+ALTER TABLE media
+    DROP CONSTRAINT IF EXISTS media_scan_status_check,
+    DROP COLUMN IF EXISTS scanned_at,
+    DROP COLUMN IF EXISTS scan_claimed_at,
+    DROP COLUMN IF EXISTS scan_signature,
+    DROP COLUMN IF EXISTS scan_status;
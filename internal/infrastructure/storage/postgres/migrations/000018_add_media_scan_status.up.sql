@@ -0,0 +1,20 @@
+-- Malware scan state of media. Files are kept in quarantine until scanned clean.
+-- Existing files are scanned too, so they start out pending.
+ALTER TABLE media
+    ADD COLUMN scan_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN scan_signature VARCHAR(255),          -- malware found in an infected file
+    ADD COLUMN scan_claimed_at TIMESTAMP WITH TIME ZONE, -- when a worker started scanning the file
+    ADD COLUMN scanned_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT media_scan_status_check CHECK (scan_status IN ('pending', 'clean', 'infected'));
+
+CREATE INDEX idx_media_scan_pending ON media(created_at) WHERE scan_status = 'pending';
+
+-- Scanning is not an edit of the file's details and must not bump updated_at
+DROP TRIGGER IF EXISTS update_media_updated_at ON media;
+
+CREATE TRIGGER update_media_updated_at
+    BEFORE UPDATE ON media
+    FOR EACH ROW
+    WHEN (OLD.scan_status IS NOT DISTINCT FROM NEW.scan_status
+        AND OLD.scan_claimed_at IS NOT DISTINCT FROM NEW.scan_claimed_at)
+    EXECUTE FUNCTION update_updated_at_column();
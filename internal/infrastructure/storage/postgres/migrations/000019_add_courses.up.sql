@@ -0,0 +1,65 @@
+-- Courses taught by instructors, made of ordered modules of ordered lessons
+CREATE TABLE courses (
+                         id SERIAL PRIMARY KEY,
+                         user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- the owning instructor
+                         title VARCHAR(200) NOT NULL,
+                         slug VARCHAR(220) NOT NULL UNIQUE,
+                         description VARCHAR(2000),
+                         status VARCHAR(20) NOT NULL DEFAULT 'draft', -- draft, published
+                         published_at TIMESTAMP WITH TIME ZONE,       -- first publication
+                         created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                         updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                         CONSTRAINT courses_valid_status CHECK (status IN ('draft', 'published'))
+);
+
+CREATE INDEX idx_courses_user_id ON courses(user_id);
+CREATE INDEX idx_courses_published ON courses(published_at DESC) WHERE status = 'published';
+
+CREATE TRIGGER update_courses_updated_at
+    BEFORE UPDATE ON courses
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE course_modules (
+                                id SERIAL PRIMARY KEY,
+                                course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+                                title VARCHAR(200) NOT NULL,
+                                description VARCHAR(1000),
+                                position INTEGER NOT NULL, -- 1-based order within the course
+                                status VARCHAR(20) NOT NULL DEFAULT 'draft',
+                                created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                                -- Checked at commit so positions can be swapped within a transaction
+                                CONSTRAINT course_modules_position_key UNIQUE (course_id, position) DEFERRABLE INITIALLY DEFERRED,
+                                CONSTRAINT course_modules_position_positive CHECK (position > 0),
+                                CONSTRAINT course_modules_valid_status CHECK (status IN ('draft', 'published'))
+);
+
+CREATE TRIGGER update_course_modules_updated_at
+    BEFORE UPDATE ON course_modules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE lessons (
+                         id SERIAL PRIMARY KEY,
+                         module_id INTEGER NOT NULL REFERENCES course_modules(id) ON DELETE CASCADE,
+                         title VARCHAR(200) NOT NULL,
+                         content TEXT NOT NULL,
+                         duration_minutes INTEGER,  -- estimated time to complete
+                         position INTEGER NOT NULL, -- 1-based order within the module
+                         status VARCHAR(20) NOT NULL DEFAULT 'draft',
+                         created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                         updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                         CONSTRAINT lessons_position_key UNIQUE (module_id, position) DEFERRABLE INITIALLY DEFERRED,
+                         CONSTRAINT lessons_position_positive CHECK (position > 0),
+                         CONSTRAINT lessons_duration_positive CHECK (duration_minutes > 0),
+                         CONSTRAINT lessons_valid_status CHECK (status IN ('draft', 'published'))
+);
+
+CREATE TRIGGER update_lessons_updated_at
+    BEFORE UPDATE ON lessons
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS course_enrollments;
+
+ALTER TABLE courses
+    DROP CONSTRAINT IF EXISTS courses_ends_after_start,
+    DROP CONSTRAINT IF EXISTS courses_enrollment_limit_positive,
+    DROP COLUMN IF EXISTS ends_at,
+    DROP COLUMN IF EXISTS starts_at,
+    DROP COLUMN IF EXISTS enrollment_limit,
+    DROP COLUMN IF EXISTS is_free;
@@ -0,0 +1,19 @@
+ALTER TABLE courses
+    ADD COLUMN is_free BOOLEAN NOT NULL DEFAULT true,         -- students may enroll themselves
+    ADD COLUMN enrollment_limit INTEGER,                       -- most students enrolled at once, NULL for no limit
+    ADD COLUMN starts_at TIMESTAMP WITH TIME ZONE,             -- lessons open to students from
+    ADD COLUMN ends_at TIMESTAMP WITH TIME ZONE,               -- self-enrollment closes at
+    ADD CONSTRAINT courses_enrollment_limit_positive CHECK (enrollment_limit > 0),
+    ADD CONSTRAINT courses_ends_after_start CHECK (ends_at > starts_at);
+
+-- Users enrolled in a course, who may read its lessons
+CREATE TABLE course_enrollments (
+                                    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+                                    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                    enrolled_by UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL for self-enrollment
+                                    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                                    PRIMARY KEY (course_id, user_id)
+);
+
+CREATE INDEX idx_course_enrollments_user ON course_enrollments(user_id, created_at DESC);
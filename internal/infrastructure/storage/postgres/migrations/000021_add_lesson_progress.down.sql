@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS lesson_progress;
+
+ALTER TABLE course_enrollments DROP COLUMN IF EXISTS completed_at;
+
+ALTER TABLE lessons DROP COLUMN IF EXISTS is_required;
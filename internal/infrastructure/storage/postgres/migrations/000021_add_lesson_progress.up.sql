@@ -0,0 +1,19 @@
+-- Optional lessons do not count towards completing a course
+ALTER TABLE lessons ADD COLUMN is_required BOOLEAN NOT NULL DEFAULT true;
+
+-- Set once all required lessons of the course are completed
+ALTER TABLE course_enrollments ADD COLUMN completed_at TIMESTAMP WITH TIME ZONE;
+
+-- A user's progress through a lesson
+CREATE TABLE lesson_progress (
+                                 user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                 lesson_id INTEGER NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+                                 position_seconds INTEGER NOT NULL DEFAULT 0, -- last video checkpoint
+                                 completed_at TIMESTAMP WITH TIME ZONE,
+                                 last_viewed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+                                 PRIMARY KEY (user_id, lesson_id),
+                                 CONSTRAINT lesson_progress_position_non_negative CHECK (position_seconds >= 0)
+);
+
+CREATE INDEX idx_lesson_progress_lesson ON lesson_progress(lesson_id) WHERE completed_at IS NOT NULL;
@@ -0,0 +1,6 @@
+UPDATE media SET scan_status = 'pending', scanned_at = NULL WHERE scan_status = 'failed';
+
+ALTER TABLE media
+    DROP CONSTRAINT IF EXISTS media_scan_status_check,
+    ADD CONSTRAINT media_scan_status_check CHECK (scan_status IN ('pending', 'clean', 'infected')),
+    DROP COLUMN IF EXISTS scan_attempts;
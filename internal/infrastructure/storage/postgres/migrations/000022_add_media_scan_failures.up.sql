@@ -0,0 +1,6 @@
+-- Scans are retried with a growing delay and given up on after a few attempts, leaving
+-- the file in quarantine as failed.
+ALTER TABLE media
+    ADD COLUMN scan_attempts INT NOT NULL DEFAULT 0,
+    DROP CONSTRAINT media_scan_status_check,
+    ADD CONSTRAINT media_scan_status_check CHECK (scan_status IN ('pending', 'clean', 'infected', 'failed'));
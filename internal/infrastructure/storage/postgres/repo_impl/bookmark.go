@@ -0,0 +1,99 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+)
+
+type BookmarkRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewBookmarkRepository(db *sql.DB) *BookmarkRepositoryImpl {
+	return &BookmarkRepositoryImpl{db: db}
+}
+
+func (r *BookmarkRepositoryImpl) AddBookmark(ctx context.Context, userID uuid.UUID, postID int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        INSERT INTO bookmarks (user_id, post_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query, userID, postID)
+	return err
+}
+
+func (r *BookmarkRepositoryImpl) RemoveBookmark(ctx context.Context, userID uuid.UUID, postID int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `DELETE FROM bookmarks WHERE user_id = $1 AND post_id = $2`
+
+	_, err := r.db.ExecContext(ctx, query, userID, postID)
+	return err
+}
+
+func (r *BookmarkRepositoryImpl) IsBookmarked(ctx context.Context, userID uuid.UUID, postID int) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM bookmarks WHERE user_id = $1 AND post_id = $2)`
+	err := r.db.QueryRowContext(ctx, query, userID, postID).Scan(&exists)
+	return exists, err
+}
+
+func (r *BookmarkRepositoryImpl) GetUserBookmarks(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*postDTOs.BookmarkDTO, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	// Posts that were unpublished after being saved are hidden from the list
+	const condition = `b.user_id = $1 AND p.status = 'published' AND p.visibility <> 'private'`
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM bookmarks b JOIN posts p ON p.id = b.post_id WHERE ` + condition
+	if err := r.db.QueryRowContext(ctx, countQuery, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	bookmarks := []*postDTOs.BookmarkDTO{}
+	if total == 0 {
+		return bookmarks, 0, nil
+	}
+
+	query := `
+        SELECT ` + postColumns + `, b.created_at
+        FROM bookmarks b
+        JOIN posts p ON p.id = b.post_id
+        WHERE ` + condition + `
+        ORDER BY b.created_at DESC, p.id DESC
+        LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		bookmark := &postDTOs.BookmarkDTO{}
+		post, err := scanPost(rows, &bookmark.BookmarkedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		bookmark.Post = post
+		bookmarks = append(bookmarks, bookmark)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return bookmarks, total, nil
+}
@@ -0,0 +1,291 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/dtos/commentDTOs"
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"app05/pkg/appErrors"
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// commentColumns selects a comment aliased as c joined with its author aliased as u
+const commentColumns = `
+        c.id, c.post_id, c.parent_id, c.user_id, u.first_name, u.last_name, u.profile_picture_url,
+        c.body, c.status, c.deleted_at IS NOT NULL, c.edited_at, c.created_at, c.updated_at`
+
+// visibleThreadCondition matches approved top-level comments. Deleted ones are
+// kept as placeholders only while they still have visible replies.
+const visibleThreadCondition = `
+        c.post_id = $1
+        AND c.parent_id IS NULL
+        AND c.status = 'approved'
+        AND (c.deleted_at IS NULL OR EXISTS (
+            SELECT 1 FROM comments r
+            WHERE r.parent_id = c.id AND r.status = 'approved' AND r.deleted_at IS NULL))`
+
+type CommentRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) *CommentRepositoryImpl {
+	return &CommentRepositoryImpl{db: db}
+}
+
+func (r *CommentRepositoryImpl) CreateComment(ctx context.Context, comment *commentDTOs.CommentDTO) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        INSERT INTO comments (post_id, user_id, parent_id, body, status)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at, updated_at`
+
+	return r.db.QueryRowContext(
+		ctx,
+		query,
+		comment.PostID,
+		comment.Author.ID,
+		comment.ParentID,
+		comment.Body,
+		comment.Status,
+	).Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt)
+}
+
+func (r *CommentRepositoryImpl) GetCommentByID(ctx context.Context, id int) (*commentDTOs.CommentDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT ` + commentColumns + `
+        FROM comments c
+        JOIN users u ON u.id = c.user_id
+        WHERE c.id = $1`
+
+	comment, err := scanComment(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "comment not found")
+	}
+	return comment, err
+}
+
+func (r *CommentRepositoryImpl) GetPostThreads(ctx context.Context, postID int, limit, offset int) ([]*commentDTOs.CommentDTO, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	threads := []*commentDTOs.CommentDTO{}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM comments c WHERE ` + visibleThreadCondition
+	if err := r.db.QueryRowContext(ctx, countQuery, postID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return threads, 0, nil
+	}
+
+	threadQuery := `
+        SELECT ` + commentColumns + `
+        FROM comments c
+        JOIN users u ON u.id = c.user_id
+        WHERE ` + visibleThreadCondition + `
+        ORDER BY c.created_at, c.id
+        LIMIT $2 OFFSET $3`
+
+	threads, err := r.queryComments(ctx, threadQuery, postID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	byID := make(map[int]*commentDTOs.CommentDTO, len(threads))
+	parentIDs := make([]int, 0, len(threads))
+	for _, thread := range threads {
+		byID[thread.ID] = thread
+		parentIDs = append(parentIDs, thread.ID)
+	}
+
+	replyQuery := `
+        SELECT ` + commentColumns + `
+        FROM comments c
+        JOIN users u ON u.id = c.user_id
+        WHERE c.parent_id = ANY($1)
+          AND c.status = 'approved'
+          AND c.deleted_at IS NULL
+        ORDER BY c.created_at, c.id`
+
+	replies, err := r.queryComments(ctx, replyQuery, pq.Array(parentIDs))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, reply := range replies {
+		if parent, ok := byID[*reply.ParentID]; ok {
+			parent.Replies = append(parent.Replies, reply)
+		}
+	}
+
+	return threads, total, nil
+}
+
+func (r *CommentRepositoryImpl) UpdateCommentBody(ctx context.Context, id int, body string, status entities.CommentStatus) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	// A changed status clears the moderation decision it replaces
+	query := `
+        UPDATE comments
+        SET body = $1, edited_at = CURRENT_TIMESTAMP, status = $2,
+            moderated_by = CASE WHEN status = $2 THEN moderated_by END,
+            moderated_at = CASE WHEN status = $2 THEN moderated_at END
+        WHERE id = $3 AND deleted_at IS NULL`
+
+	return execAffectingOne(ctx, r.db, "comment not found", query, body, status, id)
+}
+
+func (r *CommentRepositoryImpl) SoftDeleteComment(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        UPDATE comments
+        SET deleted_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND deleted_at IS NULL`
+
+	return execAffectingOne(ctx, r.db, "comment not found", query, id)
+}
+
+func (r *CommentRepositoryImpl) GetCommentsByStatus(ctx context.Context, status entities.CommentStatus, limit, offset int) ([]*commentDTOs.CommentDTO, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM comments WHERE status = $1 AND deleted_at IS NULL`
+	if err := r.db.QueryRowContext(ctx, countQuery, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT ` + commentColumns + `, p.id, p.title, p.slug
+        FROM comments c
+        JOIN users u ON u.id = c.user_id
+        JOIN posts p ON p.id = c.post_id
+        WHERE c.status = $1 AND c.deleted_at IS NULL
+        ORDER BY c.created_at, c.id
+        LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	comments := []*commentDTOs.CommentDTO{}
+	for rows.Next() {
+		post := &commentDTOs.CommentedPostDTO{}
+		comment, err := scanComment(rows, &post.ID, &post.Title, &post.Slug)
+		if err != nil {
+			return nil, 0, err
+		}
+		comment.Post = post
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+func (r *CommentRepositoryImpl) ModerateComment(ctx context.Context, id int, status entities.CommentStatus, moderatorID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        UPDATE comments
+        SET status = $1, moderated_by = $2, moderated_at = CURRENT_TIMESTAMP
+        WHERE id = $3 AND deleted_at IS NULL`
+
+	return execAffectingOne(ctx, r.db, "comment not found", query, status, moderatorID, id)
+}
+
+func (r *CommentRepositoryImpl) queryComments(ctx context.Context, query string, args ...interface{}) ([]*commentDTOs.CommentDTO, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*commentDTOs.CommentDTO{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// scanComment reads a row selected with commentColumns followed by any extra destinations
+func scanComment(row rowScanner, extra ...interface{}) (*commentDTOs.CommentDTO, error) {
+	var comment commentDTOs.CommentDTO
+	var parentID sql.NullInt64
+
+	dest := []interface{}{
+		&comment.ID,
+		&comment.PostID,
+		&parentID,
+		&comment.Author.ID,
+		&comment.Author.FirstName,
+		&comment.Author.LastName,
+		&comment.Author.ProfilePictureURL,
+		&comment.Body,
+		&comment.Status,
+		&comment.Deleted,
+		&comment.EditedAt,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		comment.ParentID = &id
+	}
+
+	// Deleted comments stay in threads as placeholders without their content
+	if comment.Deleted {
+		comment.Body = ""
+	}
+
+	return &comment, nil
+}
+
+// execAffectingOne runs an update and returns a not found error when no row was affected
+func execAffectingOne(ctx context.Context, db *sql.DB, notFoundMsg, query string, args ...interface{}) error {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return appErrors.New(appErrors.CodeNotFound, notFoundMsg)
+	}
+
+	return nil
+}
@@ -0,0 +1,710 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"app05/pkg/appErrors"
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type CourseRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewCourseRepository(db *sql.DB) *CourseRepositoryImpl {
+	return &CourseRepositoryImpl{db: db}
+}
+
+// courseColumns selects a course aliased as c with its published lesson count and enrollment count
+const courseColumns = `
+        c.id, c.user_id, c.title, c.slug, c.description, c.status,
+        (SELECT COUNT(*) FROM lessons l JOIN course_modules m ON m.id = l.module_id
+         WHERE m.course_id = c.id AND m.status = 'published' AND l.status = 'published'),
+        c.is_free, c.enrollment_limit,
+        (SELECT COUNT(*) FROM course_enrollments e WHERE e.course_id = c.id),
+        c.starts_at, c.ends_at, c.published_at, c.created_at, c.updated_at`
+
+const moduleColumns = `id, course_id, title, description, position, status, created_at, updated_at`
+
+// lessonColumns selects a lesson aliased as l joined to its module aliased as m
+const lessonColumns = `
+        l.id, l.module_id, m.course_id, l.title, l.content, l.duration_minutes,
+        l.is_required, l.position, l.status, l.created_at, l.updated_at`
+
+func (r *CourseRepositoryImpl) CreateCourse(ctx context.Context, course *entities.Course) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        INSERT INTO courses (user_id, title, slug, description, status)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, is_free, created_at, updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		course.UserID,
+		course.Title,
+		course.Slug,
+		course.Description,
+		string(course.Status),
+	).Scan(&course.ID, &course.IsFree, &course.CreatedAt, &course.UpdatedAt)
+
+	return mapCourseError(err)
+}
+
+func (r *CourseRepositoryImpl) UpdateCourse(ctx context.Context, course *entities.Course) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        UPDATE courses
+        SET title = $1, slug = $2, description = $3, status = $4,
+            published_at = CASE WHEN $4 = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END
+        WHERE id = $5
+        RETURNING published_at, updated_at`
+
+	var publishedAt sql.NullTime
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		course.Title,
+		course.Slug,
+		course.Description,
+		string(course.Status),
+		course.ID,
+	).Scan(&publishedAt, &course.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return appErrors.New(appErrors.CodeNotFound, "course not found")
+	}
+	if err != nil {
+		return mapCourseError(err)
+	}
+
+	if publishedAt.Valid {
+		course.PublishedAt = &publishedAt.Time
+	}
+	return nil
+}
+
+func (r *CourseRepositoryImpl) DeleteCourse(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return execAffectingOne(ctx, r.db, "course not found", `DELETE FROM courses WHERE id = $1`, id)
+}
+
+func (r *CourseRepositoryImpl) GetCourseByID(ctx context.Context, id int) (*entities.Course, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `SELECT ` + courseColumns + ` FROM courses c WHERE c.id = $1`
+	return scanCourse(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *CourseRepositoryImpl) GetCourseBySlug(ctx context.Context, slug string) (*entities.Course, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `SELECT ` + courseColumns + ` FROM courses c WHERE c.slug = $1`
+	return scanCourse(r.db.QueryRowContext(ctx, query, slug))
+}
+
+func (r *CourseRepositoryImpl) GetPublishedCourses(ctx context.Context, limit, offset int) ([]*entities.Course, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM courses WHERE status = 'published'`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT ` + courseColumns + `
+        FROM courses c
+        WHERE c.status = 'published'
+        ORDER BY c.published_at DESC, c.id DESC
+        LIMIT $1 OFFSET $2`
+
+	courses, err := r.queryCourses(ctx, query, limit, offset)
+	return courses, total, err
+}
+
+func (r *CourseRepositoryImpl) GetCoursesByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Course, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM courses WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT ` + courseColumns + `
+        FROM courses c
+        WHERE c.user_id = $1
+        ORDER BY c.updated_at DESC, c.id DESC
+        LIMIT $2 OFFSET $3`
+
+	courses, err := r.queryCourses(ctx, query, userID, limit, offset)
+	return courses, total, err
+}
+
+func (r *CourseRepositoryImpl) CourseSlugExists(ctx context.Context, slug string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM courses WHERE slug = $1)`, slug).Scan(&exists)
+	return exists, err
+}
+
+func (r *CourseRepositoryImpl) UpdateEnrollmentSettings(ctx context.Context, course *entities.Course) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        UPDATE courses
+        SET is_free = $1, enrollment_limit = $2, starts_at = $3, ends_at = $4
+        WHERE id = $5
+        RETURNING updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		course.IsFree,
+		course.EnrollmentLimit,
+		course.StartsAt,
+		course.EndsAt,
+		course.ID,
+	).Scan(&course.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return appErrors.New(appErrors.CodeNotFound, "course not found")
+	}
+	return err
+}
+
+func (r *CourseRepositoryImpl) CreateModule(ctx context.Context, module *entities.Module, position int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	err := dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockCourse(ctx, tx, module.CourseID); err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM course_modules WHERE course_id = $1`, module.CourseID).Scan(&count); err != nil {
+			return err
+		}
+		if position < 1 || position > count+1 {
+			position = count + 1
+		}
+
+		_, err := tx.ExecContext(ctx, `
+            UPDATE course_modules SET position = position + 1
+            WHERE course_id = $1 AND position >= $2`,
+			module.CourseID, position)
+		if err != nil {
+			return err
+		}
+
+		module.Position = position
+		return tx.QueryRowContext(ctx, `
+            INSERT INTO course_modules (course_id, title, description, position, status)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING id, created_at, updated_at`,
+			module.CourseID, module.Title, module.Description, module.Position, string(module.Status),
+		).Scan(&module.ID, &module.CreatedAt, &module.UpdatedAt)
+	})
+
+	return mapCourseError(err)
+}
+
+func (r *CourseRepositoryImpl) UpdateModule(ctx context.Context, module *entities.Module) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        UPDATE course_modules
+        SET title = $1, description = $2, status = $3
+        WHERE id = $4
+        RETURNING updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		module.Title,
+		module.Description,
+		string(module.Status),
+		module.ID,
+	).Scan(&module.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return appErrors.New(appErrors.CodeNotFound, "module not found")
+	}
+	return err
+}
+
+func (r *CourseRepositoryImpl) DeleteModule(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		var courseID int
+		err := tx.QueryRowContext(ctx, `SELECT course_id FROM course_modules WHERE id = $1`, id).Scan(&courseID)
+		if err == sql.ErrNoRows {
+			return appErrors.New(appErrors.CodeNotFound, "module not found")
+		}
+		if err != nil {
+			return err
+		}
+		if err := lockCourse(ctx, tx, courseID); err != nil {
+			return err
+		}
+
+		var position int
+		err = tx.QueryRowContext(ctx, `DELETE FROM course_modules WHERE id = $1 RETURNING position`, id).Scan(&position)
+		if err == sql.ErrNoRows {
+			return appErrors.New(appErrors.CodeNotFound, "module not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            UPDATE course_modules SET position = position - 1
+            WHERE course_id = $1 AND position > $2`,
+			courseID, position)
+		return err
+	})
+}
+
+func (r *CourseRepositoryImpl) GetModuleByID(ctx context.Context, id int) (*entities.Module, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `SELECT ` + moduleColumns + ` FROM course_modules WHERE id = $1`
+	return scanModule(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *CourseRepositoryImpl) GetModules(ctx context.Context, courseID int, publishedOnly bool) ([]*entities.Module, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT id, course_id, title, description, ROW_NUMBER() OVER (ORDER BY position), status, created_at, updated_at
+        FROM course_modules
+        WHERE course_id = $1 AND (NOT $2 OR status = 'published')
+        ORDER BY position`
+
+	rows, err := r.db.QueryContext(ctx, query, courseID, publishedOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	modules := []*entities.Module{}
+	for rows.Next() {
+		module, err := scanModule(rows)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, module)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return modules, nil
+}
+
+func (r *CourseRepositoryImpl) ReorderModules(ctx context.Context, courseID int, moduleIDs []int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockCourse(ctx, tx, courseID); err != nil {
+			return err
+		}
+
+		// The new order must be a permutation of the current modules
+		var matches bool
+		err := tx.QueryRowContext(ctx, `
+            SELECT COALESCE(array_agg(id ORDER BY id), '{}') =
+                   (SELECT COALESCE(array_agg(id ORDER BY id), '{}') FROM unnest($2::int[]) AS id)
+            FROM course_modules WHERE course_id = $1`,
+			courseID, pq.Array(moduleIDs)).Scan(&matches)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			return appErrors.New(appErrors.CodeBadRequest, "module_ids must list every module in the course exactly once")
+		}
+
+		// Positions are unique per course, checked when the transaction commits
+		_, err = tx.ExecContext(ctx, `
+            UPDATE course_modules m SET position = o.position
+            FROM unnest($2::int[]) WITH ORDINALITY AS o(id, position)
+            WHERE m.course_id = $1 AND m.id = o.id`,
+			courseID, pq.Array(moduleIDs))
+		return err
+	})
+}
+
+func (r *CourseRepositoryImpl) CreateLesson(ctx context.Context, lesson *entities.Lesson, position int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	err := dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		courseID, err := lockModule(ctx, tx, lesson.ModuleID)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM lessons WHERE module_id = $1`, lesson.ModuleID).Scan(&count); err != nil {
+			return err
+		}
+		if position < 1 || position > count+1 {
+			position = count + 1
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            UPDATE lessons SET position = position + 1
+            WHERE module_id = $1 AND position >= $2`,
+			lesson.ModuleID, position)
+		if err != nil {
+			return err
+		}
+
+		lesson.CourseID = courseID
+		lesson.Position = position
+		return tx.QueryRowContext(ctx, `
+            INSERT INTO lessons (module_id, title, content, duration_minutes, is_required, position, status)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)
+            RETURNING id, created_at, updated_at`,
+			lesson.ModuleID, lesson.Title, lesson.Content, lesson.DurationMinutes, lesson.IsRequired, lesson.Position, string(lesson.Status),
+		).Scan(&lesson.ID, &lesson.CreatedAt, &lesson.UpdatedAt)
+	})
+
+	return mapCourseError(err)
+}
+
+func (r *CourseRepositoryImpl) UpdateLesson(ctx context.Context, lesson *entities.Lesson) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        UPDATE lessons
+        SET title = $1, content = $2, duration_minutes = $3, is_required = $4, status = $5
+        WHERE id = $6
+        RETURNING updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		lesson.Title,
+		lesson.Content,
+		lesson.DurationMinutes,
+		lesson.IsRequired,
+		string(lesson.Status),
+		lesson.ID,
+	).Scan(&lesson.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return appErrors.New(appErrors.CodeNotFound, "lesson not found")
+	}
+	return err
+}
+
+func (r *CourseRepositoryImpl) DeleteLesson(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		var moduleID int
+		err := tx.QueryRowContext(ctx, `SELECT module_id FROM lessons WHERE id = $1`, id).Scan(&moduleID)
+		if err == sql.ErrNoRows {
+			return appErrors.New(appErrors.CodeNotFound, "lesson not found")
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := lockModule(ctx, tx, moduleID); err != nil {
+			return err
+		}
+
+		var position int
+		err = tx.QueryRowContext(ctx, `DELETE FROM lessons WHERE id = $1 RETURNING position`, id).Scan(&position)
+		if err == sql.ErrNoRows {
+			return appErrors.New(appErrors.CodeNotFound, "lesson not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            UPDATE lessons SET position = position - 1
+            WHERE module_id = $1 AND position > $2`,
+			moduleID, position)
+		return err
+	})
+}
+
+func (r *CourseRepositoryImpl) GetLessonByID(ctx context.Context, id int) (*entities.Lesson, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT ` + lessonColumns + `
+        FROM lessons l
+        JOIN course_modules m ON m.id = l.module_id
+        WHERE l.id = $1`
+
+	return scanLesson(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *CourseRepositoryImpl) GetLessons(ctx context.Context, courseID int, publishedOnly bool) ([]*entities.Lesson, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT l.id, l.module_id, m.course_id, l.title, '', l.duration_minutes, l.is_required,
+               ROW_NUMBER() OVER (PARTITION BY l.module_id ORDER BY l.position),
+               l.status, l.created_at, l.updated_at
+        FROM lessons l
+        JOIN course_modules m ON m.id = l.module_id
+        WHERE m.course_id = $1 AND (NOT $2 OR (m.status = 'published' AND l.status = 'published'))
+        ORDER BY m.position, l.position`
+
+	rows, err := r.db.QueryContext(ctx, query, courseID, publishedOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lessons := []*entities.Lesson{}
+	for rows.Next() {
+		lesson, err := scanLesson(rows)
+		if err != nil {
+			return nil, err
+		}
+		lessons = append(lessons, lesson)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lessons, nil
+}
+
+func (r *CourseRepositoryImpl) ReorderLessons(ctx context.Context, moduleID int, lessonIDs []int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := lockModule(ctx, tx, moduleID); err != nil {
+			return err
+		}
+
+		// The new order must be a permutation of the current lessons
+		var matches bool
+		err := tx.QueryRowContext(ctx, `
+            SELECT COALESCE(array_agg(id ORDER BY id), '{}') =
+                   (SELECT COALESCE(array_agg(id ORDER BY id), '{}') FROM unnest($2::int[]) AS id)
+            FROM lessons WHERE module_id = $1`,
+			moduleID, pq.Array(lessonIDs)).Scan(&matches)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			return appErrors.New(appErrors.CodeBadRequest, "lesson_ids must list every lesson in the module exactly once")
+		}
+
+		// Positions are unique per module, checked when the transaction commits
+		_, err = tx.ExecContext(ctx, `
+            UPDATE lessons l SET position = o.position
+            FROM unnest($2::int[]) WITH ORDINALITY AS o(id, position)
+            WHERE l.module_id = $1 AND l.id = o.id`,
+			moduleID, pq.Array(lessonIDs))
+		return err
+	})
+}
+
+func (r *CourseRepositoryImpl) queryCourses(ctx context.Context, query string, args ...interface{}) ([]*entities.Course, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	courses := []*entities.Course{}
+	for rows.Next() {
+		course, err := scanCourse(rows)
+		if err != nil {
+			return nil, err
+		}
+		courses = append(courses, course)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return courses, nil
+}
+
+// lockCourse locks a course row so concurrent changes to its module order are serialized
+func lockCourse(ctx context.Context, tx *sql.Tx, courseID int) error {
+	var id int
+	err := tx.QueryRowContext(ctx, `SELECT id FROM courses WHERE id = $1 FOR UPDATE`, courseID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return appErrors.New(appErrors.CodeNotFound, "course not found")
+	}
+	return err
+}
+
+// lockModule locks a module row so concurrent changes to its lesson order are
+// serialized, returning the module's course
+func lockModule(ctx context.Context, tx *sql.Tx, moduleID int) (int, error) {
+	var courseID int
+	err := tx.QueryRowContext(ctx, `SELECT course_id FROM course_modules WHERE id = $1 FOR UPDATE`, moduleID).Scan(&courseID)
+	if err == sql.ErrNoRows {
+		return 0, appErrors.New(appErrors.CodeNotFound, "module not found")
+	}
+	return courseID, err
+}
+
+// scanCourse reads a row selected with courseColumns. Any extra destinations are
+// scanned from the columns that follow courseColumns.
+func scanCourse(row rowScanner, extra ...interface{}) (*entities.Course, error) {
+	var course entities.Course
+	var description sql.NullString
+	var enrollmentLimit sql.NullInt64
+	var startsAt, endsAt, publishedAt sql.NullTime
+
+	dest := []interface{}{
+		&course.ID,
+		&course.UserID,
+		&course.Title,
+		&course.Slug,
+		&description,
+		&course.Status,
+		&course.LessonCount,
+		&course.IsFree,
+		&enrollmentLimit,
+		&course.EnrollmentCount,
+		&startsAt,
+		&endsAt,
+		&publishedAt,
+		&course.CreatedAt,
+		&course.UpdatedAt,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "course not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		course.Description = &description.String
+	}
+	if enrollmentLimit.Valid {
+		limit := int(enrollmentLimit.Int64)
+		course.EnrollmentLimit = &limit
+	}
+	if startsAt.Valid {
+		course.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		course.EndsAt = &endsAt.Time
+	}
+	if publishedAt.Valid {
+		course.PublishedAt = &publishedAt.Time
+	}
+
+	return &course, nil
+}
+
+func scanModule(row rowScanner) (*entities.Module, error) {
+	var module entities.Module
+	var description sql.NullString
+
+	err := row.Scan(
+		&module.ID,
+		&module.CourseID,
+		&module.Title,
+		&description,
+		&module.Position,
+		&module.Status,
+		&module.CreatedAt,
+		&module.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "module not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		module.Description = &description.String
+	}
+
+	return &module, nil
+}
+
+func scanLesson(row rowScanner) (*entities.Lesson, error) {
+	var lesson entities.Lesson
+	var duration sql.NullInt64
+
+	err := row.Scan(
+		&lesson.ID,
+		&lesson.ModuleID,
+		&lesson.CourseID,
+		&lesson.Title,
+		&lesson.Content,
+		&duration,
+		&lesson.IsRequired,
+		&lesson.Position,
+		&lesson.Status,
+		&lesson.CreatedAt,
+		&lesson.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "lesson not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if duration.Valid {
+		minutes := int(duration.Int64)
+		lesson.DurationMinutes = &minutes
+	}
+
+	return &lesson, nil
+}
+
+func mapCourseError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok {
+		if pqErr.Constraint == "courses_slug_key" {
+			return appErrors.New(appErrors.CodeBadRequest, "a course with this slug already exists")
+		}
+		if pqErr.Code == "23503" {
+			return appErrors.New(appErrors.CodeNotFound, "referenced course or module does not exist")
+		}
+	}
+	return err
+}
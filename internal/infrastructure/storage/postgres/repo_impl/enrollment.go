@@ -0,0 +1,257 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"app05/pkg/appErrors"
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type EnrollmentRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewEnrollmentRepository(db *sql.DB) *EnrollmentRepositoryImpl {
+	return &EnrollmentRepositoryImpl{db: db}
+}
+
+// enrollmentProgress selects the percent of required, published lessons the student of
+// an enrollment aliased as e completed, rounded down so only finished courses reach 100
+const enrollmentProgress = `
+        (SELECT COALESCE(FLOOR(100.0 * COUNT(p.completed_at) / NULLIF(COUNT(*), 0)), 0)::int
+         FROM lessons l
+         JOIN course_modules m ON m.id = l.module_id
+         LEFT JOIN lesson_progress p ON p.lesson_id = l.id AND p.user_id = e.user_id
+         WHERE m.course_id = e.course_id AND m.status = 'published' AND l.status = 'published' AND l.is_required)`
+
+func (r *EnrollmentRepositoryImpl) CreateEnrollment(ctx context.Context, enrollment *entities.Enrollment) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	err := dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		// Locking the course serializes enrollments, so the limit cannot be exceeded
+		var limit sql.NullInt64
+		err := tx.QueryRowContext(ctx, `SELECT enrollment_limit FROM courses WHERE id = $1 FOR UPDATE`, enrollment.CourseID).Scan(&limit)
+		if err == sql.ErrNoRows {
+			return appErrors.New(appErrors.CodeNotFound, "course not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		var enrolledBy sql.NullString
+		err = tx.QueryRowContext(ctx, `
+            SELECT enrolled_by, created_at FROM course_enrollments
+            WHERE course_id = $1 AND user_id = $2`,
+			enrollment.CourseID, enrollment.UserID).Scan(&enrolledBy, &enrollment.EnrolledAt)
+		if err == nil {
+			enrollment.EnrolledBy = nil
+			if enrolledBy.Valid {
+				enrollment.EnrolledBy = &enrolledBy.String
+			}
+			return nil
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if limit.Valid {
+			var count int64
+			if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM course_enrollments WHERE course_id = $1`, enrollment.CourseID).Scan(&count); err != nil {
+				return err
+			}
+			if count >= limit.Int64 {
+				return appErrors.New(appErrors.CodeConflict, "course is full")
+			}
+		}
+
+		return tx.QueryRowContext(ctx, `
+            INSERT INTO course_enrollments (course_id, user_id, enrolled_by)
+            VALUES ($1, $2, $3)
+            RETURNING created_at`,
+			enrollment.CourseID, enrollment.UserID, enrollment.EnrolledBy,
+		).Scan(&enrollment.EnrolledAt)
+	})
+
+	return mapEnrollmentError(err)
+}
+
+func (r *EnrollmentRepositoryImpl) DeleteEnrollment(ctx context.Context, courseID int, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return execAffectingOne(ctx, r.db, "enrollment not found",
+		`DELETE FROM course_enrollments WHERE course_id = $1 AND user_id = $2`, courseID, userID)
+}
+
+func (r *EnrollmentRepositoryImpl) GetEnrollment(ctx context.Context, courseID int, userID uuid.UUID) (*entities.Enrollment, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT e.course_id, e.user_id, e.enrolled_by, e.created_at, ` + enrollmentProgress + `, e.completed_at
+        FROM course_enrollments e
+        WHERE e.course_id = $1 AND e.user_id = $2`
+
+	var enrollment entities.Enrollment
+	var enrolledBy sql.NullString
+	var completedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, courseID, userID).Scan(
+		&enrollment.CourseID,
+		&enrollment.UserID,
+		&enrolledBy,
+		&enrollment.EnrolledAt,
+		&enrollment.Progress,
+		&completedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "enrollment not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if enrolledBy.Valid {
+		enrollment.EnrolledBy = &enrolledBy.String
+	}
+	if completedAt.Valid {
+		enrollment.CompletedAt = &completedAt.Time
+	}
+
+	return &enrollment, nil
+}
+
+func (r *EnrollmentRepositoryImpl) IsEnrolled(ctx context.Context, courseID int, userID uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `SELECT EXISTS (SELECT 1 FROM course_enrollments WHERE course_id = $1 AND user_id = $2)`
+
+	var enrolled bool
+	err := r.db.QueryRowContext(ctx, query, courseID, userID).Scan(&enrolled)
+	return enrolled, err
+}
+
+func (r *EnrollmentRepositoryImpl) GetCourseEnrollments(ctx context.Context, courseID int, limit, offset int) ([]*entities.Enrollment, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM course_enrollments WHERE course_id = $1`, courseID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT e.course_id, e.user_id, u.first_name, u.last_name, u.email, e.enrolled_by, e.created_at,
+               ` + enrollmentProgress + `, e.completed_at
+        FROM course_enrollments e
+        JOIN users u ON u.id = e.user_id
+        WHERE e.course_id = $1
+        ORDER BY e.created_at DESC, e.user_id
+        LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, courseID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	enrollments := []*entities.Enrollment{}
+	for rows.Next() {
+		var enrollment entities.Enrollment
+		var enrolledBy sql.NullString
+		var completedAt sql.NullTime
+		err := rows.Scan(
+			&enrollment.CourseID,
+			&enrollment.UserID,
+			&enrollment.FirstName,
+			&enrollment.LastName,
+			&enrollment.Email,
+			&enrolledBy,
+			&enrollment.EnrolledAt,
+			&enrollment.Progress,
+			&completedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		if enrolledBy.Valid {
+			enrollment.EnrolledBy = &enrolledBy.String
+		}
+		if completedAt.Valid {
+			enrollment.CompletedAt = &completedAt.Time
+		}
+		enrollments = append(enrollments, &enrollment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return enrollments, total, nil
+}
+
+func (r *EnrollmentRepositoryImpl) GetUserEnrollments(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Enrollment, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var total int
+	err := r.db.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM course_enrollments e
+        JOIN courses c ON c.id = e.course_id
+        WHERE e.user_id = $1 AND c.status = 'published'`,
+		userID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT ` + courseColumns + `, e.user_id, e.enrolled_by, e.created_at, ` + enrollmentProgress + `, e.completed_at
+        FROM course_enrollments e
+        JOIN courses c ON c.id = e.course_id
+        WHERE e.user_id = $1 AND c.status = 'published'
+        ORDER BY e.created_at DESC, c.id DESC
+        LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	enrollments := []*entities.Enrollment{}
+	for rows.Next() {
+		var enrollment entities.Enrollment
+		var enrolledBy sql.NullString
+		var completedAt sql.NullTime
+		course, err := scanCourse(rows, &enrollment.UserID, &enrolledBy, &enrollment.EnrolledAt, &enrollment.Progress, &completedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		enrollment.CourseID = course.ID
+		enrollment.Course = course
+		if enrolledBy.Valid {
+			enrollment.EnrolledBy = &enrolledBy.String
+		}
+		if completedAt.Valid {
+			enrollment.CompletedAt = &completedAt.Time
+		}
+		enrollments = append(enrollments, &enrollment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return enrollments, total, nil
+}
+
+func mapEnrollmentError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return appErrors.New(appErrors.CodeNotFound, "referenced course or user does not exist")
+	}
+	return err
+}
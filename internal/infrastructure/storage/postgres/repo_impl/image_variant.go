@@ -0,0 +1,192 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/lib/pq"
+	"time"
+)
+
+type ImageVariantRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewImageVariantRepository(db *sql.DB) *ImageVariantRepositoryImpl {
+	return &ImageVariantRepositoryImpl{db: db}
+}
+
+// storedImageVariant is an image variant as stored in image_variant_sets.variants.
+// URLs are not stored, they depend on where the blob store is served.
+type storedImageVariant struct {
+	Key         string `json:"key"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Thumbnail   bool   `json:"thumbnail"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size_bytes"`
+}
+
+func (r *ImageVariantRepositoryImpl) CreateImageVariantSet(ctx context.Context, sourceKey string) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO image_variant_sets (source_key) VALUES ($1)
+        ON CONFLICT (source_key) DO NOTHING`,
+		sourceKey)
+	return err
+}
+
+func (r *ImageVariantRepositoryImpl) ClaimImageVariantSet(ctx context.Context, sourceKey string, lease time.Duration) (int, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	// Claiming touches updated_at, which starts the lease
+	var attempts int
+	err := r.db.QueryRowContext(ctx, `
+        UPDATE image_variant_sets SET attempts = attempts + 1
+        WHERE source_key = $1 AND status = 'pending'
+          AND (attempts = 0 OR updated_at < CURRENT_TIMESTAMP - $2 * INTERVAL '1 second')
+        RETURNING attempts`,
+		sourceKey, lease.Seconds()).Scan(&attempts)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return attempts, true, nil
+}
+
+func (r *ImageVariantRepositoryImpl) CompleteImageVariantSet(ctx context.Context, sourceKey string, variants []*entities.ImageVariant) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	stored := make([]storedImageVariant, len(variants))
+	for i, variant := range variants {
+		stored[i] = storedImageVariant{
+			Key:         variant.Key,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			Thumbnail:   variant.Thumbnail,
+			ContentType: variant.ContentType,
+			Size:        variant.Size,
+		}
+	}
+	variantsJSON, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+        UPDATE image_variant_sets
+        SET status = 'ready', variants = $2, last_error = NULL
+        WHERE source_key = $1`,
+		sourceKey, variantsJSON)
+	return err
+}
+
+func (r *ImageVariantRepositoryImpl) FailImageVariantSet(ctx context.Context, sourceKey, message string, final bool) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE image_variant_sets
+        SET status = CASE WHEN $3 THEN 'failed' ELSE status END, last_error = $2
+        WHERE source_key = $1`,
+		sourceKey, message, final)
+	return err
+}
+
+func (r *ImageVariantRepositoryImpl) GetImageVariants(ctx context.Context, sourceKeys []string) (map[string][]*entities.ImageVariant, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	variantsByKey := make(map[string][]*entities.ImageVariant, len(sourceKeys))
+	if len(sourceKeys) == 0 {
+		return variantsByKey, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT source_key, variants FROM image_variant_sets
+        WHERE source_key = ANY($1) AND status = 'ready'`,
+		pq.Array(sourceKeys))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sourceKey string
+		var variantsJSON []byte
+		if err := rows.Scan(&sourceKey, &variantsJSON); err != nil {
+			return nil, err
+		}
+
+		var stored []storedImageVariant
+		if err := json.Unmarshal(variantsJSON, &stored); err != nil {
+			return nil, err
+		}
+		variants := make([]*entities.ImageVariant, len(stored))
+		for i, variant := range stored {
+			variants[i] = &entities.ImageVariant{
+				Key:         variant.Key,
+				Width:       variant.Width,
+				Height:      variant.Height,
+				Thumbnail:   variant.Thumbnail,
+				ContentType: variant.ContentType,
+				Size:        variant.Size,
+			}
+		}
+		variantsByKey[sourceKey] = variants
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return variantsByKey, nil
+}
+
+func (r *ImageVariantRepositoryImpl) GetPendingImageVariantSets(ctx context.Context, lease time.Duration, limit int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT source_key FROM image_variant_sets
+        WHERE status = 'pending'
+          AND (attempts = 0 OR updated_at < CURRENT_TIMESTAMP - $1 * INTERVAL '1 second')
+        ORDER BY updated_at
+        LIMIT $2`,
+		lease.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *ImageVariantRepositoryImpl) DeleteImageVariantSet(ctx context.Context, sourceKey string) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `DELETE FROM image_variant_sets WHERE source_key = $1`, sourceKey)
+	return err
+}
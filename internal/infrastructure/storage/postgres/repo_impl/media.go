@@ -0,0 +1,383 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/dtos/mediaDTOs"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"app05/pkg/appErrors"
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"time"
+)
+
+type MediaRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewMediaRepository(db *sql.DB) *MediaRepositoryImpl {
+	return &MediaRepositoryImpl{db: db}
+}
+
+// mediaColumns selects a media file aliased as m with its tags and usage count
+const mediaColumns = `
+        m.id, m.user_id, m.storage_key, m.file_name, m.content_type, m.size_bytes, m.alt_text,
+        m.scan_status, m.scan_signature, m.scan_attempts,
+        COALESCE((SELECT array_agg(mt.tag ORDER BY mt.tag) FROM media_tags mt WHERE mt.media_id = m.id), '{}'),
+        (SELECT COUNT(*) FROM post_media pm WHERE pm.media_id = m.id),
+        m.created_at, m.updated_at`
+
+func (r *MediaRepositoryImpl) CreateMedia(ctx context.Context, media *mediaDTOs.MediaDTO, quota int64) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if quota >= 0 {
+			// Serializes uploads of the same user so concurrent uploads cannot both fit the quota
+			if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('media:' || $1))`, media.UserID); err != nil {
+				return err
+			}
+
+			var used int64
+			err := tx.QueryRowContext(ctx, `SELECT COALESCE(SUM(size_bytes), 0) FROM media WHERE user_id = $1`, media.UserID).Scan(&used)
+			if err != nil {
+				return err
+			}
+			if used+media.Size > quota {
+				return appErrors.New(appErrors.CodeForbidden, "storage quota exceeded")
+			}
+		}
+
+		query := `
+            INSERT INTO media (user_id, storage_key, file_name, content_type, size_bytes, alt_text)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id, scan_status, created_at, updated_at`
+
+		err := tx.QueryRowContext(
+			ctx,
+			query,
+			media.UserID,
+			media.StorageKey,
+			media.FileName,
+			media.ContentType,
+			media.Size,
+			media.AltText,
+		).Scan(&media.ID, &media.ScanStatus, &media.CreatedAt, &media.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		return setMediaTags(ctx, tx, media.ID, media.Tags)
+	})
+}
+
+func (r *MediaRepositoryImpl) UpdateMedia(ctx context.Context, media *mediaDTOs.MediaDTO) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+            UPDATE media SET alt_text = $1
+            WHERE id = $2
+            RETURNING updated_at`,
+			media.AltText, media.ID).Scan(&media.UpdatedAt)
+		if err == sql.ErrNoRows {
+			return appErrors.New(appErrors.CodeNotFound, "media not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM media_tags WHERE media_id = $1`, media.ID); err != nil {
+			return err
+		}
+		return setMediaTags(ctx, tx, media.ID, media.Tags)
+	})
+}
+
+func (r *MediaRepositoryImpl) DeleteMedia(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	// post_media restricts the delete while a post references the file
+	err := execAffectingOne(ctx, r.db, "media not found", `DELETE FROM media WHERE id = $1`, id)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return appErrors.New(appErrors.CodeConflict, "media is used by posts and cannot be deleted")
+	}
+	return err
+}
+
+func (r *MediaRepositoryImpl) GetMediaByID(ctx context.Context, id int) (*mediaDTOs.MediaDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `SELECT ` + mediaColumns + ` FROM media m WHERE m.id = $1`
+	return scanMedia(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *MediaRepositoryImpl) GetMediaByUser(ctx context.Context, userID uuid.UUID, tag string, limit, offset int) ([]*mediaDTOs.MediaDTO, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	filter := `
+        m.user_id = $1
+        AND ($2 = '' OR EXISTS (SELECT 1 FROM media_tags mt WHERE mt.media_id = m.id AND mt.tag = $2))`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM media m WHERE `+filter, userID, tag).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT ` + mediaColumns + `
+        FROM media m
+        WHERE ` + filter + `
+        ORDER BY m.created_at DESC, m.id DESC
+        LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, tag, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	mediaList := []*mediaDTOs.MediaDTO{}
+	for rows.Next() {
+		media, err := scanMedia(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		mediaList = append(mediaList, media)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return mediaList, total, nil
+}
+
+func (r *MediaRepositoryImpl) GetMediaUsage(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var used int64
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(SUM(size_bytes), 0) FROM media WHERE user_id = $1`, userID).Scan(&used)
+	return used, err
+}
+
+func (r *MediaRepositoryImpl) GetMediaPosts(ctx context.Context, mediaID int) ([]*mediaDTOs.MediaPostDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT p.id, p.title, p.slug, p.status
+        FROM post_media pm
+        JOIN posts p ON p.id = pm.post_id
+        WHERE pm.media_id = $1
+        ORDER BY p.updated_at DESC, p.id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []*mediaDTOs.MediaPostDTO{}
+	for rows.Next() {
+		var post mediaDTOs.MediaPostDTO
+		var slug sql.NullString
+		if err := rows.Scan(&post.ID, &post.Title, &slug, &post.Status); err != nil {
+			return nil, err
+		}
+		if slug.Valid {
+			post.Slug = &slug.String
+		}
+		posts = append(posts, &post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (r *MediaRepositoryImpl) SetPostMedia(ctx context.Context, postID int, keys []string) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	// Variants are stored in a directory named after their file's key without extension
+	referenced := `(m.storage_key = ANY($2) OR regexp_replace(m.storage_key, '\.[^./]*$', '') = ANY($2))`
+
+	return dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+            DELETE FROM post_media pm
+            USING media m
+            WHERE pm.post_id = $1 AND m.id = pm.media_id AND NOT `+referenced,
+			postID, pq.Array(keys))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO post_media (post_id, media_id)
+            SELECT $1, m.id FROM media m WHERE `+referenced+`
+            ON CONFLICT DO NOTHING`,
+			postID, pq.Array(keys))
+		return err
+	})
+}
+
+func (r *MediaRepositoryImpl) ClaimMediaScan(ctx context.Context, id int, lease time.Duration) (*mediaDTOs.MediaDTO, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        WITH claimed AS (
+            UPDATE media SET scan_claimed_at = CURRENT_TIMESTAMP, scan_attempts = scan_attempts + 1
+            WHERE id = $1 AND scan_status = 'pending'
+              AND (scan_claimed_at IS NULL OR scan_claimed_at < CURRENT_TIMESTAMP - ` + scanRetryDelay("$2") + `)
+            RETURNING id
+        )
+        SELECT ` + mediaColumns + `
+        FROM media m
+        JOIN claimed c ON c.id = m.id`
+
+	media, err := scanMedia(r.db.QueryRowContext(ctx, query, id, lease.Seconds()))
+	if appErr, ok := err.(*appErrors.AppError); ok && appErr.Code == appErrors.CodeNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return media, true, nil
+}
+
+func (r *MediaRepositoryImpl) CompleteMediaScan(ctx context.Context, id int, status string, signature *string, storageKey string) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return execAffectingOne(ctx, r.db, "media not found", `
+        UPDATE media
+        SET scan_status = $2, scan_signature = $3, storage_key = $4,
+            scanned_at = CURRENT_TIMESTAMP, scan_claimed_at = NULL
+        WHERE id = $1 AND scan_status = 'pending'`,
+		id, status, signature, storageKey)
+}
+
+func (r *MediaRepositoryImpl) GetPendingMediaScans(ctx context.Context, lease time.Duration, limit int) ([]int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return r.queryIDs(ctx, `
+        SELECT id FROM media
+        WHERE scan_status = 'pending'
+          AND (scan_claimed_at IS NULL OR scan_claimed_at < CURRENT_TIMESTAMP - `+scanRetryDelay("$1")+`)
+        ORDER BY created_at
+        LIMIT $2`,
+		lease.Seconds(), limit)
+}
+
+func (r *MediaRepositoryImpl) GetPendingMediaOutside(ctx context.Context, keyPrefix string, lease time.Duration, limit int) ([]int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return r.queryIDs(ctx, `
+        SELECT id FROM media
+        WHERE scan_status = 'pending' AND left(storage_key, length($1)) <> $1
+          AND (scan_claimed_at IS NULL OR scan_claimed_at < CURRENT_TIMESTAMP - `+scanRetryDelay("$2")+`)
+        ORDER BY created_at
+        LIMIT $3`,
+		keyPrefix, lease.Seconds(), limit)
+}
+
+func (r *MediaRepositoryImpl) MoveMedia(ctx context.Context, id int, fromKey, toKey string) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return execAffectingOne(ctx, r.db, "media not found", `
+        UPDATE media
+        SET storage_key = $3, scan_claimed_at = NULL, scan_attempts = GREATEST(scan_attempts - 1, 0)
+        WHERE id = $1 AND storage_key = $2 AND scan_status = 'pending'`,
+		id, fromKey, toKey)
+}
+
+func (r *MediaRepositoryImpl) queryIDs(ctx context.Context, query string, args ...interface{}) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// scanRetryDelay is how long a claimed scan waits before it is retried: the lease given by
+// the lease parameter, doubled for every attempt after the first
+func scanRetryDelay(lease string) string {
+	return lease + ` * power(2, GREATEST(scan_attempts - 1, 0)) * INTERVAL '1 second'`
+}
+
+func setMediaTags(ctx context.Context, tx *sql.Tx, mediaID int, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO media_tags (media_id, tag)
+        SELECT $1, unnest($2::text[])
+        ON CONFLICT DO NOTHING`,
+		mediaID, pq.Array(tags))
+	return err
+}
+
+func scanMedia(row rowScanner) (*mediaDTOs.MediaDTO, error) {
+	var media mediaDTOs.MediaDTO
+	var altText, scanSignature sql.NullString
+
+	err := row.Scan(
+		&media.ID,
+		&media.UserID,
+		&media.StorageKey,
+		&media.FileName,
+		&media.ContentType,
+		&media.Size,
+		&altText,
+		&media.ScanStatus,
+		&scanSignature,
+		&media.ScanAttempts,
+		pq.Array(&media.Tags),
+		&media.UsageCount,
+		&media.CreatedAt,
+		&media.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "media not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if altText.Valid {
+		media.AltText = &altText.String
+	}
+	if scanSignature.Valid {
+		media.ScanSignature = &scanSignature.String
+	}
+
+	return &media, nil
+}
@@ -2,10 +2,113 @@ package repo_impl
 
 import (
 	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"app05/pkg/appErrors"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"html"
+	"sort"
+	"strings"
 )
 
+// postColumns selects a post aliased as p together with its visible comment count
+// and its categories, tags and authors aggregated as JSON arrays. Use scanPost to read rows selected with it.
+const postColumns = `
+        p.id, p.user_id, p.title, p.content, p.excerpt, p.status, p.slug,
+        p.view_count, p.published_at, p.created_at, p.updated_at,
+        p.meta_title, p.meta_description, p.canonical_url, p.og_image_url,
+        p.visibility, p.visible_roles, p.locale, p.translation_group_id,
+        (SELECT COUNT(*) FROM comments cm
+         WHERE cm.post_id = p.id AND cm.status = 'approved' AND cm.deleted_at IS NULL),
+        COALESCE((SELECT json_agg(json_build_object('id', c.id, 'name', c.name, 'slug', c.slug) ORDER BY c.name)
+                  FROM post_categories pc JOIN categories c ON c.id = pc.category_id
+                  WHERE pc.post_id = p.id), '[]'),
+        COALESCE((SELECT json_agg(json_build_object('id', t.id, 'name', t.name, 'slug', t.slug) ORDER BY t.name)
+                  FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+                  WHERE pt.post_id = p.id), '[]'),
+        COALESCE((SELECT json_agg(json_build_object('id', u.id, 'name', u.first_name || ' ' || u.last_name, 'role', pa.role)
+                                  ORDER BY pa.role = 'owner' DESC, pa.created_at, u.id)
+                  FROM post_authors pa JOIN users u ON u.id = pa.user_id
+                  WHERE pa.post_id = p.id), '[]')`
+
+// localeCondition keeps posts in the locale bound to localeArg, and posts in the
+// fallback locale bound to fallbackArg whose article has no listed translation in it
+func localeCondition(localeArg, fallbackArg int) string {
+	return fmt.Sprintf(`(p.locale = $%[1]d OR (p.locale = $%[2]d AND NOT EXISTS (
+            SELECT 1 FROM posts tr
+            WHERE tr.translation_group_id = p.translation_group_id AND tr.locale = $%[1]d
+              AND tr.status = 'published' AND tr.visibility <> 'private')))`, localeArg, fallbackArg)
+}
+
+// mapPostError turns unique violations on posts into client errors
+func mapPostError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		if pqErr.Constraint == "posts_translation_locale_key" {
+			return appErrors.New(appErrors.CodeBadRequest, "the article already has a translation in this locale")
+		}
+		return appErrors.New(appErrors.CodeBadRequest, "a post with this slug already exists")
+	}
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPost reads a row selected with postColumns. Any extra destinations are
+// scanned from the columns that follow postColumns.
+func scanPost(row rowScanner, extra ...interface{}) (*postDTOs.PostDTO, error) {
+	var post postDTOs.PostDTO
+	var categoriesJSON, tagsJSON, authorsJSON []byte
+
+	dest := []interface{}{
+		&post.ID,
+		&post.UserID,
+		&post.Title,
+		&post.Content,
+		&post.Excerpt,
+		&post.Status,
+		&post.Slug,
+		&post.ViewCount,
+		&post.PublishedAt,
+		&post.CreatedAt,
+		&post.UpdatedAt,
+		&post.SEO.MetaTitle,
+		&post.SEO.MetaDescription,
+		&post.SEO.CanonicalURL,
+		&post.SEO.OGImageURL,
+		&post.Visibility,
+		pq.Array(&post.VisibleRoles),
+		&post.Locale,
+		&post.TranslationGroupID,
+		&post.CommentCount,
+		&categoriesJSON,
+		&tagsJSON,
+		&authorsJSON,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(categoriesJSON, &post.Categories); err != nil {
+		return nil, fmt.Errorf("error unmarshalling post categories: %w", err)
+	}
+	if err := json.Unmarshal(tagsJSON, &post.Tags); err != nil {
+		return nil, fmt.Errorf("error unmarshalling post tags: %w", err)
+	}
+	if err := json.Unmarshal(authorsJSON, &post.Authors); err != nil {
+		return nil, fmt.Errorf("error unmarshalling post authors: %w", err)
+	}
+
+	return &post, nil
+}
+
 type PostRepository struct {
 	db *sql.DB
 }
@@ -16,39 +119,647 @@ func NewPostRepository(db *sql.DB) *PostRepository {
 	}
 }
 
-// GetAllPosts retrieves all posts from the database
-func (r *PostRepository) GetAllPosts(ctx context.Context) ([]*postDTOs.PostDTO, error) {
+// GetAllPosts retrieves all published posts from the database, plus all unpublished ones
+// when allUnpublished is set or else those written by authorID
+func (r *PostRepository) GetAllPosts(ctx context.Context, allUnpublished bool, authorID uuid.UUID) ([]*postDTOs.PostDTO, error) {
 	posts := []*postDTOs.PostDTO{}
 
-	query := `SELECT id, user_id, title, content, excerpt, status, slug, 
-                   view_count, published_at, created_at, updated_at 
-              FROM posts`
+	query := `SELECT ` + postColumns + ` FROM posts p`
+	args := []interface{}{}
+	if !allUnpublished {
+		query += ` WHERE p.status = 'published' OR EXISTS (
+            SELECT 1 FROM post_authors pa WHERE pa.post_id = p.id AND pa.user_id = $1)`
+		args = append(args, authorID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (r *PostRepository) GetPostByID(ctx context.Context, id int) (*postDTOs.PostDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `SELECT ` + postColumns + ` FROM posts p WHERE p.id = $1`
+
+	post, err := scanPost(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "post not found")
+	}
+	return post, err
+}
+
+func (r *PostRepository) GetPublishedPostBySlug(ctx context.Context, slug string) (*postDTOs.PostDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `SELECT ` + postColumns + ` FROM posts p WHERE p.slug = $1 AND p.status = 'published'`
+
+	post, err := scanPost(r.db.QueryRowContext(ctx, query, slug))
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "post not found")
+	}
+	return post, err
+}
+
+// GetPublishedPosts returns a page of published posts matching the filter, newest first
+func (r *PostRepository) GetPublishedPosts(ctx context.Context, filter postDTOs.PublishedPostFilter) ([]*postDTOs.PostDTO, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	// Private posts are never listed
+	conditions := []string{"p.status = 'published'", "p.visibility <> 'private'"}
+	args := []interface{}{}
+
+	if filter.TagSlug != "" {
+		args = append(args, filter.TagSlug)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+            SELECT 1 FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+            WHERE pt.post_id = p.id AND t.slug = $%d)`, len(args)))
+	}
+	if filter.CategorySlug != "" {
+		args = append(args, filter.CategorySlug)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+            WITH RECURSIVE tree AS (
+                SELECT id FROM categories WHERE slug = $%d
+                UNION ALL
+                SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+            )
+            SELECT 1 FROM post_categories pc
+            WHERE pc.post_id = p.id AND pc.category_id IN (SELECT id FROM tree))`, len(args)))
+	}
+	if filter.AuthorID != nil {
+		args = append(args, *filter.AuthorID)
+		// Co-authored posts are listed for every author
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM post_authors pa WHERE pa.post_id = p.id AND pa.user_id = $%d)", len(args)))
+	}
+	if filter.Locale != "" {
+		args = append(args, filter.Locale, filter.FallbackLocale)
+		conditions = append(conditions, localeCondition(len(args)-1, len(args)))
+	}
+
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM posts p WHERE ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	posts := []*postDTOs.PostDTO{}
+	if total == 0 {
+		return posts, 0, nil
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM posts p
+        WHERE %s
+        ORDER BY p.published_at DESC NULLS LAST, p.id DESC
+        LIMIT $%d OFFSET $%d`, postColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return posts, total, nil
+}
+
+// SearchPosts matches published posts against a websearch-style query, ranks them
+// by the weighted search vector and highlights the matching fragments
+func (r *PostRepository) SearchPosts(ctx context.Context, query, locale, fallbackLocale string, limit, offset int) ([]*postDTOs.PostSearchResultDTO, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	results := []*postDTOs.PostSearchResultDTO{}
+
+	where := `p.status = 'published' AND p.visibility <> 'private'`
+	args := []interface{}{query}
+	if locale != "" {
+		args = append(args, locale, fallbackLocale)
+		where += " AND " + localeCondition(2, 3)
+	}
+
+	var total int
+	countQuery := `
+        SELECT COUNT(*)
+        FROM posts p
+        WHERE ` + where + `
+          AND p.search_vector @@ websearch_to_tsquery('english', $1)`
+
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return results, 0, nil
+	}
+
+	// Rank and paginate first so ts_headline only runs on the rows being returned. Matches
+	// are delimited by the control characters in highlightMarks, which are removed from
+	// the text first, and replaced by <mark> tags once the fragment is escaped.
+	args = append(args, limit, offset)
+	searchQuery := fmt.Sprintf(`
+        WITH q AS (
+            SELECT websearch_to_tsquery('english', $1) AS query
+        ), ranked AS (
+            SELECT p.id, ts_rank_cd(p.search_vector, q.query, 32) AS rank
+            FROM posts p, q
+            WHERE %s
+              AND p.search_vector @@ q.query
+            ORDER BY rank DESC, p.published_at DESC NULLS LAST
+            LIMIT $%d OFFSET $%d
+        )
+        SELECT %s, ranked.rank,
+               ts_headline('english', translate(p.title, chr(2) || chr(3), ''), q.query,
+                           'HighlightAll=true, StartSel=' || chr(2) || ', StopSel=' || chr(3)),
+               ts_headline('english', translate(p.content, chr(2) || chr(3), ''), q.query,
+                           'StartSel=' || chr(2) || ', StopSel=' || chr(3) ||
+                           ', MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" … "')
+        FROM ranked
+        JOIN posts p ON p.id = ranked.id
+        CROSS JOIN q
+        ORDER BY ranked.rank DESC, p.published_at DESC NULLS LAST`, where, len(args)-1, len(args), postColumns)
+
+	rows, err := r.db.QueryContext(ctx, searchQuery, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result postDTOs.PostSearchResultDTO
+		post, err := scanPost(rows, &result.Rank, &result.TitleHighlight, &result.Snippet)
+		if err != nil {
+			return nil, 0, err
+		}
+		result.PostDTO = *post
+		result.TitleHighlight = escapeHighlight(result.TitleHighlight)
+		result.Snippet = escapeHighlight(result.Snippet)
+		results = append(results, &result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// highlightMarks turns the delimiters SearchPosts has ts_headline put around matches into
+// <mark> tags. html.EscapeString leaves the control characters alone.
+var highlightMarks = strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>")
+
+// escapeHighlight escapes a ts_headline fragment of raw post text so it is safe to render
+// as HTML, marking only the matches
+func escapeHighlight(fragment string) string {
+	return highlightMarks.Replace(html.EscapeString(fragment))
+}
+
+func (r *PostRepository) CreatePost(ctx context.Context, post *postDTOs.PostDTO) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	err := dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		return insertPost(ctx, tx, post)
+	})
+
+	return mapPostError(err)
+}
+
+// ImportPost stores an imported post with its tags and categories, all or nothing
+func (r *PostRepository) ImportPost(ctx context.Context, post *postDTOs.PostDTO, tagIDs, categoryIDs []int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	err := dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := insertPost(ctx, tx, post); err != nil {
+			return err
+		}
+
+		if len(tagIDs) > 0 {
+			_, err := tx.ExecContext(ctx, `
+                INSERT INTO post_tags (post_id, tag_id)
+                SELECT $1, unnest($2::int[])
+                ON CONFLICT DO NOTHING`,
+				post.ID, pq.Array(tagIDs))
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(categoryIDs) > 0 {
+			_, err := tx.ExecContext(ctx, `
+                INSERT INTO post_categories (post_id, category_id)
+                SELECT $1, unnest($2::int[])
+                ON CONFLICT DO NOTHING`,
+				post.ID, pq.Array(categoryIDs))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return mapPostError(err)
+}
+
+// insertPost stores a post and makes its user the owner
+func insertPost(ctx context.Context, tx *sql.Tx, post *postDTOs.PostDTO) error {
+	query := `
+        INSERT INTO posts (user_id, title, content, excerpt, status, slug, published_at, locale, translation_group_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE(NULLIF($9, '')::uuid, uuid_generate_v4()))
+        RETURNING id, translation_group_id, view_count, created_at, updated_at`
+
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		post.UserID,
+		post.Title,
+		post.Content,
+		post.Excerpt,
+		post.Status,
+		post.Slug,
+		post.PublishedAt,
+		post.Locale,
+		post.TranslationGroupID,
+	).Scan(&post.ID, &post.TranslationGroupID, &post.ViewCount, &post.CreatedAt, &post.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	// The creator owns the post
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO post_authors (post_id, user_id, role, created_at)
+        VALUES ($1, $2, 'owner', $3)`,
+		post.ID, post.UserID, post.CreatedAt)
+	return err
+}
+
+func (r *PostRepository) UpdatePost(ctx context.Context, post *postDTOs.PostDTO) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        UPDATE posts
+        SET title = $1, content = $2, excerpt = $3, slug = $4
+        WHERE id = $5`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	err := execAffectingOne(ctx, r.db, "post not found", query,
+		post.Title, post.Content, post.Excerpt, post.Slug, post.ID)
+
+	return mapPostError(err)
+}
+
+// UpdatePostTranslation sets a post's locale and moves it into a translation group.
+// An empty groupID moves the post into a new group of its own.
+func (r *PostRepository) UpdatePostTranslation(ctx context.Context, id int, locale, groupID string) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        UPDATE posts
+        SET locale = $1, translation_group_id = COALESCE(NULLIF($2, '')::uuid, uuid_generate_v4())
+        WHERE id = $3`
+
+	return mapPostError(execAffectingOne(ctx, r.db, "post not found", query, locale, groupID, id))
+}
+
+// GetPostTranslations returns the listed posts of a translation group ordered by locale
+func (r *PostRepository) GetPostTranslations(ctx context.Context, groupID string) ([]*postDTOs.PostTranslationDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT id, locale, title, slug
+        FROM posts
+        WHERE translation_group_id = $1 AND status = 'published' AND visibility <> 'private'
+          AND slug IS NOT NULL
+        ORDER BY locale`
+
+	rows, err := r.db.QueryContext(ctx, query, groupID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	translations := []*postDTOs.PostTranslationDTO{}
+	for rows.Next() {
+		var translation postDTOs.PostTranslationDTO
+		if err := rows.Scan(&translation.ID, &translation.Locale, &translation.Title, &translation.Slug); err != nil {
+			return nil, err
+		}
+		translations = append(translations, &translation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+// GetPublishedTranslation returns the published post of a translation group in a locale
+func (r *PostRepository) GetPublishedTranslation(ctx context.Context, groupID, locale string) (*postDTOs.PostDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT ` + postColumns + `
+        FROM posts p
+        WHERE p.translation_group_id = $1 AND p.locale = $2 AND p.status = 'published'`
+
+	post, err := scanPost(r.db.QueryRowContext(ctx, query, groupID, locale))
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "translation not found")
+	}
+	return post, err
+}
+
+func (r *PostRepository) UpdatePostVisibility(ctx context.Context, id int, visibility string, roles []string) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `UPDATE posts SET visibility = $1, visible_roles = $2 WHERE id = $3`
+
+	return execAffectingOne(ctx, r.db, "post not found", query, visibility, pq.Array(roles), id)
+}
+
+func (r *PostRepository) UpdatePostStatus(ctx context.Context, id int, status string) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	// The first publication date is kept when a post is unpublished and published again
+	query := `
+        UPDATE posts
+        SET status = $1,
+            published_at = CASE WHEN $1 = 'published' THEN COALESCE(published_at, CURRENT_TIMESTAMP) ELSE published_at END
+        WHERE id = $2`
+
+	return execAffectingOne(ctx, r.db, "post not found", query, status, id)
+}
+
+func (r *PostRepository) PostSlugExists(ctx context.Context, slug string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM posts WHERE slug = $1)`, slug).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostRepository) UpdatePostSEO(ctx context.Context, id int, seo postDTOs.PostSEODTO) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        UPDATE posts
+        SET meta_title = $1, meta_description = $2, canonical_url = $3, og_image_url = $4
+        WHERE id = $5`
+
+	return execAffectingOne(ctx, r.db, "post not found", query,
+		seo.MetaTitle, seo.MetaDescription, seo.CanonicalURL, seo.OGImageURL, id)
+}
+
+// GetSitemapEntries returns a page of published post slugs in a stable order with the total count
+func (r *PostRepository) GetSitemapEntries(ctx context.Context, limit, offset int) ([]*postDTOs.SitemapEntryDTO, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	const condition = `status = 'published' AND visibility <> 'private' AND slug IS NOT NULL`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM posts WHERE `+condition).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	entries := []*postDTOs.SitemapEntryDTO{}
+	if total == 0 {
+		return entries, 0, nil
+	}
+
+	query := `
+        SELECT slug, updated_at
+        FROM posts
+        WHERE ` + condition + `
+        ORDER BY id
+        LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry postDTOs.SitemapEntryDTO
+		if err := rows.Scan(&entry.Slug, &entry.UpdatedAt); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// IncrementViewCounts applies buffered view increments in batches and adds them to
+// today's daily views. Posts are updated in id order so concurrent flushes cannot
+// deadlock on row locks.
+func (r *PostRepository) IncrementViewCounts(ctx context.Context, counts map[int]int64) error {
+	const batchSize = 500
+
+	ids := make([]int, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	query := `
+        WITH updated AS (
+            UPDATE posts p
+            SET view_count = p.view_count + v.delta
+            FROM unnest($1::int[], $2::bigint[]) AS v(id, delta)
+            WHERE p.id = v.id
+            RETURNING p.id, v.delta
+        )
+        INSERT INTO post_daily_views (post_id, day, views)
+        SELECT id, CURRENT_DATE, delta FROM updated ORDER BY id
+        ON CONFLICT (post_id, day) DO UPDATE SET views = post_daily_views.views + EXCLUDED.views`
+
+	// All batches commit together, so a failed flush can be retried without counting
+	// the batches that went through twice
+	return dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		for start := 0; start < len(ids); start += batchSize {
+			end := start + batchSize
+			if end > len(ids) {
+				end = len(ids)
+			}
+
+			batchIDs := ids[start:end]
+			deltas := make([]int64, len(batchIDs))
+			for i, id := range batchIDs {
+				deltas[i] = counts[id]
+			}
+
+			err := func() error {
+				ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+				defer cancel()
+				_, err := tx.ExecContext(ctx, query, pq.Array(batchIDs), pq.Array(deltas))
+				return err
+			}()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetRelatedPostIDs ranks listed posts against a post by the share of its tags they carry,
+// full-text similarity to its most prominent terms, title trigram similarity and how often
+// they are read together. Each signal is scaled to 0..1 before weighting.
+func (r *PostRepository) GetRelatedPostIDs(ctx context.Context, postID int, coViews map[int]float64, limit int) ([]int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	coViewIDs := make([]int, 0, len(coViews))
+	coViewScores := make([]float64, 0, len(coViews))
+	for id, score := range coViews {
+		coViewIDs = append(coViewIDs, id)
+		coViewScores = append(coViewScores, score)
+	}
+
+	// Title and excerpt terms (weights A and B) come first, then the most frequent content terms.
+	// The lexemes are already stemmed, so the 'simple' configuration keeps them as they are.
+	query := `
+        WITH source AS (
+            SELECT p.id, p.title, p.search_vector,
+                   (SELECT COUNT(*) FROM post_tags pt WHERE pt.post_id = p.id) AS tag_count
+            FROM posts p
+            WHERE p.id = $1
+        ), terms AS (
+            SELECT to_tsquery('simple', string_agg(quote_literal(t.lexeme), ' | ')) AS query
+            FROM (
+                SELECT u.lexeme
+                FROM source s, unnest(s.search_vector) u
+                ORDER BY 'A' = ANY(u.weights) DESC, 'B' = ANY(u.weights) DESC,
+                         cardinality(u.positions) DESC, u.lexeme
+                LIMIT 20
+            ) t
+        ), candidates AS (
+            SELECT p.id, p.published_at,
+                   (SELECT COUNT(*) FROM post_tags pt
+                    JOIN post_tags st ON st.tag_id = pt.tag_id AND st.post_id = s.id
+                    WHERE pt.post_id = p.id) AS shared_tags,
+                   s.tag_count,
+                   COALESCE(ts_rank(p.search_vector, terms.query), 0) AS text_rank,
+                   similarity(p.title, s.title) AS title_similarity,
+                   COALESCE(cv.score, 0) AS co_views
+            FROM posts p
+            CROSS JOIN source s
+            CROSS JOIN terms
+            LEFT JOIN unnest($2::int[], $3::float8[]) AS cv(id, score) ON cv.id = p.id
+            WHERE p.id <> s.id AND p.status = 'published' AND p.visibility <> 'private'
+              AND (EXISTS (SELECT 1 FROM post_tags pt
+                           JOIN post_tags st ON st.tag_id = pt.tag_id AND st.post_id = s.id
+                           WHERE pt.post_id = p.id)
+                   OR p.search_vector @@ terms.query
+                   OR p.title % s.title
+                   OR cv.id IS NOT NULL)
+        )
+        SELECT id
+        FROM candidates
+        ORDER BY 0.35 * shared_tags / GREATEST(tag_count, 1)
+               + 0.30 * COALESCE(text_rank / NULLIF(MAX(text_rank) OVER (), 0), 0)
+               + 0.10 * title_similarity
+               + 0.25 * COALESCE(co_views / NULLIF(MAX(co_views) OVER (), 0), 0) DESC,
+                 published_at DESC NULLS LAST, id DESC
+        LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, postID, pq.Array(coViewIDs), pq.Array(coViewScores), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetListedPostsByIDs returns the listed posts among ids in the order given
+func (r *PostRepository) GetListedPostsByIDs(ctx context.Context, ids []int) ([]*postDTOs.PostDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT ` + postColumns + `
+        FROM unnest($1::int[]) WITH ORDINALITY AS ids(id, ord)
+        JOIN posts p ON p.id = ids.id
+        WHERE p.status = 'published' AND p.visibility <> 'private'
+        ORDER BY ids.ord`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []*postDTOs.PostDTO{}
 	for rows.Next() {
-		var post postDTOs.PostDTO
-		err := rows.Scan(
-			&post.ID,
-			&post.UserID,
-			&post.Title,
-			&post.Content,
-			&post.Excerpt,
-			&post.Status,
-			&post.Slug,
-			&post.ViewCount,
-			&post.PublishedAt,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-		)
+		post, err := scanPost(rows)
 		if err != nil {
 			return nil, err
 		}
-		posts = append(posts, &post)
+		posts = append(posts, post)
 	}
 
 	if err = rows.Err(); err != nil {
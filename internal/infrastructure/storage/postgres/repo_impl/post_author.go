@@ -0,0 +1,119 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"app05/pkg/appErrors"
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type PostAuthorRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewPostAuthorRepository(db *sql.DB) *PostAuthorRepositoryImpl {
+	return &PostAuthorRepositoryImpl{db: db}
+}
+
+func (r *PostAuthorRepositoryImpl) GetPostAuthorRole(ctx context.Context, postID int, userID uuid.UUID) (entities.PostAuthorRole, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var role entities.PostAuthorRole
+	err := r.db.QueryRowContext(ctx, `SELECT role FROM post_authors WHERE post_id = $1 AND user_id = $2`, postID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
+func (r *PostAuthorRepositoryImpl) SetPostAuthor(ctx context.Context, postID int, userID uuid.UUID, role entities.PostAuthorRole) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        INSERT INTO post_authors (post_id, user_id, role)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (post_id, user_id) DO UPDATE SET role = EXCLUDED.role
+        WHERE post_authors.role <> 'owner'`
+
+	changed, err := execChangedRow(ctx, r.db, query, postID, userID, role)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return appErrors.New(appErrors.CodeNotFound, "post or user not found")
+	}
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return appErrors.New(appErrors.CodeBadRequest, "the owner's role cannot be changed")
+	}
+	return nil
+}
+
+func (r *PostAuthorRepositoryImpl) RemovePostAuthor(ctx context.Context, postID int, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `DELETE FROM post_authors WHERE post_id = $1 AND user_id = $2 AND role <> 'owner'`
+
+	return execAffectingOne(ctx, r.db, "co-author not found", query, postID, userID)
+}
+
+func (r *PostAuthorRepositoryImpl) TransferOwnedPosts(ctx context.Context, fromUserID, toUserID uuid.UUID) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var transferred int
+	err := dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		var postIDs []int64
+		err := tx.QueryRowContext(ctx, `
+            SELECT COALESCE(array_agg(post_id), '{}') FROM (
+                SELECT post_id FROM post_authors
+                WHERE user_id = $1 AND role = 'owner'
+                FOR UPDATE
+            ) owned`,
+			fromUserID).Scan(pq.Array(&postIDs))
+		if err != nil {
+			return err
+		}
+		if len(postIDs) == 0 {
+			return nil
+		}
+
+		// The new owner may already be a co-author of some of the posts
+		_, err = tx.ExecContext(ctx, `DELETE FROM post_authors WHERE user_id = $1 AND post_id = ANY($2)`, toUserID, pq.Array(postIDs))
+		if err != nil {
+			return err
+		}
+
+		// Demote before promoting, a post can only have one owner at a time
+		_, err = tx.ExecContext(ctx, `
+            UPDATE post_authors SET role = 'contributor'
+            WHERE user_id = $1 AND post_id = ANY($2)`,
+			fromUserID, pq.Array(postIDs))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO post_authors (post_id, user_id, role)
+            SELECT unnest($2::int[]), $1, 'owner'`,
+			toUserID, pq.Array(postIDs))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE posts SET user_id = $1 WHERE id = ANY($2)`, toUserID, pq.Array(postIDs))
+		if err != nil {
+			return err
+		}
+
+		transferred = len(postIDs)
+		return nil
+	})
+
+	return transferred, err
+}
@@ -0,0 +1,25 @@
+package repo_impl
+
+import "testing"
+
+func TestEscapeHighlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		fragment string
+		want     string
+	}{
+		{name: "plain text", fragment: "nothing to see", want: "nothing to see"},
+		{name: "match", fragment: "a \x02match\x03 here", want: "a <mark>match</mark> here"},
+		{name: "markup in the post", fragment: "<script>alert(1)</script> \x02go\x03", want: "&lt;script&gt;alert(1)&lt;/script&gt; <mark>go</mark>"},
+		{name: "literal mark tags in the post", fragment: "<mark>fake</mark> \x02real\x03", want: "&lt;mark&gt;fake&lt;/mark&gt; <mark>real</mark>"},
+		{name: "unbalanced literal mark tag", fragment: "</mark><mark> \x02real\x03", want: "&lt;/mark&gt;&lt;mark&gt; <mark>real</mark>"},
+		{name: "quotes and ampersands", fragment: `"Tom" & 'Jerry'`, want: "&#34;Tom&#34; &amp; &#39;Jerry&#39;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeHighlight(tt.fragment); got != tt.want {
+				t.Errorf("escapeHighlight(%q) = %q, want %q", tt.fragment, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"app05/pkg/appErrors"
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ProgressRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewProgressRepository(db *sql.DB) *ProgressRepositoryImpl {
+	return &ProgressRepositoryImpl{db: db}
+}
+
+const lessonProgressColumns = `lesson_id, position_seconds, completed_at, last_viewed_at`
+
+func (r *ProgressRepositoryImpl) SaveCheckpoint(ctx context.Context, userID uuid.UUID, lessonID, positionSeconds int) (*entities.LessonProgress, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        INSERT INTO lesson_progress (user_id, lesson_id, position_seconds)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (user_id, lesson_id) DO UPDATE
+        SET position_seconds = EXCLUDED.position_seconds, last_viewed_at = NOW()
+        RETURNING ` + lessonProgressColumns
+
+	progress, err := scanLessonProgress(r.db.QueryRowContext(ctx, query, userID, lessonID, positionSeconds))
+	return progress, mapProgressError(err)
+}
+
+func (r *ProgressRepositoryImpl) CompleteLesson(ctx context.Context, userID uuid.UUID, lessonID int) (*entities.LessonProgress, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        INSERT INTO lesson_progress (user_id, lesson_id, completed_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (user_id, lesson_id) DO UPDATE
+        SET completed_at = COALESCE(lesson_progress.completed_at, NOW()), last_viewed_at = NOW()
+        RETURNING ` + lessonProgressColumns
+
+	progress, err := scanLessonProgress(r.db.QueryRowContext(ctx, query, userID, lessonID))
+	return progress, mapProgressError(err)
+}
+
+func (r *ProgressRepositoryImpl) GetLessonProgress(ctx context.Context, userID uuid.UUID, courseID int) ([]*entities.LessonProgress, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT p.lesson_id, p.position_seconds, p.completed_at, p.last_viewed_at
+        FROM lesson_progress p
+        JOIN lessons l ON l.id = p.lesson_id
+        JOIN course_modules m ON m.id = l.module_id
+        WHERE p.user_id = $1 AND m.course_id = $2
+        ORDER BY p.last_viewed_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := []*entities.LessonProgress{}
+	for rows.Next() {
+		lesson, err := scanLessonProgress(rows)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, lesson)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+func (r *ProgressRepositoryImpl) CompleteCourse(ctx context.Context, courseID int, userID uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	// Courses without required lessons are never completed
+	query := `
+        UPDATE course_enrollments e
+        SET completed_at = NOW()
+        WHERE e.course_id = $1 AND e.user_id = $2 AND e.completed_at IS NULL
+          AND ` + enrollmentProgress + ` = 100`
+
+	result, err := r.db.ExecContext(ctx, query, courseID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func (r *ProgressRepositoryImpl) GetClassProgress(ctx context.Context, courseID int) (*entities.ClassProgress, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	class := &entities.ClassProgress{CourseID: courseID}
+	err := r.db.QueryRowContext(ctx, `
+        SELECT COUNT(*), COUNT(completed_at), COALESCE(ROUND(AVG(progress)), 0)::int
+        FROM (
+            SELECT e.completed_at, `+enrollmentProgress+` AS progress
+            FROM course_enrollments e
+            WHERE e.course_id = $1
+        ) enrolled`,
+		courseID).Scan(&class.Enrolled, &class.Completed, &class.AverageProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Completions of users who are no longer enrolled are not counted
+	query := `
+        SELECT l.id, l.module_id, l.title, l.status, l.is_required, COUNT(e.user_id)
+        FROM lessons l
+        JOIN course_modules m ON m.id = l.module_id
+        LEFT JOIN lesson_progress p ON p.lesson_id = l.id AND p.completed_at IS NOT NULL
+        LEFT JOIN course_enrollments e ON e.course_id = m.course_id AND e.user_id = p.user_id
+        WHERE m.course_id = $1
+        GROUP BY l.id, m.position
+        ORDER BY m.position, l.position`
+
+	rows, err := r.db.QueryContext(ctx, query, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	class.Lessons = []*entities.LessonCompletion{}
+	for rows.Next() {
+		var lesson entities.LessonCompletion
+		err := rows.Scan(
+			&lesson.LessonID,
+			&lesson.ModuleID,
+			&lesson.Title,
+			&lesson.Status,
+			&lesson.IsRequired,
+			&lesson.Completed,
+		)
+		if err != nil {
+			return nil, err
+		}
+		class.Lessons = append(class.Lessons, &lesson)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return class, nil
+}
+
+func scanLessonProgress(row rowScanner) (*entities.LessonProgress, error) {
+	var progress entities.LessonProgress
+	var completedAt, lastViewedAt sql.NullTime
+
+	err := row.Scan(
+		&progress.LessonID,
+		&progress.PositionSeconds,
+		&completedAt,
+		&lastViewedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if completedAt.Valid {
+		progress.CompletedAt = &completedAt.Time
+	}
+	if lastViewedAt.Valid {
+		progress.LastViewedAt = &lastViewedAt.Time
+	}
+
+	return &progress, nil
+}
+
+func mapProgressError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return appErrors.New(appErrors.CodeNotFound, "lesson not found")
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/entities"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ReactionRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewReactionRepository(db *sql.DB) *ReactionRepositoryImpl {
+	return &ReactionRepositoryImpl{db: db}
+}
+
+func (r *ReactionRepositoryImpl) AddReaction(ctx context.Context, postID int, userID uuid.UUID, reaction entities.ReactionType) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        INSERT INTO post_reactions (post_id, user_id, reaction)
+        VALUES ($1, $2, $3)
+        ON CONFLICT DO NOTHING`
+
+	return execChangedRow(ctx, r.db, query, postID, userID, reaction)
+}
+
+func (r *ReactionRepositoryImpl) RemoveReaction(ctx context.Context, postID int, userID uuid.UUID, reaction entities.ReactionType) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `DELETE FROM post_reactions WHERE post_id = $1 AND user_id = $2 AND reaction = $3`
+
+	return execChangedRow(ctx, r.db, query, postID, userID, reaction)
+}
+
+func (r *ReactionRepositoryImpl) GetReactionCounts(ctx context.Context, postIDs []int) (map[int]map[string]int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT post_id, reaction, COUNT(*)
+        FROM post_reactions
+        WHERE post_id = ANY($1)
+        GROUP BY post_id, reaction`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int]map[string]int)
+	for rows.Next() {
+		var postID, count int
+		var reaction string
+		if err := rows.Scan(&postID, &reaction, &count); err != nil {
+			return nil, err
+		}
+		if counts[postID] == nil {
+			counts[postID] = make(map[string]int)
+		}
+		counts[postID][reaction] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *ReactionRepositoryImpl) GetUserReactions(ctx context.Context, postID int, userID uuid.UUID) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT reaction
+        FROM post_reactions
+        WHERE post_id = $1 AND user_id = $2
+        ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reactions := []string{}
+	for rows.Next() {
+		var reaction string
+		if err := rows.Scan(&reaction); err != nil {
+			return nil, err
+		}
+		reactions = append(reactions, reaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reactions, nil
+}
+
+// execChangedRow runs an insert or delete and reports whether it changed a row
+func execChangedRow(ctx context.Context, db *sql.DB, query string, args ...interface{}) (bool, error) {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
@@ -0,0 +1,365 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/dtos/seriesDTOs"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"app05/pkg/appErrors"
+	"context"
+	"database/sql"
+	"github.com/lib/pq"
+)
+
+type SeriesRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewSeriesRepository(db *sql.DB) *SeriesRepositoryImpl {
+	return &SeriesRepositoryImpl{db: db}
+}
+
+// seriesColumns selects a series aliased as s with its published post count
+const seriesColumns = `
+        s.id, s.user_id, s.title, s.slug, s.description,
+        (SELECT COUNT(*) FROM series_posts sp JOIN posts p ON p.id = sp.post_id
+         WHERE sp.series_id = s.id AND p.status = 'published' AND p.visibility <> 'private'),
+        s.created_at, s.updated_at`
+
+func (r *SeriesRepositoryImpl) CreateSeries(ctx context.Context, series *seriesDTOs.SeriesDTO) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        INSERT INTO series (user_id, title, slug, description)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		series.UserID,
+		series.Title,
+		series.Slug,
+		series.Description,
+	).Scan(&series.ID, &series.CreatedAt, &series.UpdatedAt)
+
+	return mapSeriesError(err)
+}
+
+func (r *SeriesRepositoryImpl) UpdateSeries(ctx context.Context, series *seriesDTOs.SeriesDTO) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        UPDATE series
+        SET title = $1, slug = $2, description = $3
+        WHERE id = $4
+        RETURNING updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		series.Title,
+		series.Slug,
+		series.Description,
+		series.ID,
+	).Scan(&series.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return appErrors.New(appErrors.CodeNotFound, "series not found")
+	}
+	return mapSeriesError(err)
+}
+
+func (r *SeriesRepositoryImpl) DeleteSeries(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return execAffectingOne(ctx, r.db, "series not found", `DELETE FROM series WHERE id = $1`, id)
+}
+
+func (r *SeriesRepositoryImpl) GetSeriesByID(ctx context.Context, id int) (*seriesDTOs.SeriesDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `SELECT ` + seriesColumns + ` FROM series s WHERE s.id = $1`
+	return scanSeries(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *SeriesRepositoryImpl) GetSeriesBySlug(ctx context.Context, slug string) (*seriesDTOs.SeriesDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `SELECT ` + seriesColumns + ` FROM series s WHERE s.slug = $1`
+	return scanSeries(r.db.QueryRowContext(ctx, query, slug))
+}
+
+func (r *SeriesRepositoryImpl) GetSeriesByPostID(ctx context.Context, postID int) (*seriesDTOs.SeriesDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT ` + seriesColumns + `
+        FROM series s
+        JOIN series_posts member ON member.series_id = s.id
+        WHERE member.post_id = $1`
+
+	return scanSeries(r.db.QueryRowContext(ctx, query, postID))
+}
+
+func (r *SeriesRepositoryImpl) GetPublishedSeries(ctx context.Context, limit, offset int) ([]*seriesDTOs.SeriesDTO, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	hasPublished := `
+        EXISTS (SELECT 1 FROM series_posts sp JOIN posts p ON p.id = sp.post_id
+                WHERE sp.series_id = s.id AND p.status = 'published' AND p.visibility <> 'private')`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM series s WHERE `+hasPublished).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT ` + seriesColumns + `
+        FROM series s
+        WHERE ` + hasPublished + `
+        ORDER BY s.updated_at DESC, s.id DESC
+        LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	seriesList := []*seriesDTOs.SeriesDTO{}
+	for rows.Next() {
+		series, err := scanSeries(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		seriesList = append(seriesList, series)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return seriesList, total, nil
+}
+
+func (r *SeriesRepositoryImpl) SeriesSlugExists(ctx context.Context, slug string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM series WHERE slug = $1)`, slug).Scan(&exists)
+	return exists, err
+}
+
+func (r *SeriesRepositoryImpl) GetSeriesEntries(ctx context.Context, seriesID int, publishedOnly bool) ([]*seriesDTOs.SeriesEntryDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT p.id, ROW_NUMBER() OVER (ORDER BY sp.position), p.title, p.slug, p.excerpt, p.status, p.published_at
+        FROM series_posts sp
+        JOIN posts p ON p.id = sp.post_id
+        WHERE sp.series_id = $1 AND (NOT $2 OR (p.status = 'published' AND p.visibility <> 'private'))
+        ORDER BY sp.position`
+
+	rows, err := r.db.QueryContext(ctx, query, seriesID, publishedOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*seriesDTOs.SeriesEntryDTO{}
+	for rows.Next() {
+		var entry seriesDTOs.SeriesEntryDTO
+		var slug, excerpt sql.NullString
+		var publishedAt sql.NullTime
+		err := rows.Scan(
+			&entry.PostID,
+			&entry.Position,
+			&entry.Title,
+			&slug,
+			&excerpt,
+			&entry.Status,
+			&publishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if slug.Valid {
+			entry.Slug = &slug.String
+		}
+		if excerpt.Valid {
+			entry.Excerpt = &excerpt.String
+		}
+		if publishedAt.Valid {
+			entry.PublishedAt = &publishedAt.Time
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *SeriesRepositoryImpl) AddSeriesPost(ctx context.Context, seriesID, postID, position int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	err := dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockSeries(ctx, tx, seriesID); err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM series_posts WHERE series_id = $1`, seriesID).Scan(&count); err != nil {
+			return err
+		}
+		if position < 1 || position > count+1 {
+			position = count + 1
+		}
+
+		_, err := tx.ExecContext(ctx, `
+            UPDATE series_posts SET position = position + 1
+            WHERE series_id = $1 AND position >= $2`,
+			seriesID, position)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO series_posts (series_id, post_id, position)
+            VALUES ($1, $2, $3)`,
+			seriesID, postID, position)
+		return err
+	})
+
+	return mapSeriesError(err)
+}
+
+func (r *SeriesRepositoryImpl) RemoveSeriesPost(ctx context.Context, seriesID, postID int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	err := dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockSeries(ctx, tx, seriesID); err != nil {
+			return err
+		}
+
+		var position int
+		err := tx.QueryRowContext(ctx, `
+            DELETE FROM series_posts WHERE series_id = $1 AND post_id = $2
+            RETURNING position`,
+			seriesID, postID).Scan(&position)
+		if err == sql.ErrNoRows {
+			return appErrors.New(appErrors.CodeNotFound, "post is not part of this series")
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            UPDATE series_posts SET position = position - 1
+            WHERE series_id = $1 AND position > $2`,
+			seriesID, position)
+		return err
+	})
+
+	return mapSeriesError(err)
+}
+
+func (r *SeriesRepositoryImpl) ReorderSeriesPosts(ctx context.Context, seriesID int, postIDs []int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	err := dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockSeries(ctx, tx, seriesID); err != nil {
+			return err
+		}
+
+		// The new order must be a permutation of the current members
+		var matches bool
+		err := tx.QueryRowContext(ctx, `
+            SELECT COALESCE(array_agg(post_id ORDER BY post_id), '{}') =
+                   (SELECT COALESCE(array_agg(id ORDER BY id), '{}') FROM unnest($2::int[]) AS id)
+            FROM series_posts WHERE series_id = $1`,
+			seriesID, pq.Array(postIDs)).Scan(&matches)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			return appErrors.New(appErrors.CodeBadRequest, "post_ids must list every post in the series exactly once")
+		}
+
+		// Positions are unique per series, checked when the transaction commits
+		_, err = tx.ExecContext(ctx, `
+            UPDATE series_posts sp SET position = o.position
+            FROM unnest($2::int[]) WITH ORDINALITY AS o(post_id, position)
+            WHERE sp.series_id = $1 AND sp.post_id = o.post_id`,
+			seriesID, pq.Array(postIDs))
+		return err
+	})
+
+	return mapSeriesError(err)
+}
+
+// lockSeries locks a series row so concurrent changes to its order are serialized
+func lockSeries(ctx context.Context, tx *sql.Tx, seriesID int) error {
+	var id int
+	err := tx.QueryRowContext(ctx, `SELECT id FROM series WHERE id = $1 FOR UPDATE`, seriesID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return appErrors.New(appErrors.CodeNotFound, "series not found")
+	}
+	return err
+}
+
+func scanSeries(row rowScanner) (*seriesDTOs.SeriesDTO, error) {
+	var series seriesDTOs.SeriesDTO
+	var description sql.NullString
+
+	err := row.Scan(
+		&series.ID,
+		&series.UserID,
+		&series.Title,
+		&series.Slug,
+		&description,
+		&series.PostCount,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "series not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		series.Description = &description.String
+	}
+
+	return &series, nil
+}
+
+func mapSeriesError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Constraint {
+		case "series_posts_post_id_key":
+			return appErrors.New(appErrors.CodeBadRequest, "post already belongs to a series")
+		case "series_slug_key":
+			return appErrors.New(appErrors.CodeBadRequest, "a series with this slug already exists")
+		}
+		if pqErr.Code == "23503" {
+			return appErrors.New(appErrors.CodeNotFound, "referenced post or series does not exist")
+		}
+	}
+	return err
+}
@@ -0,0 +1,387 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/dtos/taxonomyDTOs"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"app05/pkg/appErrors"
+	"context"
+	"database/sql"
+	"github.com/lib/pq"
+	"strings"
+)
+
+type TaxonomyRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewTaxonomyRepository(db *sql.DB) *TaxonomyRepositoryImpl {
+	return &TaxonomyRepositoryImpl{db: db}
+}
+
+// categoryPostCount counts the published posts attached to the category aliased as c
+const categoryPostCount = `
+        (SELECT COUNT(*) FROM post_categories pc JOIN posts p ON p.id = pc.post_id
+         WHERE pc.category_id = c.id AND p.status = 'published' AND p.visibility <> 'private')`
+
+// tagPostCount counts the published posts attached to the tag aliased as t
+const tagPostCount = `
+        (SELECT COUNT(*) FROM post_tags pt JOIN posts p ON p.id = pt.post_id
+         WHERE pt.tag_id = t.id AND p.status = 'published' AND p.visibility <> 'private')`
+
+func (r *TaxonomyRepositoryImpl) CreateCategory(ctx context.Context, category *taxonomyDTOs.CategoryDTO) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        INSERT INTO categories (parent_id, name, slug, description)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		category.ParentID,
+		category.Name,
+		category.Slug,
+		category.Description,
+	).Scan(&category.ID, &category.CreatedAt, &category.UpdatedAt)
+
+	return mapTaxonomyError(err)
+}
+
+func (r *TaxonomyRepositoryImpl) UpdateCategory(ctx context.Context, category *taxonomyDTOs.CategoryDTO) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        UPDATE categories
+        SET parent_id = $1, name = $2, slug = $3, description = $4
+        WHERE id = $5
+        RETURNING updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		category.ParentID,
+		category.Name,
+		category.Slug,
+		category.Description,
+		category.ID,
+	).Scan(&category.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return appErrors.New(appErrors.CodeNotFound, "category not found")
+	}
+	return mapTaxonomyError(err)
+}
+
+func (r *TaxonomyRepositoryImpl) DeleteCategory(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		var parentID sql.NullInt64
+		err := tx.QueryRowContext(ctx, `SELECT parent_id FROM categories WHERE id = $1 FOR UPDATE`, id).Scan(&parentID)
+		if err == sql.ErrNoRows {
+			return appErrors.New(appErrors.CodeNotFound, "category not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		// Keep the subtree attached to the tree instead of orphaning it at the root
+		_, err = tx.ExecContext(ctx, `UPDATE categories SET parent_id = $1 WHERE parent_id = $2`, parentID, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+		return err
+	})
+}
+
+func (r *TaxonomyRepositoryImpl) GetCategoryByID(ctx context.Context, id int) (*taxonomyDTOs.CategoryDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT c.id, c.parent_id, c.name, c.slug, c.description, ` + categoryPostCount + `,
+               c.created_at, c.updated_at
+        FROM categories c
+        WHERE c.id = $1`
+
+	return scanCategory(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *TaxonomyRepositoryImpl) GetCategoryBySlug(ctx context.Context, slug string) (*taxonomyDTOs.CategoryDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT c.id, c.parent_id, c.name, c.slug, c.description, ` + categoryPostCount + `,
+               c.created_at, c.updated_at
+        FROM categories c
+        WHERE c.slug = $1`
+
+	return scanCategory(r.db.QueryRowContext(ctx, query, slug))
+}
+
+func (r *TaxonomyRepositoryImpl) GetAllCategories(ctx context.Context) ([]*taxonomyDTOs.CategoryDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT c.id, c.parent_id, c.name, c.slug, c.description, ` + categoryPostCount + `,
+               c.created_at, c.updated_at
+        FROM categories c
+        ORDER BY c.name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []*taxonomyDTOs.CategoryDTO{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *TaxonomyRepositoryImpl) CategorySlugExists(ctx context.Context, slug string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM categories WHERE slug = $1)`, slug).Scan(&exists)
+	return exists, err
+}
+
+func (r *TaxonomyRepositoryImpl) IsCategoryDescendant(ctx context.Context, ancestorID, categoryID int) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        WITH RECURSIVE tree AS (
+            SELECT id FROM categories WHERE id = $1
+            UNION ALL
+            SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+        )
+        SELECT EXISTS (SELECT 1 FROM tree WHERE id = $2)`
+
+	var descendant bool
+	err := r.db.QueryRowContext(ctx, query, ancestorID, categoryID).Scan(&descendant)
+	return descendant, err
+}
+
+func (r *TaxonomyRepositoryImpl) CreateTag(ctx context.Context, tag *taxonomyDTOs.TagDTO) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        INSERT INTO tags (name, slug)
+        VALUES ($1, $2)
+        RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, tag.Name, tag.Slug).Scan(&tag.ID, &tag.CreatedAt)
+	return mapTaxonomyError(err)
+}
+
+func (r *TaxonomyRepositoryImpl) GetTagByName(ctx context.Context, name string) (*taxonomyDTOs.TagDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT t.id, t.name, t.slug, ` + tagPostCount + `, t.created_at
+        FROM tags t
+        WHERE lower(t.name) = lower($1)`
+
+	return scanTag(r.db.QueryRowContext(ctx, query, name))
+}
+
+func (r *TaxonomyRepositoryImpl) GetTagBySlug(ctx context.Context, slug string) (*taxonomyDTOs.TagDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT t.id, t.name, t.slug, ` + tagPostCount + `, t.created_at
+        FROM tags t
+        WHERE t.slug = $1`
+
+	return scanTag(r.db.QueryRowContext(ctx, query, slug))
+}
+
+func (r *TaxonomyRepositoryImpl) GetAllTags(ctx context.Context, limit, offset int) ([]*taxonomyDTOs.TagDTO, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM tags`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT t.id, t.name, t.slug, ` + tagPostCount + ` AS post_count, t.created_at
+        FROM tags t
+        ORDER BY post_count DESC, t.name
+        LIMIT $1 OFFSET $2`
+
+	tags, err := r.queryTags(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tags, total, nil
+}
+
+func (r *TaxonomyRepositoryImpl) SearchTagsByPrefix(ctx context.Context, prefix string, limit int) ([]*taxonomyDTOs.TagDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+        SELECT t.id, t.name, t.slug, ` + tagPostCount + ` AS post_count, t.created_at
+        FROM tags t
+        WHERE lower(t.name) LIKE $1
+        ORDER BY post_count DESC, t.name
+        LIMIT $2`
+
+	return r.queryTags(ctx, query, escapeLike(strings.ToLower(prefix))+"%", limit)
+}
+
+func (r *TaxonomyRepositoryImpl) TagSlugExists(ctx context.Context, slug string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tags WHERE slug = $1)`, slug).Scan(&exists)
+	return exists, err
+}
+
+func (r *TaxonomyRepositoryImpl) SetPostCategories(ctx context.Context, postID int, categoryIDs []int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_categories WHERE post_id = $1`, postID); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `
+            INSERT INTO post_categories (post_id, category_id)
+            SELECT $1, unnest($2::int[])
+            ON CONFLICT DO NOTHING`,
+			postID, pq.Array(categoryIDs))
+		return mapTaxonomyError(err)
+	})
+}
+
+func (r *TaxonomyRepositoryImpl) SetPostTags(ctx context.Context, postID int, tagIDs []int) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	return dbUtils.WithTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_tags WHERE post_id = $1`, postID); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `
+            INSERT INTO post_tags (post_id, tag_id)
+            SELECT $1, unnest($2::int[])
+            ON CONFLICT DO NOTHING`,
+			postID, pq.Array(tagIDs))
+		return mapTaxonomyError(err)
+	})
+}
+
+func (r *TaxonomyRepositoryImpl) queryTags(ctx context.Context, query string, args ...interface{}) ([]*taxonomyDTOs.TagDTO, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []*taxonomyDTOs.TagDTO{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func scanCategory(row rowScanner) (*taxonomyDTOs.CategoryDTO, error) {
+	var category taxonomyDTOs.CategoryDTO
+	var parentID sql.NullInt64
+
+	err := row.Scan(
+		&category.ID,
+		&parentID,
+		&category.Name,
+		&category.Slug,
+		&category.Description,
+		&category.PostCount,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "category not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		category.ParentID = &id
+	}
+
+	return &category, nil
+}
+
+func scanTag(row rowScanner) (*taxonomyDTOs.TagDTO, error) {
+	var tag taxonomyDTOs.TagDTO
+
+	err := row.Scan(&tag.ID, &tag.Name, &tag.Slug, &tag.PostCount, &tag.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.New(appErrors.CodeNotFound, "tag not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// mapTaxonomyError converts constraint violations into client errors
+func mapTaxonomyError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case "23505":
+			return appErrors.New(appErrors.CodeBadRequest, "a term with this name or slug already exists")
+		case "23503":
+			return appErrors.New(appErrors.CodeNotFound, "referenced post or category does not exist")
+		}
+	}
+	return err
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,91 @@
+package repo_impl
+
+import (
+	"app05/internal/core/domain/dtos/postDTOs"
+	"app05/internal/infrastructure/storage/postgres/repo_impl/dbUtils"
+	"context"
+	"database/sql"
+	"github.com/lib/pq"
+	"time"
+)
+
+type TrendingRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewTrendingRepository(db *sql.DB) *TrendingRepositoryImpl {
+	return &TrendingRepositoryImpl{db: db}
+}
+
+func (r *TrendingRepositoryImpl) GetTrendingScores(ctx context.Context, since, until time.Time, pendingViews map[int]int64, halfLife time.Duration, weights postDTOs.TrendingWeights) ([]*postDTOs.TrendingScoreDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	pendingIDs := make([]int64, 0, len(pendingViews))
+	pendingCounts := make([]int64, 0, len(pendingViews))
+	for id, count := range pendingViews {
+		pendingIDs = append(pendingIDs, int64(id))
+		pendingCounts = append(pendingCounts, count)
+	}
+
+	// Daily views are dated at midday, or now for today's views before noon
+	query := `
+        WITH activity AS (
+            SELECT post_id, views * $2 AS weight,
+                   LEAST(day + INTERVAL '12 hours', CURRENT_TIMESTAMP) AS at
+            FROM post_daily_views
+            WHERE day >= $1::timestamptz::date
+            UNION ALL
+            SELECT v.post_id, v.views * $2, CURRENT_TIMESTAMP
+            FROM unnest($7::int[], $8::bigint[]) AS v(post_id, views)
+            UNION ALL
+            SELECT post_id, $3, created_at
+            FROM post_reactions
+            WHERE created_at >= $1 AND created_at < $6
+            UNION ALL
+            SELECT post_id, $4, created_at
+            FROM comments
+            WHERE status = 'approved' AND deleted_at IS NULL AND created_at >= $1 AND created_at < $6
+        )
+        SELECT p.id,
+               SUM(a.weight * power(0.5, EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - a.at) / $5)),
+               COALESCE((SELECT array_agg(pc.category_id) FROM post_categories pc WHERE pc.post_id = p.id), '{}')
+        FROM activity a
+        JOIN posts p ON p.id = a.post_id
+        WHERE p.status = 'published' AND p.visibility <> 'private'
+        GROUP BY p.id`
+
+	rows, err := r.db.QueryContext(ctx, query, since, weights.View, weights.Reaction, weights.Comment, halfLife.Seconds(),
+		until, pq.Array(pendingIDs), pq.Array(pendingCounts))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := []*postDTOs.TrendingScoreDTO{}
+	for rows.Next() {
+		var score postDTOs.TrendingScoreDTO
+		var categoryIDs []int64
+		if err := rows.Scan(&score.PostID, &score.Score, pq.Array(&categoryIDs)); err != nil {
+			return nil, err
+		}
+		for _, id := range categoryIDs {
+			score.CategoryIDs = append(score.CategoryIDs, int(id))
+		}
+		scores = append(scores, &score)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+func (r *TrendingRepositoryImpl) PruneDailyViews(ctx context.Context, before time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbUtils.QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `DELETE FROM post_daily_views WHERE day < $1::timestamptz::date`, before)
+	return err
+}
@@ -250,7 +250,7 @@ func (r *UserRepositoryImpl) UpdateProfilePicture(ctx context.Context, userID uu
 	defer cancel()
 	query := `
         UPDATE users 
-        SET profile_picture_url = $1,
+        SET profile_picture_url = NULLIF($1, ''),
             updated_at = CURRENT_TIMESTAMP
         WHERE id = $2`
 
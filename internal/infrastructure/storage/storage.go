@@ -7,15 +7,39 @@ import (
 )
 
 type Storage struct {
-	User    repositories.UserRepository
-	Session repositories.SessionRepository
-	Post    repositories.PostRepository
+	User       repositories.UserRepository
+	Session    repositories.SessionRepository
+	Post       repositories.PostRepository
+	Taxonomy   repositories.TaxonomyRepository
+	Comment    repositories.CommentRepository
+	Reaction   repositories.ReactionRepository
+	Bookmark   repositories.BookmarkRepository
+	Series     repositories.SeriesRepository
+	Author     repositories.PostAuthorRepository
+	Trending   repositories.TrendingRepository
+	Media      repositories.MediaRepository
+	Variant    repositories.ImageVariantRepository
+	Course     repositories.CourseRepository
+	Enrollment repositories.EnrollmentRepository
+	Progress   repositories.ProgressRepository
 }
 
 func NewStorage(db *sql.DB) Storage {
 	return Storage{
-		User:    repo_impl.NewUserRepository(db),
-		Session: repo_impl.NewSessionRepository(db),
-		Post:    repo_impl.NewPostRepository(db),
+		User:       repo_impl.NewUserRepository(db),
+		Session:    repo_impl.NewSessionRepository(db),
+		Post:       repo_impl.NewPostRepository(db),
+		Taxonomy:   repo_impl.NewTaxonomyRepository(db),
+		Comment:    repo_impl.NewCommentRepository(db),
+		Reaction:   repo_impl.NewReactionRepository(db),
+		Bookmark:   repo_impl.NewBookmarkRepository(db),
+		Series:     repo_impl.NewSeriesRepository(db),
+		Author:     repo_impl.NewPostAuthorRepository(db),
+		Trending:   repo_impl.NewTrendingRepository(db),
+		Media:      repo_impl.NewMediaRepository(db),
+		Variant:    repo_impl.NewImageVariantRepository(db),
+		Course:     repo_impl.NewCourseRepository(db),
+		Enrollment: repo_impl.NewEnrollmentRepository(db),
+		Progress:   repo_impl.NewProgressRepository(db),
 	}
 }
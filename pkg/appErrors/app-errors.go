@@ -57,6 +57,41 @@ var (
 		Message:  "Access denied",
 		Severity: SeverityMedium,
 	}
+
+	CodeConflict = ErrorCode{
+		Status:   http.StatusConflict,
+		Code:     http.StatusText(http.StatusConflict),
+		Message:  "Resource is in use",
+		Severity: SeverityLow,
+	}
+
+	CodePreconditionFailed = ErrorCode{
+		Status:   http.StatusPreconditionFailed,
+		Code:     http.StatusText(http.StatusPreconditionFailed),
+		Message:  "Precondition failed",
+		Severity: SeverityLow,
+	}
+
+	CodeRequestEntityTooLarge = ErrorCode{
+		Status:   http.StatusRequestEntityTooLarge,
+		Code:     http.StatusText(http.StatusRequestEntityTooLarge),
+		Message:  "Request entity too large",
+		Severity: SeverityLow,
+	}
+
+	CodeUnsupportedMediaType = ErrorCode{
+		Status:   http.StatusUnsupportedMediaType,
+		Code:     http.StatusText(http.StatusUnsupportedMediaType),
+		Message:  "Unsupported media type",
+		Severity: SeverityLow,
+	}
+
+	CodeTooManyRequests = ErrorCode{
+		Status:   http.StatusTooManyRequests,
+		Code:     http.StatusText(http.StatusTooManyRequests),
+		Message:  "Too many requests",
+		Severity: SeverityLow,
+	}
 )
 
 type AppError struct {
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultPage    = 1
+	DefaultPerPage = 10
+	MaxPerPage     = 100
+)
+
+// ParsePagination reads the page and per_page query parameters, falling back to
+// defaults for missing or invalid values and capping per_page at MaxPerPage
+func ParsePagination(r *http.Request) (page, perPage int) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = DefaultPage
+	}
+
+	perPage, err = strconv.Atoi(r.URL.Query().Get("per_page"))
+	if err != nil || perPage < 1 {
+		perPage = DefaultPerPage
+	}
+	if perPage > MaxPerPage {
+		perPage = MaxPerPage
+	}
+
+	return page, perPage
+}
+
+// Offset returns the number of rows to skip for the given page
+func Offset(page, perPage int) int {
+	return (page - 1) * perPage
+}